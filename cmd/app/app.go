@@ -13,6 +13,7 @@ import (
 	"goapptemp/pkg/apmtracer"
 	"goapptemp/pkg/bundb"
 	"goapptemp/pkg/logger"
+	"goapptemp/pkg/metrics"
 	"os"
 	"os/signal"
 	"sync"
@@ -72,8 +73,15 @@ func (a *App) Run() error {
 		return fmt.Errorf("failed to setup repository: %w", err)
 	}
 
+	if err := metrics.RegisterDBStats(repo.MySQL().DB().DB, "mysql"); err != nil {
+		return fmt.Errorf("failed to register database metrics: %w", err)
+	}
+
 	// Initialize pubsub
 	var publisher pubsub.Publisher
+
+	eventPublishers := make(map[string]pubsub.Publisher)
+
 	if a.config.App.UsePubsub {
 		a.pubsub, err = pubsubClient.NewPubsub(ctx, a.config.Pubsub.ProjectID, a.config.Pubsub.CredFile)
 		if err != nil {
@@ -84,6 +92,28 @@ func (a *App) Run() error {
 		if err != nil {
 			return fmt.Errorf("failed to setup pubsub publisher: %w", err)
 		}
+
+		eventTopicIDs := []string{a.config.Pubsub.EventTopicID}
+		for _, topicID := range a.config.Pubsub.EventTopicRoutes {
+			eventTopicIDs = append(eventTopicIDs, topicID)
+		}
+
+		for _, topicID := range eventTopicIDs {
+			if _, exists := eventPublishers[topicID]; exists || topicID == "" {
+				continue
+			}
+
+			if topicID == a.config.Pubsub.TopicID {
+				eventPublishers[topicID] = publisher
+
+				continue
+			}
+
+			eventPublishers[topicID], err = pubsub.NewPublisher(a.logger, a.pubsub, topicID)
+			if err != nil {
+				return fmt.Errorf("failed to setup pubsub event publisher for topic %s: %w", topicID, err)
+			}
+		}
 	}
 
 	// Initialize token
@@ -98,15 +128,63 @@ func (a *App) Run() error {
 	}
 
 	// Initialize service
-	service, err := service.NewService(a.config, repo, a.logger, token, publisher)
+	service, err := service.NewService(a.config, repo, a.logger, token, publisher, eventPublishers)
 	if err != nil {
 		return fmt.Errorf("failed to setup service: %w", err)
 	}
 
+	if err := service.Permission().Sync(ctx); err != nil {
+		return fmt.Errorf("failed to sync permission catalog: %w", err)
+	}
+
+	if err := repo.MySQL().StoreProcedure().LoadForeignKeys(ctx); err != nil {
+		return fmt.Errorf("failed to load foreign key graph: %w", err)
+	}
+
+	wg.Go(func() {
+		service.Scheduler().Start(ctx)
+	})
+
+	wg.Go(func() {
+		service.WebhookDeliveryWorker().Start(ctx)
+	})
+
+	// The task queue drains itself once ctx is cancelled; wg.Wait below waits for it.
 	wg.Go(func() {
-		service.StaleTaskDetector().Start(ctx)
+		service.TaskQueue().Start(ctx)
 	})
 
+	// Initialize and start pubsub subscriber
+	if a.config.App.UsePubsub && a.config.Pubsub.SubscriptionID != "" {
+		opts := []pubsub.SubscriberOption{
+			pubsub.WithMaxDeliveryAttempts(a.config.Pubsub.MaxDeliveryAttempts),
+			pubsub.WithMaxOutstandingMessages(a.config.Pubsub.MaxOutstandingMessages),
+			pubsub.WithNumGoroutines(a.config.Pubsub.NumGoroutines),
+		}
+
+		if a.config.Pubsub.DeadLetterTopicID != "" {
+			deadLetter, err := pubsub.NewPublisher(a.logger, a.pubsub, a.config.Pubsub.DeadLetterTopicID)
+			if err != nil {
+				return fmt.Errorf("failed to setup pubsub dead-letter publisher: %w", err)
+			}
+
+			opts = append(opts, pubsub.WithDeadLetter(deadLetter))
+		}
+
+		subscriber, err := pubsub.NewSubscriber(a.logger, a.pubsub, a.config.Pubsub.SubscriptionID, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to setup pubsub subscriber: %w", err)
+		}
+
+		service.Consumer().Register(subscriber)
+
+		wg.Go(func() {
+			if err := subscriber.Start(ctx); err != nil {
+				a.logger.Error().Err(err).Msg("PubSub subscriber stopped unexpectedly")
+			}
+		})
+	}
+
 	// Initialize and start REST server
 	a.restServer, err = rest.NewEchoServer(a.config, a.logger, token, service, repo)
 	if err != nil {
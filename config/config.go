@@ -8,16 +8,25 @@ import (
 )
 
 type Config struct {
-	App       *AppConfig
-	Tracer    *TracerConfig
-	HTTP      *HTTPConfig
-	MySQL     *DatabaseConfig
-	Token     *TokenConfig
-	Pubsub    *PubsubConfig
-	Drive     *DriveConfig
-	StaleTask *StaleTaskConfig
-	Redis     *RedisConfig
-	Gmail     *GmailConfig
+	App        *AppConfig
+	Tracer     *TracerConfig
+	HTTP       *HTTPConfig
+	MySQL      *DatabaseConfig
+	Token      *TokenConfig
+	Pubsub     *PubsubConfig
+	Drive      *DriveConfig
+	StaleTask  *StaleTaskConfig
+	Redis      *RedisConfig
+	Gmail      *GmailConfig
+	Email      *EmailConfig
+	Webhook    *WebhookConfig
+	TaskQueue  *TaskQueueConfig
+	BruteForce *BruteForceConfig
+	RateLimit  *RateLimitConfig
+	OIDC       *OIDCConfig
+	Password   *PasswordPolicyConfig
+	Invitation *InvitationConfig
+	Policy     *PolicyConfig
 }
 
 type AppConfig struct {
@@ -62,6 +71,9 @@ type HTTPConfig struct {
 	BasePath           string
 	DomainName         string
 	EnableMigrationAPI bool
+	// EnableMetrics serves Prometheus metrics on /metrics. The endpoint is unauthenticated, so it
+	// should only be reachable from the monitoring network.
+	EnableMetrics bool
 }
 
 type TokenConfig struct {
@@ -69,12 +81,21 @@ type TokenConfig struct {
 	AccessTokenDuration  int // in minutes
 	RefreshSecretKey     string
 	RefreshTokenDuration int // in minutes
+	// ImpersonationTokenDuration bounds admin impersonation sessions, which cannot be refreshed.
+	ImpersonationTokenDuration int // in minutes
 }
 
 type PubsubConfig struct {
-	ProjectID string
-	TopicID   string
-	CredFile  string
+	ProjectID              string
+	TopicID                string
+	CredFile               string
+	SubscriptionID         string
+	DeadLetterTopicID      string
+	MaxDeliveryAttempts    int
+	MaxOutstandingMessages int
+	NumGoroutines          int
+	EventTopicID           string
+	EventTopicRoutes       map[string]string
 }
 
 type DriveConfig struct {
@@ -82,8 +103,98 @@ type DriveConfig struct {
 }
 
 type GmailConfig struct {
-	CredFile string
-	Sender   string
+	CredFile  string
+	TokenFile string
+	Sender    string
+}
+
+type EmailConfig struct {
+	Driver        string // smtp, gmail or file
+	From          string
+	FromName      string
+	ReplyTo       string
+	DefaultLocale string
+	SMTPHost      string
+	SMTPPort      int
+	SMTPUsername  string
+	SMTPPassword  string
+	SMTPSecurity  string // starttls, tls or none
+	SMTPTimeout   int    // in seconds
+	FileDir       string
+}
+
+type WebhookConfig struct {
+	PollInterval     int // in seconds
+	BatchSize        int
+	Concurrency      int
+	Timeout          int // in seconds
+	MaxAttempts      int
+	BackoffBase      int // in seconds
+	BackoffMax       int // in seconds
+	FailureThreshold int
+}
+
+type TaskQueueConfig struct {
+	Name              string
+	Workers           int
+	MaxAttempts       int
+	VisibilityTimeout int // in seconds
+	PollInterval      int // in milliseconds
+	BackoffBase       int // in seconds
+	BackoffMax        int // in seconds
+	DrainTimeout      int // in seconds
+	DeadLetterSize    int
+}
+
+type BruteForceConfig struct {
+	UserMaxAttempts int
+	UserWindow      int // in seconds
+	UserLockout     int // in seconds
+	IPMaxAttempts   int
+	IPWindow        int // in seconds
+	IPBackoffBase   int // in seconds
+	IPBackoffMax    int // in seconds
+	IPLevelReset    int // in seconds
+	IPAllowlist     []string
+}
+
+type RateLimitConfig struct {
+	Disabled bool
+}
+
+type OIDCConfig struct {
+	StateTTL  int // in seconds
+	Providers []*OIDCProviderConfig
+}
+
+// OIDCProviderConfig is read from OIDC_<NAME>_* keys for every name listed in OIDC_PROVIDERS.
+type OIDCProviderConfig struct {
+	Name          string
+	DisplayName   string
+	IssuerURL     string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	Scopes        []string
+	CompanyID     uint
+	DefaultRoleID uint
+	AutoProvision bool
+}
+
+type PasswordPolicyConfig struct {
+	MinLength        int
+	MinClasses       int // out of lowercase, uppercase, digits and symbols
+	MaxAgeDays       int // 0 disables expiry
+	HistorySize      int // previous passwords that cannot be reused
+	BreachedListFile string
+}
+
+type InvitationConfig struct {
+	TokenTTL int // in seconds
+}
+
+type PolicyConfig struct {
+	File string // YAML attribute-based access policies, loaded on top of the embedded defaults
 }
 
 type StaleTaskConfig struct {
@@ -130,6 +241,7 @@ func LoadConfig(envPath string) (*Config, error) {
 			BasePath:           viper.GetString("HTTP_BASE_PATH"),
 			DomainName:         viper.GetString("HTTP_DOMAIN_NAME"),
 			EnableMigrationAPI: viper.GetBool("HTTP_ENABLE_MIGRATION_API"),
+			EnableMetrics:      viper.GetBool("HTTP_ENABLE_METRICS"),
 		},
 		MySQL: &DatabaseConfig{
 			DSN:                viper.GetString("MYSQL_DSN"),
@@ -148,15 +260,23 @@ func LoadConfig(envPath string) (*Config, error) {
 			DB:       viper.GetInt("REDIS_DB"),
 		},
 		Token: &TokenConfig{
-			AccessSecretKey:      viper.GetString("ACCESS_TOKEN_SECRET_KEY"),
-			AccessTokenDuration:  viper.GetInt("ACCESS_TOKEN_DURATION"),
-			RefreshSecretKey:     viper.GetString("REFRESH_TOKEN_SECRET_KEY"),
-			RefreshTokenDuration: viper.GetInt("REFRESH_TOKEN_DURATION"),
+			AccessSecretKey:            viper.GetString("ACCESS_TOKEN_SECRET_KEY"),
+			AccessTokenDuration:        viper.GetInt("ACCESS_TOKEN_DURATION"),
+			RefreshSecretKey:           viper.GetString("REFRESH_TOKEN_SECRET_KEY"),
+			RefreshTokenDuration:       viper.GetInt("REFRESH_TOKEN_DURATION"),
+			ImpersonationTokenDuration: viper.GetInt("IMPERSONATION_TOKEN_DURATION"),
 		},
 		Pubsub: &PubsubConfig{
-			ProjectID: viper.GetString("PUBSUB_PROJECT_ID"),
-			TopicID:   viper.GetString("PUBSUB_TOPIC_ID"),
-			CredFile:  viper.GetString("PUBSUB_CRED_FILE"),
+			ProjectID:              viper.GetString("PUBSUB_PROJECT_ID"),
+			TopicID:                viper.GetString("PUBSUB_TOPIC_ID"),
+			CredFile:               viper.GetString("PUBSUB_CRED_FILE"),
+			SubscriptionID:         viper.GetString("PUBSUB_SUBSCRIPTION_ID"),
+			DeadLetterTopicID:      viper.GetString("PUBSUB_DEAD_LETTER_TOPIC_ID"),
+			MaxDeliveryAttempts:    viper.GetInt("PUBSUB_MAX_DELIVERY_ATTEMPTS"),
+			MaxOutstandingMessages: viper.GetInt("PUBSUB_MAX_OUTSTANDING_MESSAGES"),
+			NumGoroutines:          viper.GetInt("PUBSUB_NUM_GOROUTINES"),
+			EventTopicID:           viper.GetString("PUBSUB_EVENT_TOPIC_ID"),
+			EventTopicRoutes:       parseKeyValueList(viper.GetString("PUBSUB_EVENT_TOPIC_ROUTES")),
 		},
 		Drive: &DriveConfig{
 			IconFolderID: viper.GetString("DRIVE_ICON_FOLDER_ID"),
@@ -166,10 +286,129 @@ func LoadConfig(envPath string) (*Config, error) {
 			CheckInterval: viper.GetInt("STALE_TASK_CHECK_INTERVAL"),
 		},
 		Gmail: &GmailConfig{
-			CredFile: viper.GetString("GMAIL_CRED_FILE"),
-			Sender:   viper.GetString("GMAIL_SENDER"),
+			CredFile:  viper.GetString("GMAIL_CRED_FILE"),
+			TokenFile: viper.GetString("GMAIL_TOKEN_FILE"),
+			Sender:    viper.GetString("GMAIL_SENDER"),
+		},
+		Email: &EmailConfig{
+			Driver:        viper.GetString("EMAIL_DRIVER"),
+			From:          viper.GetString("EMAIL_FROM"),
+			FromName:      viper.GetString("EMAIL_FROM_NAME"),
+			ReplyTo:       viper.GetString("EMAIL_REPLY_TO"),
+			DefaultLocale: viper.GetString("EMAIL_DEFAULT_LOCALE"),
+			SMTPHost:      viper.GetString("EMAIL_SMTP_HOST"),
+			SMTPPort:      viper.GetInt("EMAIL_SMTP_PORT"),
+			SMTPUsername:  viper.GetString("EMAIL_SMTP_USERNAME"),
+			SMTPPassword:  viper.GetString("EMAIL_SMTP_PASSWORD"),
+			SMTPSecurity:  viper.GetString("EMAIL_SMTP_SECURITY"),
+			SMTPTimeout:   viper.GetInt("EMAIL_SMTP_TIMEOUT"),
+			FileDir:       viper.GetString("EMAIL_FILE_DIR"),
+		},
+		Webhook: &WebhookConfig{
+			PollInterval:     viper.GetInt("WEBHOOK_POLL_INTERVAL"),
+			BatchSize:        viper.GetInt("WEBHOOK_BATCH_SIZE"),
+			Concurrency:      viper.GetInt("WEBHOOK_CONCURRENCY"),
+			Timeout:          viper.GetInt("WEBHOOK_TIMEOUT"),
+			MaxAttempts:      viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			BackoffBase:      viper.GetInt("WEBHOOK_BACKOFF_BASE"),
+			BackoffMax:       viper.GetInt("WEBHOOK_BACKOFF_MAX"),
+			FailureThreshold: viper.GetInt("WEBHOOK_FAILURE_THRESHOLD"),
+		},
+		TaskQueue: &TaskQueueConfig{
+			Name:              viper.GetString("TASK_QUEUE_NAME"),
+			Workers:           viper.GetInt("TASK_QUEUE_WORKERS"),
+			MaxAttempts:       viper.GetInt("TASK_QUEUE_MAX_ATTEMPTS"),
+			VisibilityTimeout: viper.GetInt("TASK_QUEUE_VISIBILITY_TIMEOUT"),
+			PollInterval:      viper.GetInt("TASK_QUEUE_POLL_INTERVAL"),
+			BackoffBase:       viper.GetInt("TASK_QUEUE_BACKOFF_BASE"),
+			BackoffMax:        viper.GetInt("TASK_QUEUE_BACKOFF_MAX"),
+			DrainTimeout:      viper.GetInt("TASK_QUEUE_DRAIN_TIMEOUT"),
+			DeadLetterSize:    viper.GetInt("TASK_QUEUE_DEAD_LETTER_SIZE"),
+		},
+		BruteForce: &BruteForceConfig{
+			UserMaxAttempts: viper.GetInt("BRUTE_FORCE_USER_MAX_ATTEMPTS"),
+			UserWindow:      viper.GetInt("BRUTE_FORCE_USER_WINDOW"),
+			UserLockout:     viper.GetInt("BRUTE_FORCE_USER_LOCKOUT"),
+			IPMaxAttempts:   viper.GetInt("BRUTE_FORCE_IP_MAX_ATTEMPTS"),
+			IPWindow:        viper.GetInt("BRUTE_FORCE_IP_WINDOW"),
+			IPBackoffBase:   viper.GetInt("BRUTE_FORCE_IP_BACKOFF_BASE"),
+			IPBackoffMax:    viper.GetInt("BRUTE_FORCE_IP_BACKOFF_MAX"),
+			IPLevelReset:    viper.GetInt("BRUTE_FORCE_IP_LEVEL_RESET"),
+			IPAllowlist:     parseList(viper.GetString("BRUTE_FORCE_IP_ALLOWLIST")),
+		},
+		RateLimit: &RateLimitConfig{
+			Disabled: viper.GetBool("RATE_LIMIT_DISABLED"),
+		},
+		OIDC: &OIDCConfig{
+			StateTTL:  viper.GetInt("OIDC_STATE_TTL"),
+			Providers: loadOIDCProviders(parseList(viper.GetString("OIDC_PROVIDERS"))),
+		},
+		Password: &PasswordPolicyConfig{
+			MinLength:        viper.GetInt("PASSWORD_MIN_LENGTH"),
+			MinClasses:       viper.GetInt("PASSWORD_MIN_CLASSES"),
+			MaxAgeDays:       viper.GetInt("PASSWORD_MAX_AGE_DAYS"),
+			HistorySize:      viper.GetInt("PASSWORD_HISTORY_SIZE"),
+			BreachedListFile: viper.GetString("PASSWORD_BREACHED_LIST_FILE"),
+		},
+		Invitation: &InvitationConfig{
+			TokenTTL: viper.GetInt("INVITATION_TOKEN_TTL"),
+		},
+		Policy: &PolicyConfig{
+			File: viper.GetString("POLICY_FILE"),
 		},
 	}
 
 	return config, nil
 }
+
+func loadOIDCProviders(names []string) []*OIDCProviderConfig {
+	providers := make([]*OIDCProviderConfig, 0, len(names))
+
+	for _, name := range names {
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		providers = append(providers, &OIDCProviderConfig{
+			Name:          strings.ToLower(name),
+			DisplayName:   viper.GetString(prefix + "DISPLAY_NAME"),
+			IssuerURL:     viper.GetString(prefix + "ISSUER_URL"),
+			ClientID:      viper.GetString(prefix + "CLIENT_ID"),
+			ClientSecret:  viper.GetString(prefix + "CLIENT_SECRET"),
+			RedirectURL:   viper.GetString(prefix + "REDIRECT_URL"),
+			Scopes:        parseList(viper.GetString(prefix + "SCOPES")),
+			CompanyID:     viper.GetUint(prefix + "COMPANY_ID"),
+			DefaultRoleID: viper.GetUint(prefix + "DEFAULT_ROLE_ID"),
+			AutoProvision: viper.GetBool(prefix + "AUTO_PROVISION"),
+		})
+	}
+
+	return providers
+}
+
+// parseList splits a comma separated value, dropping empty items.
+func parseList(raw string) []string {
+	var result []string
+
+	for item := range strings.SplitSeq(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// parseKeyValueList parses "key=value,key=value" into a map, skipping malformed entries.
+func parseKeyValueList(raw string) map[string]string {
+	result := make(map[string]string)
+
+	for item := range strings.SplitSeq(raw, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || key == "" || value == "" {
+			continue
+		}
+
+		result[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return result
+}
@@ -42,12 +42,15 @@ const (
 	CtxKeyTraceID         string     = "trace_id"
 	CtxKeyLoggerStartTime contextKey = "redis_logger_start_time"
 	CtxKeyRequestIP       contextKey = "request_ip"
+	CtxKeyAPIKey          contextKey = "api_key"
+	CtxKeyImpersonator    contextKey = "impersonator"
 )
 
 const (
 	TokenType          string = "Bearer"
 	TokenMinSecretSize int    = 32
 	TokenIssuer        string = "goapptemp-auth"
+	HeaderAPIKey       string = "X-API-Key"
 )
 
 const (
@@ -58,17 +61,27 @@ const (
 const (
 	SoftDeleteColumnName string = "deleted_at"
 	KeyColumnName        string = "key"
-	ParentSchema         string = "goapptemp"
+	// DependencySampleSize caps the referencing IDs reported per table when explaining why a record
+	// cannot be deleted.
+	DependencySampleSize int = 5
 )
 
 const (
 	ClientModelType string = "client"
 )
 
+const (
+	PubsubCommandPubImage   string = "pub image"
+	PubsubCommandUpdateIcon string = "update icon"
+)
+
+// Brute-force protection defaults, used when the matching BRUTE_FORCE_* setting is not configured.
 const (
 	IpRateLimitAttempts     int           = 50
 	IpRateLimitWindow       time.Duration = 10 * time.Minute
 	IpBackoffBaseSeconds    int           = 60
+	IpBackoffMaxSeconds     int           = 24 * 60 * 60
+	IpBlockLevelReset       time.Duration = 7 * 24 * time.Hour
 	UserFailedAttemptsLimit int           = 10
 	UserFailedWindow        time.Duration = 15 * time.Minute
 	UserLockoutDuration     time.Duration = 30 * time.Minute
@@ -79,5 +92,13 @@ const (
 )
 
 const (
-	MinPasswordLength = 8
+	MinPasswordLength   = 8
+	MinPasswordClasses  = 4
+	PasswordHistorySize = 5
 )
+
+// MaxRoleDepth caps how many roles a parent chain may hold, including the role itself.
+const MaxRoleDepth = 5
+
+// BulkMaxIDs caps how many records a single bulk request may touch.
+const BulkMaxIDs = 100
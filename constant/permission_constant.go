@@ -9,6 +9,10 @@ var PermissionCodes = map[string]string{
 	"COMPANY.READ":           "COMPANY.READ",
 	"COMPANY.UPDATE":         "COMPANY.UPDATE",
 	"COMPANY.DELETE":         "COMPANY.DELETE",
+	"FEATURE.CREATE":         "FEATURE.CREATE",
+	"FEATURE.READ":           "FEATURE.READ",
+	"FEATURE.UPDATE":         "FEATURE.UPDATE",
+	"FEATURE.DELETE":         "FEATURE.DELETE",
 	"GROUP.CREATE":           "GROUP.CREATE",
 	"GROUP.READ":             "GROUP.READ",
 	"GROUP.UPDATE":           "GROUP.UPDATE",
@@ -21,6 +25,14 @@ var PermissionCodes = map[string]string{
 	"PRINCIPLE.READ":         "PRINCIPLE.READ",
 	"PRINCIPLE.UPDATE":       "PRINCIPLE.UPDATE",
 	"PRINCIPLE.DELETE":       "PRINCIPLE.DELETE",
+	"PERMISSION.CREATE":      "PERMISSION.CREATE",
+	"PERMISSION.READ":        "PERMISSION.READ",
+	"PERMISSION.UPDATE":      "PERMISSION.UPDATE",
+	"PERMISSION.DELETE":      "PERMISSION.DELETE",
+	"PROFILE.CREATE":         "PROFILE.CREATE",
+	"PROFILE.READ":           "PROFILE.READ",
+	"PROFILE.UPDATE":         "PROFILE.UPDATE",
+	"PROFILE.DELETE":         "PROFILE.DELETE",
 	"ROLE.CREATE":            "ROLE.CREATE",
 	"ROLE.READ":              "ROLE.READ",
 	"ROLE.UPDATE":            "ROLE.UPDATE",
@@ -41,6 +53,7 @@ var PermissionCodes = map[string]string{
 	"USER.READ":              "USER.READ",
 	"USER.UPDATE":            "USER.UPDATE",
 	"USER.DELETE":            "USER.DELETE",
+	"USER.SUSPEND":           "USER.SUSPEND",
 	"ISSUER.CREATE":          "ISSUER.CREATE",
 	"ISSUER.READ":            "ISSUER.READ",
 	"ISSUER.UPDATE":          "ISSUER.UPDATE",
@@ -53,6 +66,14 @@ var PermissionCodes = map[string]string{
 	"SUPPORT_FEATURE.READ":   "SUPPORT_FEATURE.READ",
 	"SUPPORT_FEATURE.UPDATE": "SUPPORT_FEATURE.UPDATE",
 	"SUPPORT_FEATURE.DELETE": "SUPPORT_FEATURE.DELETE",
+	"HELP_SERVICE.CREATE":    "HELP_SERVICE.CREATE",
+	"HELP_SERVICE.READ":      "HELP_SERVICE.READ",
+	"HELP_SERVICE.UPDATE":    "HELP_SERVICE.UPDATE",
+	"HELP_SERVICE.DELETE":    "HELP_SERVICE.DELETE",
+	"TAG.CREATE":             "TAG.CREATE",
+	"TAG.READ":               "TAG.READ",
+	"TAG.UPDATE":             "TAG.UPDATE",
+	"TAG.DELETE":             "TAG.DELETE",
 	"ACQUIRER.CREATE":        "ACQUIRER.CREATE",
 	"ACQUIRER.READ":          "ACQUIRER.READ",
 	"ACQUIRER.UPDATE":        "ACQUIRER.UPDATE",
@@ -61,4 +82,16 @@ var PermissionCodes = map[string]string{
 	"ENDPOINT.READ":          "ENDPOINT.READ",
 	"ENDPOINT.UPDATE":        "ENDPOINT.UPDATE",
 	"ENDPOINT.DELETE":        "ENDPOINT.DELETE",
+	"WEBHOOK.CREATE":         "WEBHOOK.CREATE",
+	"WEBHOOK.READ":           "WEBHOOK.READ",
+	"WEBHOOK.UPDATE":         "WEBHOOK.UPDATE",
+	"WEBHOOK.DELETE":         "WEBHOOK.DELETE",
+	"JOB.READ":               "JOB.READ",
+	"JOB.EXECUTE":            "JOB.EXECUTE",
+	"EMAIL_TEMPLATE.READ":    "EMAIL_TEMPLATE.READ",
+	"LOCKOUT.READ":           "LOCKOUT.READ",
+	"LOCKOUT.UPDATE":         "LOCKOUT.UPDATE",
+	"API_KEY.READ":           "API_KEY.READ",
+	"API_KEY.CREATE":         "API_KEY.CREATE",
+	"API_KEY.DELETE":         "API_KEY.DELETE",
 }
@@ -0,0 +1,6 @@
+package emailtemplate
+
+import "embed"
+
+//go:embed layouts partials samples en id
+var FS embed.FS
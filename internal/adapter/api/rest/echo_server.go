@@ -10,6 +10,7 @@ import (
 	"goapptemp/internal/domain/service"
 	"goapptemp/internal/shared/token"
 	"goapptemp/pkg/logger"
+	"goapptemp/pkg/ratelimit"
 	"net/http"
 	"time"
 
@@ -32,6 +33,9 @@ type echoServer struct {
 	token   token.Token
 	handler handler.Handler
 	redis   redisrepository.RedisRepository
+	policy  *service.LoginPolicy
+	limiter ratelimit.Limiter
+	apiKeys service.APIKeyService
 }
 
 func NewEchoServer(config *config.Config, logger logger.Logger, token token.Token, service service.Service, repository repository.Repository) (*echoServer, error) {
@@ -50,6 +54,9 @@ func NewEchoServer(config *config.Config, logger logger.Logger, token token.Toke
 		token:   token,
 		handler: handler,
 		redis:   repository.Redis(),
+		policy:  service.LoginPolicy(),
+		limiter: ratelimit.NewLimiter(repository.Redis()),
+		apiKeys: service.APIKey(),
 	}
 
 	server.setupMiddlewares()
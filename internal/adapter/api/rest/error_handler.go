@@ -87,6 +87,29 @@ func (s *echoServer) httpErrorHandler(err error, c echo.Context) {
 	}
 }
 
+// errorStatus is the status code httpErrorHandler responds to err with.
+func errorStatus(err error) int {
+	var (
+		httpErr  *echo.HTTPError
+		exMarker *exception.Exception
+	)
+
+	switch {
+	case errors.As(err, &httpErr):
+		if errors.As(httpErr.Internal, &exMarker) {
+			statusCode, _, _ := buildErrorPayload(exMarker, httpErr.Code, true, "")
+			return statusCode
+		}
+
+		return httpErr.Code
+	case errors.As(err, &exMarker):
+		statusCode, _, _ := buildErrorPayload(exMarker, 0, false, "")
+		return statusCode
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func buildErrorPayload(ex *exception.Exception, initialStatusCode int, forceGeneric bool, requestID string) (statusCode int, message string, errorDetail any) {
 	defaultMessage := "An internal server error occurred."
 	defaultDetail := map[string]any{"type": string(exception.TypeInternalError), "request_id": requestID}
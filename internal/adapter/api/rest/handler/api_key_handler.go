@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"goapptemp/internal/adapter/api/rest/response"
+	"goapptemp/internal/adapter/api/rest/serializer"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/service"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+	echo "github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler struct {
+	properties
+}
+
+func NewAPIKeyHandler(properties properties) *APIKeyHandler {
+	return &APIKeyHandler{
+		properties: properties,
+	}
+}
+
+type CreateAPIKey struct {
+	Name        string     `json:"name"                 validate:"required,min=2,max=100"`
+	Permissions []string   `json:"permissions"          validate:"required,min=1,dive,required"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" validate:"omitempty"`
+}
+
+type CreateAPIKeyRequest struct {
+	APIKey CreateAPIKey `json:"api_key" validate:"required"`
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(CreateAPIKeyRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	apiKey, key, err := h.service.APIKey().Create(ctx,
+		&service.CreateAPIKeyRequest{
+			AuthParams:  &authArg,
+			Name:        req.APIKey.Name,
+			Permissions: req.APIKey.Permissions,
+			ExpiresAt:   req.APIKey.ExpiresAt,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeAPIKey(apiKey)
+	data.Key = key
+
+	return response.Success(c, "Create API key success", data)
+}
+
+type FilterAPIKeyRequest struct {
+	IDs            []uint `validate:"omitempty,dive,gt=0"     query:"ids"`
+	IncludeRevoked bool   `validate:"omitempty"               query:"include_revoked"`
+	Search         string `validate:"omitempty,min=1"         query:"search"`
+	Page           int    `validate:"omitempty,min=1"         query:"page"`
+	PerPage        int    `validate:"omitempty,min=1,max=100" query:"per_page"`
+}
+
+func (h *APIKeyHandler) FindAPIKeys(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(FilterAPIKeyRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	if req.PerPage <= 0 {
+		req.PerPage = 10
+	} else if req.PerPage > 100 {
+		req.PerPage = 100
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid query parameters")
+	}
+
+	apiKeys, totalCount, err := h.service.APIKey().Find(ctx,
+		&service.FindAPIKeysRequest{
+			AuthParams: &authArg,
+			Filter: &mysqlrepository.FilterAPIKeyPayload{
+				IDs:            req.IDs,
+				IncludeRevoked: req.IncludeRevoked,
+				Search:         req.Search,
+				Page:           req.Page,
+				PerPage:        req.PerPage,
+			},
+		})
+	if err != nil {
+		return err
+	}
+
+	list := serializer.SerializeAPIKeys(apiKeys)
+
+	pagination := response.Pagination{
+		Page:       req.Page,
+		PerPage:    req.PerPage,
+		TotalCount: totalCount,
+		TotalPage:  0,
+	}
+	if req.PerPage > 0 {
+		pagination.TotalPage = (totalCount + req.PerPage - 1) / req.PerPage
+	}
+
+	return response.Paginate(c, "Find API keys success", list, pagination)
+}
+
+func (h *APIKeyHandler) FindOneAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	apiKey, err := h.service.APIKey().FindOne(ctx,
+		&service.FindOneAPIKeyRequest{
+			AuthParams: &authArg,
+			APIKeyID:   id,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeAPIKey(apiKey)
+
+	return response.Success(c, "Find API key success", data)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	err = h.service.APIKey().Revoke(ctx,
+		&service.RevokeAPIKeyRequest{
+			AuthParams: &authArg,
+			APIKeyID:   id,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Revoke API key success", nil)
+}
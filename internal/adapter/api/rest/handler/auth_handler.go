@@ -220,3 +220,58 @@ func (h *AuthHandler) ResetPassword(c echo.Context) error {
 
 	return response.Success(c, "Password has been reset successfully.", nil)
 }
+
+type ChangePasswordRequest struct {
+	Username        string `json:"username" validate:"required"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,max=200"`
+}
+
+func (h *AuthHandler) ChangePassword(c echo.Context) error {
+	ctx := c.Request().Context()
+	req := new(ChangePasswordRequest)
+
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "failed to bind data")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "request validation failed")
+	}
+
+	err := h.service.Auth().ChangePassword(ctx, &service.ChangePasswordRequest{
+		Username:        req.Username,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Password has been changed successfully.", nil)
+}
+
+func (h *AuthHandler) Me(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.service.Auth().Me(ctx, &service.MeRequest{
+		AuthParams: &authArg,
+	})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeUser(user)
+
+	return response.Success(c, "Get current user success", data)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"goapptemp/internal/adapter/api/rest/response"
+	"goapptemp/internal/adapter/api/rest/serializer"
+	"goapptemp/internal/domain/service"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+	echo "github.com/labstack/echo/v4"
+)
+
+type EmailTemplateHandler struct {
+	properties
+}
+
+func NewEmailTemplateHandler(properties properties) *EmailTemplateHandler {
+	return &EmailTemplateHandler{
+		properties: properties,
+	}
+}
+
+func (h *EmailTemplateHandler) FindEmailTemplates(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	templates, err := h.service.EmailTemplate().Find(ctx,
+		&service.FindEmailTemplatesRequest{
+			AuthParams: &authArg,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeEmailTemplates(templates)
+
+	return response.Success(c, "Find email templates success", data)
+}
+
+type PreviewEmailTemplateRequest struct {
+	Name   string         `validate:"required,max=100"                   param:"name"`
+	Locale string         `validate:"omitempty,bcp47_language_tag,max=10" query:"locale" json:"locale"`
+	Format string         `validate:"omitempty,oneof=json html text"      query:"format"`
+	Data   map[string]any `json:"data"`
+}
+
+// PreviewEmailTemplate renders a template with its sample data. GET takes the locale from the query string;
+// POST additionally accepts "data" to override sample values. format=html or format=text returns the raw
+// body so the preview can be opened directly in a browser.
+func (h *EmailTemplateHandler) PreviewEmailTemplate(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(PreviewEmailTemplateRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	// Echo only binds query parameters for GET, DELETE and HEAD.
+	if req.Format == "" {
+		req.Format = c.QueryParam("format")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid parameters")
+	}
+
+	rendered, err := h.service.EmailTemplate().Preview(ctx,
+		&service.PreviewEmailTemplateRequest{
+			AuthParams: &authArg,
+			Name:       req.Name,
+			Locale:     req.Locale,
+			Data:       req.Data,
+		})
+	if err != nil {
+		return err
+	}
+
+	switch req.Format {
+	case "html":
+		return c.HTML(http.StatusOK, rendered.HTML)
+	case "text":
+		return c.String(http.StatusOK, rendered.Text)
+	}
+
+	data := serializer.SerializeEmailTemplatePreview(rendered)
+
+	return response.Success(c, "Preview email template success", data)
+}
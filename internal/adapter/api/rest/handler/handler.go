@@ -13,16 +13,25 @@ import (
 )
 
 type Handler interface {
+	APIKey() *APIKeyHandler
 	Auth() *AuthHandler
 	City() *CityHandler
 	District() *DistrictHandler
+	EmailTemplate() *EmailTemplateHandler
 	Health() *HealthHandler
+	Invitation() *InvitationHandler
+	Job() *JobHandler
+	Lockout() *LockoutHandler
 	Migration() *MigrationHandler
+	Notification() *NotificationHandler
+	Permission() *PermissionHandler
 	Province() *ProvinceHandler
 	Role() *RoleHandler
+	SSO() *SSOHandler
 	SupportFeature() *SupportFeatureHandler
 	User() *UserHandler
 	Webhook() *WebhookHandler
+	WebhookSubscription() *WebhookSubscriptionHandler
 }
 
 type properties struct {
@@ -34,16 +43,25 @@ type properties struct {
 
 type handler struct {
 	properties
-	authHandler           *AuthHandler
-	cityHandler           *CityHandler
-	districtHandler       *DistrictHandler
-	healthHandler         *HealthHandler
-	migrationHandler      *MigrationHandler
-	provinceHandler       *ProvinceHandler
-	roleHandler           *RoleHandler
-	supportFeatureHandler *SupportFeatureHandler
-	userHandler           *UserHandler
-	webhookHandler        *WebhookHandler
+	apiKeyHandler              *APIKeyHandler
+	authHandler                *AuthHandler
+	cityHandler                *CityHandler
+	districtHandler            *DistrictHandler
+	emailTemplateHandler       *EmailTemplateHandler
+	healthHandler              *HealthHandler
+	invitationHandler          *InvitationHandler
+	jobHandler                 *JobHandler
+	lockoutHandler             *LockoutHandler
+	migrationHandler           *MigrationHandler
+	notificationHandler        *NotificationHandler
+	permissionHandler          *PermissionHandler
+	provinceHandler            *ProvinceHandler
+	roleHandler                *RoleHandler
+	ssoHandler                 *SSOHandler
+	supportFeatureHandler      *SupportFeatureHandler
+	userHandler                *UserHandler
+	webhookHandler             *WebhookHandler
+	webhookSubscriptionHandler *WebhookSubscriptionHandler
 }
 
 func NewHandler(config *config.Config, logger logger.Logger, service service.Service, db *bun.DB) (*handler, error) {
@@ -64,20 +82,33 @@ func NewHandler(config *config.Config, logger logger.Logger, service service.Ser
 	}
 
 	return &handler{
-		properties:            properties,
-		authHandler:           NewAuthHandler(properties),
-		cityHandler:           NewCityHandler(properties),
-		districtHandler:       NewDistrictHandler(properties),
-		healthHandler:         NewHealthHandler(db, logger),
-		migrationHandler:      NewMigrationHandler(properties),
-		provinceHandler:       NewProvinceHandler(properties),
-		roleHandler:           NewRoleHandler(properties),
-		supportFeatureHandler: NewSupportFeatureHandler(properties),
-		userHandler:           NewUserHandler(properties),
-		webhookHandler:        NewWebhookHandler(properties),
+		properties:                 properties,
+		apiKeyHandler:              NewAPIKeyHandler(properties),
+		authHandler:                NewAuthHandler(properties),
+		cityHandler:                NewCityHandler(properties),
+		districtHandler:            NewDistrictHandler(properties),
+		emailTemplateHandler:       NewEmailTemplateHandler(properties),
+		healthHandler:              NewHealthHandler(db, logger),
+		invitationHandler:          NewInvitationHandler(properties),
+		jobHandler:                 NewJobHandler(properties),
+		lockoutHandler:             NewLockoutHandler(properties),
+		migrationHandler:           NewMigrationHandler(properties),
+		notificationHandler:        NewNotificationHandler(properties),
+		permissionHandler:          NewPermissionHandler(properties),
+		provinceHandler:            NewProvinceHandler(properties),
+		roleHandler:                NewRoleHandler(properties),
+		ssoHandler:                 NewSSOHandler(properties),
+		supportFeatureHandler:      NewSupportFeatureHandler(properties),
+		userHandler:                NewUserHandler(properties),
+		webhookHandler:             NewWebhookHandler(properties),
+		webhookSubscriptionHandler: NewWebhookSubscriptionHandler(properties),
 	}, nil
 }
 
+func (h *handler) APIKey() *APIKeyHandler {
+	return h.apiKeyHandler
+}
+
 func (h *handler) Auth() *AuthHandler {
 	return h.authHandler
 }
@@ -90,14 +121,38 @@ func (h *handler) District() *DistrictHandler {
 	return h.districtHandler
 }
 
+func (h *handler) EmailTemplate() *EmailTemplateHandler {
+	return h.emailTemplateHandler
+}
+
 func (h *handler) Health() *HealthHandler {
 	return h.healthHandler
 }
 
+func (h *handler) Invitation() *InvitationHandler {
+	return h.invitationHandler
+}
+
+func (h *handler) Job() *JobHandler {
+	return h.jobHandler
+}
+
+func (h *handler) Lockout() *LockoutHandler {
+	return h.lockoutHandler
+}
+
 func (h *handler) Migration() *MigrationHandler {
 	return h.migrationHandler
 }
 
+func (h *handler) Notification() *NotificationHandler {
+	return h.notificationHandler
+}
+
+func (h *handler) Permission() *PermissionHandler {
+	return h.permissionHandler
+}
+
 func (h *handler) Province() *ProvinceHandler {
 	return h.provinceHandler
 }
@@ -106,6 +161,10 @@ func (h *handler) Role() *RoleHandler {
 	return h.roleHandler
 }
 
+func (h *handler) SSO() *SSOHandler {
+	return h.ssoHandler
+}
+
 func (h *handler) SupportFeature() *SupportFeatureHandler {
 	return h.supportFeatureHandler
 }
@@ -117,3 +176,7 @@ func (h *handler) User() *UserHandler {
 func (h *handler) Webhook() *WebhookHandler {
 	return h.webhookHandler
 }
+
+func (h *handler) WebhookSubscription() *WebhookSubscriptionHandler {
+	return h.webhookSubscriptionHandler
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"goapptemp/internal/adapter/api/rest/response"
+	"goapptemp/internal/adapter/api/rest/serializer"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/domain/service"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+	"io"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+	echo "github.com/labstack/echo/v4"
+)
+
+type InvitationHandler struct {
+	properties
+}
+
+func NewInvitationHandler(properties properties) *InvitationHandler {
+	return &InvitationHandler{
+		properties: properties,
+	}
+}
+
+type InviteUser struct {
+	RoleIDs  []uint `json:"role_ids" validate:"required,dive,required,gt=0"`
+	Fullname string `json:"fullname" validate:"required,min=3,max=100"`
+	Username string `json:"username" validate:"required,min=3,max=100,username_chars_allowed"`
+	Email    string `json:"email"    validate:"required,email,min=3,max=100"`
+	Locale   string `json:"locale"   validate:"omitempty,bcp47_language_tag,max=10"`
+}
+
+type InviteUserRequest struct {
+	User InviteUser `json:"user" validate:"required"`
+}
+
+func (h *InvitationHandler) InviteUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(InviteUserRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	user, err := h.service.Invitation().Invite(ctx, &service.InviteUserRequest{
+		AuthParams: &authArg,
+		User: &entity.User{
+			RoleIDs:  req.User.RoleIDs,
+			Fullname: req.User.Fullname,
+			Email:    req.User.Email,
+			Username: req.User.Username,
+			Locale:   req.User.Locale,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeUser(user)
+
+	return response.Success(c, "Invite user success", data)
+}
+
+func (h *InvitationHandler) ResendInvitation(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	err = h.service.Invitation().Resend(ctx, &service.ResendInvitationRequest{
+		AuthParams: &authArg,
+		UserID:     id,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Resend invitation success", nil)
+}
+
+func (h *InvitationHandler) RevokeInvitation(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	err = h.service.Invitation().Revoke(ctx, &service.RevokeInvitationRequest{
+		AuthParams: &authArg,
+		UserID:     id,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Revoke invitation success", nil)
+}
+
+type VerifyInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (h *InvitationHandler) VerifyInvitation(c echo.Context) error {
+	ctx := c.Request().Context()
+	req := new(VerifyInvitationRequest)
+
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	if err := h.service.Invitation().Verify(ctx, &service.VerifyInvitationRequest{Token: req.Token}); err != nil {
+		return err
+	}
+
+	return response.Success(c, "Invitation is valid.", nil)
+}
+
+type AcceptInvitationRequest struct {
+	Token    string `json:"token"    validate:"required"`
+	Password string `json:"password" validate:"required,max=200"`
+}
+
+func (h *InvitationHandler) AcceptInvitation(c echo.Context) error {
+	ctx := c.Request().Context()
+	req := new(AcceptInvitationRequest)
+
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	err := h.service.Invitation().Accept(ctx, &service.AcceptInvitationRequest{
+		Token:    req.Token,
+		Password: req.Password,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Account has been activated. You can now log in.", nil)
+}
+
+func (h *InvitationHandler) TemplateImportUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	fileData, err := h.service.Invitation().TemplateImport(ctx, &service.TemplateImportUserRequest{
+		AuthParams: &authArg,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, fileData.MIMEType)
+	c.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(fileData.Size, 10))
+	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename="+strconv.Quote(fileData.Filename))
+
+	if _, err := io.Copy(c.Response().Writer, fileData.Content); err != nil {
+		if h.logger != nil {
+			h.logger.Error().Err(err).Msg("Failed to write Excel file")
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (h *InvitationHandler) ImportPreviewUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to get file from form")
+	}
+
+	data, err := h.service.Invitation().ImportPreview(ctx, &service.ImportPreviewUserRequest{
+		AuthParams: &authArg,
+		File:       file,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Import preview success", serializer.SerializeUserImportPreviews(data))
+}
+
+func (h *InvitationHandler) ImportUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to get file from form")
+	}
+
+	users, err := h.service.Invitation().Import(ctx, &service.ImportUsersRequest{
+		AuthParams: &authArg,
+		File:       file,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Import users success", serializer.SerializeImportedUsers(users))
+}
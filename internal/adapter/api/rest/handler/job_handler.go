@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"goapptemp/internal/adapter/api/rest/response"
+	"goapptemp/internal/adapter/api/rest/serializer"
+	"goapptemp/internal/domain/service"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+	echo "github.com/labstack/echo/v4"
+)
+
+type JobHandler struct {
+	properties
+}
+
+func NewJobHandler(properties properties) *JobHandler {
+	return &JobHandler{
+		properties: properties,
+	}
+}
+
+func (h *JobHandler) FindJobs(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	jobs, err := h.service.Job().Find(ctx,
+		&service.FindJobsRequest{
+			AuthParams: &authArg,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeJobs(jobs)
+
+	return response.Success(c, "Find jobs success", data)
+}
+
+type FindJobRunsRequest struct {
+	Name  string `validate:"required,max=100"        param:"name"`
+	Limit int    `validate:"omitempty,min=1,max=100" query:"limit"`
+}
+
+func (h *JobHandler) FindJobRuns(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(FindJobRunsRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid query parameters")
+	}
+
+	runs, err := h.service.Job().FindRuns(ctx,
+		&service.FindJobRunsRequest{
+			AuthParams: &authArg,
+			Name:       req.Name,
+			Limit:      req.Limit,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeJobRuns(runs)
+
+	return response.Success(c, "Find job runs success", data)
+}
+
+func (h *JobHandler) TriggerJob(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	run, err := h.service.Job().Trigger(ctx,
+		&service.TriggerJobRequest{
+			AuthParams: &authArg,
+			Name:       c.Param("name"),
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeJobRun(run)
+
+	return response.Success(c, "Trigger job success", data)
+}
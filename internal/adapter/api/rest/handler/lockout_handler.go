@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"goapptemp/internal/adapter/api/rest/response"
+	"goapptemp/internal/adapter/api/rest/serializer"
+	"goapptemp/internal/domain/service"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+	echo "github.com/labstack/echo/v4"
+)
+
+type LockoutHandler struct {
+	properties
+}
+
+func NewLockoutHandler(properties properties) *LockoutHandler {
+	return &LockoutHandler{
+		properties: properties,
+	}
+}
+
+func (h *LockoutHandler) FindLockedUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	blocks, err := h.service.Lockout().FindLockedUsers(ctx,
+		&service.FindLockedUsersRequest{
+			AuthParams: &authArg,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeLoginBlocks(blocks)
+
+	return response.Success(c, "Find locked users success", data)
+}
+
+func (h *LockoutHandler) FindBlockedIPs(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	blocks, err := h.service.Lockout().FindBlockedIPs(ctx,
+		&service.FindBlockedIPsRequest{
+			AuthParams: &authArg,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeLoginBlocks(blocks)
+
+	return response.Success(c, "Find blocked IPs success", data)
+}
+
+type UnlockUserRequest struct {
+	Username string `validate:"required,max=255" param:"username"`
+}
+
+func (h *LockoutHandler) UnlockUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(UnlockUserRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid parameters")
+	}
+
+	err = h.service.Lockout().UnlockUser(ctx,
+		&service.UnlockUserRequest{
+			AuthParams: &authArg,
+			Username:   req.Username,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Unlock user success", nil)
+}
+
+type UnblockIPRequest struct {
+	IP string `validate:"required,ip" param:"ip"`
+}
+
+func (h *LockoutHandler) UnblockIP(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(UnblockIPRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid parameters")
+	}
+
+	err = h.service.Lockout().UnblockIP(ctx,
+		&service.UnblockIPRequest{
+			AuthParams: &authArg,
+			IP:         req.IP,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Unblock IP success", nil)
+}
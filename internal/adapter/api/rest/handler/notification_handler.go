@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"goapptemp/internal/adapter/api/rest/response"
+	"goapptemp/internal/adapter/api/rest/serializer"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/domain/service"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+	echo "github.com/labstack/echo/v4"
+)
+
+// NotificationHandler serves the authenticated user's own inbox and preferences.
+type NotificationHandler struct {
+	properties
+}
+
+func NewNotificationHandler(properties properties) *NotificationHandler {
+	return &NotificationHandler{
+		properties: properties,
+	}
+}
+
+type FilterNotificationRequest struct {
+	Unread  bool     `validate:"omitempty"                  query:"unread"`
+	Types   []string `validate:"omitempty,dive,min=1,max=255" query:"types"`
+	Page    int      `validate:"omitempty,min=1"            query:"page"`
+	PerPage int      `validate:"omitempty,min=1,max=100"    query:"per_page"`
+}
+
+func (h *NotificationHandler) FindNotifications(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(FilterNotificationRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	if req.PerPage <= 0 {
+		req.PerPage = 10
+	} else if req.PerPage > 100 {
+		req.PerPage = 100
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid query parameters")
+	}
+
+	notifications, totalCount, err := h.service.Notification().Find(ctx,
+		&service.FindNotificationsRequest{
+			AuthParams: &authArg,
+			UnreadOnly: req.Unread,
+			Types:      req.Types,
+			Page:       req.Page,
+			PerPage:    req.PerPage,
+		})
+	if err != nil {
+		return err
+	}
+
+	list := serializer.SerializeNotifications(notifications)
+
+	pagination := response.Pagination{
+		Page:       req.Page,
+		PerPage:    req.PerPage,
+		TotalCount: totalCount,
+		TotalPage:  0,
+	}
+	if req.PerPage > 0 {
+		pagination.TotalPage = (totalCount + req.PerPage - 1) / req.PerPage
+	}
+
+	return response.Paginate(c, "Find notifications success", list, pagination)
+}
+
+func (h *NotificationHandler) CountUnreadNotifications(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	count, err := h.service.Notification().CountUnread(ctx,
+		&service.CountUnreadNotificationsRequest{
+			AuthParams: &authArg,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Count unread notifications success", map[string]int{"unread_count": count})
+}
+
+type MarkNotificationsReadRequest struct {
+	IDs []uint64 `validate:"required,min=1,max=100,dive,gt=0" json:"ids"`
+}
+
+func (h *NotificationHandler) MarkNotificationsRead(c echo.Context) error {
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(MarkNotificationsReadRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind request body")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid request body")
+	}
+
+	return h.markRead(c, authArg, req.IDs)
+}
+
+func (h *NotificationHandler) MarkNotificationRead(c echo.Context) error {
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, parseErr := strconv.ParseUint(c.Param("id"), 10, 64)
+	if parseErr != nil || id == 0 {
+		msg := "id must be a positive integer in URL path"
+		err := exception.Wrap(parseErr, exception.TypeBadRequest, exception.CodeValidationFailed, msg)
+
+		return exception.WithFieldError(err, "id", msg)
+	}
+
+	return h.markRead(c, authArg, []uint64{id})
+}
+
+func (h *NotificationHandler) markRead(c echo.Context, authArg service.AuthParams, ids []uint64) error {
+	updated, err := h.service.Notification().MarkRead(c.Request().Context(),
+		&service.MarkNotificationsReadRequest{
+			AuthParams: &authArg,
+			IDs:        ids,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Mark notifications read success", map[string]int{"updated": updated})
+}
+
+func (h *NotificationHandler) MarkAllNotificationsRead(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.service.Notification().MarkAllRead(ctx,
+		&service.MarkAllNotificationsReadRequest{
+			AuthParams: &authArg,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Mark all notifications read success", map[string]int{"updated": updated})
+}
+
+func (h *NotificationHandler) FindNotificationPreferences(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	preferences, err := h.service.Notification().FindPreferences(ctx,
+		&service.FindNotificationPreferencesRequest{
+			AuthParams: &authArg,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeNotificationPreferences(preferences)
+
+	return response.Success(c, "Find notification preferences success", data)
+}
+
+type NotificationPreferenceRequest struct {
+	Type    string `validate:"required,max=255"                 json:"type"`
+	Channel string `validate:"required,oneof=email in_app webhook" json:"channel"`
+	Enabled *bool  `validate:"required"                         json:"enabled"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []*NotificationPreferenceRequest `validate:"required,min=1,dive,required" json:"preferences"`
+}
+
+func (h *NotificationHandler) UpdateNotificationPreferences(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(UpdateNotificationPreferencesRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind request body")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid request body")
+	}
+
+	preferences := make([]*entity.NotificationPreference, 0, len(req.Preferences))
+	for _, pref := range req.Preferences {
+		preferences = append(preferences, &entity.NotificationPreference{
+			Type:    entity.NotificationType(pref.Type),
+			Channel: entity.NotificationChannel(pref.Channel),
+			Enabled: *pref.Enabled,
+		})
+	}
+
+	updated, err := h.service.Notification().UpdatePreferences(ctx,
+		&service.UpdateNotificationPreferencesRequest{
+			AuthParams:  &authArg,
+			Preferences: preferences,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeNotificationPreferences(updated)
+
+	return response.Success(c, "Update notification preferences success", data)
+}
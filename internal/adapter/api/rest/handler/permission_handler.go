@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"goapptemp/internal/adapter/api/rest/response"
+	"goapptemp/internal/adapter/api/rest/serializer"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/service"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+	echo "github.com/labstack/echo/v4"
+)
+
+type PermissionHandler struct {
+	properties
+}
+
+func NewPermissionHandler(properties properties) *PermissionHandler {
+	return &PermissionHandler{
+		properties: properties,
+	}
+}
+
+type FilterPermissionRequest struct {
+	IDs     []uint   `validate:"omitempty,dive,gt=0"           query:"ids"`
+	Codes   []string `validate:"omitempty,dive,min=3,max=255"  query:"codes"`
+	Modules []string `validate:"omitempty,dive,min=1,max=100"  query:"modules"`
+	Search  string   `validate:"omitempty,min=1"               query:"search"`
+	Page    int      `validate:"omitempty,min=1"               query:"page"`
+	PerPage int      `validate:"omitempty,min=1,max=100"       query:"per_page"`
+}
+
+func (h *PermissionHandler) FindPermissions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(FilterPermissionRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	if req.PerPage <= 0 {
+		req.PerPage = 10
+	} else if req.PerPage > 100 {
+		req.PerPage = 100
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid query parameters")
+	}
+
+	permissions, totalCount, err := h.service.Permission().Find(ctx,
+		&service.FindPermissionsRequest{
+			AuthParams: &authArg,
+			Filter: &mysqlrepository.FilterPermissionPayload{
+				IDs:     req.IDs,
+				Codes:   req.Codes,
+				Modules: toPermissionModules(req.Modules),
+				Search:  req.Search,
+				Page:    req.Page,
+				PerPage: req.PerPage,
+			},
+		})
+	if err != nil {
+		return err
+	}
+
+	list := serializer.SerializePermissions(permissions)
+
+	pagination := response.Pagination{
+		Page:       req.Page,
+		PerPage:    req.PerPage,
+		TotalCount: totalCount,
+		TotalPage:  0,
+	}
+	if req.PerPage > 0 {
+		pagination.TotalPage = (totalCount + req.PerPage - 1) / req.PerPage
+	}
+
+	return response.Paginate(c, "Find permissions success", list, pagination)
+}
+
+type FilterPermissionGroupRequest struct {
+	Modules []string `validate:"omitempty,dive,min=1,max=100" query:"modules"`
+}
+
+func (h *PermissionHandler) FindPermissionGroups(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(FilterPermissionGroupRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid query parameters")
+	}
+
+	groups, err := h.service.Permission().FindGroups(ctx,
+		&service.FindPermissionGroupsRequest{
+			AuthParams: &authArg,
+			Modules:    toPermissionModules(req.Modules),
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializePermissionGroups(groups)
+
+	return response.Success(c, "Find permission groups success", data)
+}
+
+func (h *PermissionHandler) FindOnePermission(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	permission, err := h.service.Permission().FindOne(ctx,
+		&service.FindOnePermissionRequest{
+			AuthParams:   &authArg,
+			PermissionID: id,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializePermission(permission)
+
+	return response.Success(c, "Find permission success", data)
+}
+
+// toPermissionModules upper-cases module names so ?modules=user matches USER.* codes.
+func toPermissionModules(modules []string) []string {
+	if len(modules) == 0 {
+		return nil
+	}
+
+	res := make([]string, 0, len(modules))
+	for _, module := range modules {
+		res = append(res, strings.ToUpper(module))
+	}
+
+	return res
+}
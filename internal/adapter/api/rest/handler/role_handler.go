@@ -30,6 +30,8 @@ type CreateRole struct {
 	Name          string  `json:"name"                  validate:"required,min=2,max=100"`
 	Description   *string `json:"description,omitempty" validate:"max=255"`
 	SuperAdmin    bool    `json:"super_admin"`
+	ParentID      *uint   `json:"parent_id,omitempty"   validate:"omitempty,gt=0"`
+	CompanyID     *uint   `json:"company_id,omitempty"  validate:"omitempty,gt=0"`
 }
 
 type CreateRoleRequest struct {
@@ -69,6 +71,8 @@ func (h *RoleHandler) CreateRole(c echo.Context) error {
 				Code:          req.Role.Code,
 				Description:   req.Role.Description,
 				SuperAdmin:    req.Role.SuperAdmin,
+				ParentID:      req.Role.ParentID,
+				CompanyID:     req.Role.CompanyID,
 			},
 		})
 	if err != nil {
@@ -85,6 +89,9 @@ type FilterRoleRequest struct {
 	Codes      []string `validate:"omitempty,dive,min=2,max=50,code_chars_allowed" query:"codes"`
 	Names      []string `validate:"omitempty,dive,min=2,max=100"                   query:"names"`
 	SuperAdmin *bool    `validate:"omitempty"                                      query:"super_admin"`
+	ParentIDs  []uint   `validate:"omitempty,dive,gt=0"                            query:"parent_ids"`
+	CompanyIDs []uint   `validate:"omitempty,dive,gt=0"                            query:"company_ids"`
+	Global     *bool    `validate:"omitempty"                                      query:"global"`
 	Search     string   `validate:"omitempty,min=1"                                query:"search"`
 	Page       int      `validate:"omitempty,min=1"                                query:"page"`
 	PerPage    int      `validate:"omitempty,min=1,max=100"                        query:"per_page"`
@@ -132,6 +139,9 @@ func (h *RoleHandler) FindRoles(c echo.Context) error {
 				Names:      req.Names,
 				Codes:      req.Codes,
 				SuperAdmin: req.SuperAdmin,
+				ParentIDs:  req.ParentIDs,
+				CompanyIDs: req.CompanyIDs,
+				Global:     req.Global,
 				Search:     req.Search,
 				Page:       req.Page,
 				PerPage:    req.PerPage,
@@ -190,6 +200,8 @@ type UpdateRole struct {
 	Name          *string `json:"name,omitempty"           validate:"min=2,max=100"`
 	Description   *string `json:"description,omitempty"    validate:"max=255"`
 	SuperAdmin    *bool   `json:"super_admin,omitempty"`
+	// ParentID 0 detaches the role from its parent.
+	ParentID *uint `json:"parent_id,omitempty"`
 }
 
 type UpdateRoleRequest struct {
@@ -236,6 +248,7 @@ func (h *RoleHandler) UpdateRole(c echo.Context) error {
 				Code:          req.Role.Code,
 				Description:   req.Role.Description,
 				SuperAdmin:    req.Role.SuperAdmin,
+				ParentID:      req.Role.ParentID,
 			},
 		})
 	if err != nil {
@@ -271,3 +284,91 @@ func (h *RoleHandler) DeleteRole(c echo.Context) error {
 
 	return response.Success(c, "Delete role success", nil)
 }
+
+func (h *RoleHandler) FindRolePermissions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	permissions, err := h.service.Role().FindEffectivePermissions(ctx,
+		&service.FindRolePermissionsRequest{
+			AuthParams: &authArg,
+			RoleID:     id,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeRolePermissions(id, permissions)
+
+	return response.Success(c, "Find role permissions success", data)
+}
+
+func (h *RoleHandler) FindRoleDependencies(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	dependencies, err := h.service.Role().FindDependencies(ctx,
+		&service.FindRoleDependenciesRequest{
+			AuthParams: &authArg,
+			RoleID:     id,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Find role dependencies success", serializer.SerializeDependencies(dependencies))
+}
+
+func (h *RoleHandler) BulkDeleteRoles(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(BulkIDsRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	results, err := h.service.Role().BulkDelete(ctx,
+		&service.BulkDeleteRolesRequest{
+			AuthParams: &authArg,
+			RoleIDs:    req.IDs,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Bulk delete role success", serializer.SerializeBulkResults(results))
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"goapptemp/internal/adapter/api/rest/response"
+	"goapptemp/internal/adapter/api/rest/serializer"
+	"goapptemp/internal/domain/service"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+	echo "github.com/labstack/echo/v4"
+)
+
+type SSOHandler struct {
+	properties
+}
+
+func NewSSOHandler(properties properties) *SSOHandler {
+	return &SSOHandler{
+		properties: properties,
+	}
+}
+
+func (h *SSOHandler) FindSSOProviders(c echo.Context) error {
+	data := serializer.SerializeSSOProviders(h.service.SSO().Providers())
+
+	return response.Success(c, "Find SSO providers success", data)
+}
+
+type SSOAuthorizeRequest struct {
+	Provider string `validate:"required,max=64" param:"provider"`
+}
+
+// AuthorizeSSO returns the identity provider URL instead of redirecting, so the frontend decides
+// how to navigate there.
+func (h *SSOHandler) AuthorizeSSO(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(SSOAuthorizeRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid parameters")
+	}
+
+	authURL, err := h.service.SSO().Authorize(ctx, &service.SSOAuthorizeRequest{Provider: req.Provider})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Authorize SSO success", &serializer.SSOAuthorizeResponseData{AuthorizationURL: authURL})
+}
+
+type SSOCallbackRequest struct {
+	Provider string `validate:"required,max=64"   param:"provider"`
+	Code     string `json:"code"                  validate:"required,max=2048"`
+	State    string `json:"state"                 validate:"required,max=128"`
+}
+
+func (h *SSOHandler) CallbackSSO(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(SSOCallbackRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	user, err := h.service.SSO().Callback(ctx, &service.SSOCallbackRequest{
+		Provider: req.Provider,
+		Code:     req.Code,
+		State:    req.State,
+	})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeUser(user)
+
+	return response.Success(c, "SSO login success", data)
+}
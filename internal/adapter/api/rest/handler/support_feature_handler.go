@@ -318,10 +318,16 @@ func (h *SupportFeatureHandler) DeleteSupportFeature(c echo.Context) error {
 		return err
 	}
 
+	cascade, err := parseBoolQuery(c, "cascade")
+	if err != nil {
+		return err
+	}
+
 	err = h.service.SupportFeature().Delete(ctx,
 		&service.DeleteSupportFeatureRequest{
 			AuthParams:       &authArg,
 			SupportFeatureID: id,
+			Cascade:          cascade,
 		})
 	if err != nil {
 		return err
@@ -359,6 +365,31 @@ func (h *SupportFeatureHandler) IsSupportFeatureDeletable(c echo.Context) error
 	return response.Success(c, "Check if help service is deletable success", &data{IsDeletable: isDeletable})
 }
 
+func (h *SupportFeatureHandler) FindSupportFeatureDependencies(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	dependencies, err := h.service.SupportFeature().FindDependencies(ctx,
+		&service.FindSupportFeatureDependenciesRequest{
+			AuthParams:       &authArg,
+			SupportFeatureID: id,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Find help service dependencies success", serializer.SerializeDependencies(dependencies))
+}
+
 func (h *SupportFeatureHandler) ImportPreviewSupportFeature(c echo.Context) error {
 	ctx := c.Request().Context()
 
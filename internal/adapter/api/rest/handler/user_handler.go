@@ -29,7 +29,8 @@ type CreateUser struct {
 	Fullname string `json:"fullname" validate:"required,min=3,max=100"`
 	Username string `json:"username" validate:"required,min=3,max=100,username_chars_allowed"`
 	Email    string `json:"email"    validate:"required,email,min=3,max=100"`
-	Password string `json:"password" validate:"required,password,max=200"`
+	Password string `json:"password" validate:"required,max=200"`
+	Locale   string `json:"locale"   validate:"omitempty,bcp47_language_tag,max=10"`
 }
 
 type CreateUserRequest struct {
@@ -68,6 +69,7 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 			Email:    req.User.Email,
 			Username: req.User.Username,
 			Password: req.User.Password,
+			Locale:   req.User.Locale,
 		},
 	})
 	if err != nil {
@@ -83,6 +85,7 @@ type FilterUserRequest struct {
 	IDs       []uint   `validate:"omitempty,dive,gt=0"                           query:"ids"`
 	Usernames []string `validate:"omitemptymin=3,max=100,username_chars_allowed" query:"usernames"`
 	Emails    []string `validate:"email,min=3,max=100"                           query:"emails"`
+	Statuses  []string `validate:"omitempty,dive,oneof=active pending suspended" query:"statuses"`
 	Search    string   `validate:"omitempty,min=1"                               query:"search"`
 	Page      int      `validate:"omitempty,min=1"                               query:"page"`
 	PerPage   int      `validate:"omitempty,min=1,max=100"                       query:"per_page"`
@@ -129,6 +132,7 @@ func (h *UserHandler) FindUsers(c echo.Context) error {
 				IDs:       req.IDs,
 				Usernames: req.Usernames,
 				Emails:    req.Emails,
+				Statuses:  toUserStatuses(req.Statuses),
 				Search:    req.Search,
 				Page:      req.Page,
 				PerPage:   req.PerPage,
@@ -153,6 +157,19 @@ func (h *UserHandler) FindUsers(c echo.Context) error {
 	return response.Paginate(c, "Find users success", list, pagination)
 }
 
+func toUserStatuses(statuses []string) []entity.UserStatus {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	res := make([]entity.UserStatus, 0, len(statuses))
+	for _, status := range statuses {
+		res = append(res, entity.UserStatus(status))
+	}
+
+	return res
+}
+
 func (h *UserHandler) FindOneUser(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -185,8 +202,9 @@ type UpdateUser struct {
 	RoleIDs  []*uint `json:"role_ids,omitempty" validate:"dive,required,gt=0"`
 	Email    *string `json:"email,omitempty"    validate:"email,min=3,max=100"`
 	Username *string `json:"username,omitempty" validate:"min=3,max=100,username_chars_allowed"`
-	Password *string `json:"password,omitempty" validate:"password,max=200"`
+	Password *string `json:"password,omitempty" validate:"omitempty,max=200"`
 	Fullname *string `json:"fullname,omitempty" validate:"min=3,max=100"`
+	Locale   *string `json:"locale,omitempty"   validate:"omitempty,bcp47_language_tag,max=10"`
 }
 
 type UpdateUserRequest struct {
@@ -233,6 +251,7 @@ func (h *UserHandler) UpdateUser(c echo.Context) error {
 				Email:    req.User.Email,
 				Username: req.User.Username,
 				Password: req.User.Password,
+				Locale:   req.User.Locale,
 			},
 		})
 	if err != nil {
@@ -268,3 +287,307 @@ func (h *UserHandler) DeleteUser(c echo.Context) error {
 
 	return response.Success(c, "Delete user success", nil)
 }
+
+func (h *UserHandler) FindUserDependencies(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	dependencies, err := h.service.User().FindDependencies(ctx,
+		&service.FindUserDependenciesRequest{
+			AuthParams: &authArg,
+			UserID:     id,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Find user dependencies success", serializer.SerializeDependencies(dependencies))
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+func (h *UserHandler) SuspendUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(SuspendUserRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	user, err := h.service.User().Suspend(ctx,
+		&service.SuspendUserRequest{
+			AuthParams: &authArg,
+			UserID:     id,
+			Reason:     req.Reason,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeUser(user)
+
+	return response.Success(c, "Suspend user success", data)
+}
+
+func (h *UserHandler) ReactivateUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	user, err := h.service.User().Reactivate(ctx,
+		&service.ReactivateUserRequest{
+			AuthParams: &authArg,
+			UserID:     id,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeUser(user)
+
+	return response.Success(c, "Reactivate user success", data)
+}
+
+type ImpersonateUserRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+func (h *UserHandler) ImpersonateUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(ImpersonateUserRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	user, err := h.service.Impersonation().Impersonate(ctx,
+		&service.ImpersonateRequest{
+			AuthParams: &authArg,
+			UserID:     id,
+			Reason:     req.Reason,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeUser(user)
+
+	return response.Success(c, "Impersonate user success", data)
+}
+
+type BulkSuspendUsersRequest struct {
+	IDs    []uint `json:"ids"    validate:"required,min=1,max=100,dive,gt=0"`
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type BulkAssignUserRolesRequest struct {
+	IDs     []uint `json:"ids"      validate:"required,min=1,max=100,dive,gt=0"`
+	RoleIDs []uint `json:"role_ids" validate:"required,min=1,dive,gt=0"`
+}
+
+func (h *UserHandler) BulkDeleteUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(BulkIDsRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	results, err := h.service.User().BulkDelete(ctx,
+		&service.BulkDeleteUsersRequest{
+			AuthParams: &authArg,
+			UserIDs:    req.IDs,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Bulk delete user success", serializer.SerializeBulkResults(results))
+}
+
+func (h *UserHandler) BulkDeactivateUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(BulkSuspendUsersRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	results, err := h.service.User().BulkSuspend(ctx,
+		&service.BulkSetUserStatusRequest{
+			AuthParams: &authArg,
+			UserIDs:    req.IDs,
+			Reason:     req.Reason,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Bulk deactivate user success", serializer.SerializeBulkResults(results))
+}
+
+func (h *UserHandler) BulkActivateUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(BulkIDsRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	results, err := h.service.User().BulkReactivate(ctx,
+		&service.BulkSetUserStatusRequest{
+			AuthParams: &authArg,
+			UserIDs:    req.IDs,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Bulk activate user success", serializer.SerializeBulkResults(results))
+}
+
+func (h *UserHandler) BulkAssignUserRoles(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(BulkAssignUserRolesRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	results, err := h.service.User().BulkAssignRoles(ctx,
+		&service.BulkAssignUserRolesRequest{
+			AuthParams: &authArg,
+			UserIDs:    req.IDs,
+			RoleIDs:    req.RoleIDs,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Bulk assign user roles success", serializer.SerializeBulkResults(results))
+}
@@ -9,6 +9,11 @@ import (
 	echo "github.com/labstack/echo/v4"
 )
 
+// BulkIDsRequest is the body of bulk endpoints that only take record IDs.
+type BulkIDsRequest struct {
+	IDs []uint `json:"ids" validate:"required,min=1,max=100,dive,gt=0"`
+}
+
 func parseUintParam(c echo.Context, paramName string) (uint, error) {
 	idStr := c.Param(paramName)
 	if idStr == "" {
@@ -29,6 +34,24 @@ func parseUintParam(c echo.Context, paramName string) (uint, error) {
 	return uint(id), nil
 }
 
+// parseBoolQuery reads an optional boolean query parameter, treating an absent one as false.
+func parseBoolQuery(c echo.Context, paramName string) (bool, error) {
+	value := c.QueryParam(paramName)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, parseErr := strconv.ParseBool(value)
+	if parseErr != nil {
+		msg := paramName + " must be a boolean in URL query"
+		err := exception.Wrap(parseErr, exception.TypeBadRequest, exception.CodeValidationFailed, msg)
+
+		return false, exception.WithFieldError(err, paramName, msg)
+	}
+
+	return parsed, nil
+}
+
 func getAuthArg(c echo.Context) (service.AuthParams, error) {
 	arg := c.Get(constant.CtxKeyAuthPayload)
 	if arg == nil {
@@ -0,0 +1,383 @@
+package handler
+
+import (
+	"goapptemp/internal/adapter/api/rest/response"
+	"goapptemp/internal/adapter/api/rest/serializer"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/domain/service"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+	echo "github.com/labstack/echo/v4"
+)
+
+type WebhookSubscriptionHandler struct {
+	properties
+}
+
+func NewWebhookSubscriptionHandler(properties properties) *WebhookSubscriptionHandler {
+	return &WebhookSubscriptionHandler{
+		properties: properties,
+	}
+}
+
+type CreateWebhookSubscription struct {
+	Name       string   `json:"name"             validate:"required,min=2,max=100"`
+	TargetURL  string   `json:"target_url"       validate:"required,url,max=2048"`
+	Secret     string   `json:"secret,omitempty" validate:"omitempty,min=16,max=255"`
+	EventTypes []string `json:"event_types"      validate:"required,min=1,dive,required"`
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	Subscription CreateWebhookSubscription `json:"subscription" validate:"required"`
+}
+
+func (h *WebhookSubscriptionHandler) CreateWebhookSubscription(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(CreateWebhookSubscriptionRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	subscription, err := h.service.WebhookSubscription().Create(ctx,
+		&service.CreateWebhookSubscriptionRequest{
+			AuthParams: &authArg,
+			Subscription: &entity.WebhookSubscription{
+				Name:       req.Subscription.Name,
+				TargetURL:  req.Subscription.TargetURL,
+				Secret:     req.Subscription.Secret,
+				EventTypes: req.Subscription.EventTypes,
+			},
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeWebhookSubscription(subscription)
+	data.Secret = subscription.Secret
+
+	return response.Success(c, "Create webhook subscription success", data)
+}
+
+type FilterWebhookSubscriptionRequest struct {
+	IDs      []uint `validate:"omitempty,dive,gt=0"     query:"ids"`
+	IsActive *bool  `validate:"omitempty"               query:"is_active"`
+	Search   string `validate:"omitempty,min=1"         query:"search"`
+	Page     int    `validate:"omitempty,min=1"         query:"page"`
+	PerPage  int    `validate:"omitempty,min=1,max=100" query:"per_page"`
+}
+
+func (h *WebhookSubscriptionHandler) FindWebhookSubscriptions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(FilterWebhookSubscriptionRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	if req.PerPage <= 0 {
+		req.PerPage = 10
+	} else if req.PerPage > 100 {
+		req.PerPage = 100
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid query parameters")
+	}
+
+	subscriptions, totalCount, err := h.service.WebhookSubscription().Find(ctx,
+		&service.FindWebhookSubscriptionsRequest{
+			AuthParams: &authArg,
+			Filter: &mysqlrepository.FilterWebhookSubscriptionPayload{
+				IDs:      req.IDs,
+				IsActive: req.IsActive,
+				Search:   req.Search,
+				Page:     req.Page,
+				PerPage:  req.PerPage,
+			},
+		})
+	if err != nil {
+		return err
+	}
+
+	list := serializer.SerializeWebhookSubscriptions(subscriptions)
+
+	pagination := response.Pagination{
+		Page:       req.Page,
+		PerPage:    req.PerPage,
+		TotalCount: totalCount,
+		TotalPage:  0,
+	}
+	if req.PerPage > 0 {
+		pagination.TotalPage = (totalCount + req.PerPage - 1) / req.PerPage
+	}
+
+	return response.Paginate(c, "Find webhook subscriptions success", list, pagination)
+}
+
+func (h *WebhookSubscriptionHandler) FindOneWebhookSubscription(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	subscription, err := h.service.WebhookSubscription().FindOne(ctx,
+		&service.FindOneWebhookSubscriptionRequest{
+			AuthParams:     &authArg,
+			SubscriptionID: id,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeWebhookSubscription(subscription)
+
+	return response.Success(c, "Find webhook subscription success", data)
+}
+
+type UpdateWebhookSubscription struct {
+	ID         uint     `validate:"required,gt=0"     param:"id"`
+	Name       *string  `json:"name,omitempty"        validate:"omitempty,min=2,max=100"`
+	TargetURL  *string  `json:"target_url,omitempty"  validate:"omitempty,url,max=2048"`
+	Secret     *string  `json:"secret,omitempty"      validate:"omitempty,min=16,max=255"`
+	EventTypes []string `json:"event_types,omitempty" validate:"omitempty,min=1,dive,required"`
+	IsActive   *bool    `json:"is_active,omitempty"`
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	Subscription UpdateWebhookSubscription `json:"subscription" validate:"required"`
+}
+
+func (h *WebhookSubscriptionHandler) UpdateWebhookSubscription(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(UpdateWebhookSubscriptionRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind data")
+	}
+
+	shared.Sanitize(req, nil)
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	req.Subscription.ID = id
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Request validation failed")
+	}
+
+	subscription, err := h.service.WebhookSubscription().Update(ctx,
+		&service.UpdateWebhookSubscriptionRequest{
+			AuthParams: &authArg,
+			Update: &mysqlrepository.UpdateWebhookSubscriptionPayload{
+				ID:         req.Subscription.ID,
+				Name:       req.Subscription.Name,
+				TargetURL:  req.Subscription.TargetURL,
+				Secret:     req.Subscription.Secret,
+				EventTypes: req.Subscription.EventTypes,
+				IsActive:   req.Subscription.IsActive,
+			},
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeWebhookSubscription(subscription)
+
+	return response.Success(c, "Update webhook subscription success", data)
+}
+
+func (h *WebhookSubscriptionHandler) DeleteWebhookSubscription(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	err = h.service.WebhookSubscription().Delete(ctx,
+		&service.DeleteWebhookSubscriptionRequest{
+			AuthParams:     &authArg,
+			SubscriptionID: id,
+		})
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, "Delete webhook subscription success", nil)
+}
+
+type FilterWebhookDeliveryRequest struct {
+	Statuses   []string `validate:"omitempty,dive,oneof=pending processing succeeded failed cancelled" query:"statuses"`
+	EventID    string   `validate:"omitempty,max=64"                                                   query:"event_id"`
+	EventTypes []string `validate:"omitempty,dive,required"                                            query:"event_types"`
+	Page       int      `validate:"omitempty,min=1"                                                    query:"page"`
+	PerPage    int      `validate:"omitempty,min=1,max=100"                                            query:"per_page"`
+}
+
+func (h *WebhookSubscriptionHandler) FindWebhookDeliveries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	req := new(FilterWebhookDeliveryRequest)
+	if err := c.Bind(req); err != nil {
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to bind parameters")
+	}
+
+	shared.Sanitize(req, nil)
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	if req.PerPage <= 0 {
+		req.PerPage = 10
+	} else if req.PerPage > 100 {
+		req.PerPage = 100
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return exception.FromValidationErrors(req, validationErrors)
+		}
+
+		return exception.Wrap(err, exception.TypeBadRequest, exception.CodeValidationFailed, "Invalid query parameters")
+	}
+
+	deliveries, totalCount, err := h.service.WebhookSubscription().FindDeliveries(ctx,
+		&service.FindWebhookDeliveriesRequest{
+			AuthParams:     &authArg,
+			SubscriptionID: id,
+			Filter: &mysqlrepository.FilterWebhookDeliveryPayload{
+				Statuses:   req.Statuses,
+				EventID:    req.EventID,
+				EventTypes: req.EventTypes,
+				Page:       req.Page,
+				PerPage:    req.PerPage,
+			},
+		})
+	if err != nil {
+		return err
+	}
+
+	list := serializer.SerializeWebhookDeliveries(deliveries)
+
+	pagination := response.Pagination{
+		Page:       req.Page,
+		PerPage:    req.PerPage,
+		TotalCount: totalCount,
+		TotalPage:  0,
+	}
+	if req.PerPage > 0 {
+		pagination.TotalPage = (totalCount + req.PerPage - 1) / req.PerPage
+	}
+
+	return response.Paginate(c, "Find webhook deliveries success", list, pagination)
+}
+
+func (h *WebhookSubscriptionHandler) ReplayWebhookDelivery(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	authArg, err := getAuthArg(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseUintParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	deliveryID, parseErr := strconv.ParseUint(c.Param("deliveryId"), 10, 64)
+	if parseErr != nil || deliveryID == 0 {
+		msg := "deliveryId must be a positive integer in URL path"
+		err := exception.Wrap(parseErr, exception.TypeBadRequest, exception.CodeValidationFailed, msg)
+
+		return exception.WithFieldError(err, "deliveryId", msg)
+	}
+
+	delivery, err := h.service.WebhookSubscription().ReplayDelivery(ctx,
+		&service.ReplayWebhookDeliveryRequest{
+			AuthParams:     &authArg,
+			SubscriptionID: id,
+			DeliveryID:     deliveryID,
+		})
+	if err != nil {
+		return err
+	}
+
+	data := serializer.SerializeWebhookDelivery(delivery)
+
+	return response.Success(c, "Replay webhook delivery success", data)
+}
@@ -6,6 +6,9 @@ import (
 	"goapptemp/internal/domain/service"
 	"goapptemp/internal/shared/exception"
 	"goapptemp/pkg/logger"
+	"goapptemp/pkg/metrics"
+	"goapptemp/pkg/ratelimit"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -17,15 +20,27 @@ import (
 	apmecho "go.elastic.co/apm/module/apmechov4/v2"
 )
 
+const (
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
+	headerRateLimitPolicy    = "RateLimit-Policy"
+	headerRetryAfter         = "Retry-After"
+)
+
 func (s *echoServer) setupMiddlewares() {
 	s.echo.Use(middleware.Recover())
 	s.echo.Use(middleware.RequestID())
 	s.echo.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
 		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, constant.HeaderAPIKey},
+		ExposeHeaders: []string{
+			headerRateLimitLimit, headerRateLimitRemaining, headerRateLimitReset, headerRateLimitPolicy, headerRetryAfter,
+		},
 	}))
 	s.echo.Use(s.requestLoggerMiddleware())
+	s.echo.Use(s.metricsMiddleware())
 	s.echo.Use(apmecho.Middleware())
 	s.echo.HTTPErrorHandler = s.httpErrorHandler
 }
@@ -63,6 +78,14 @@ func (s *echoServer) requestLoggerMiddleware() echo.MiddlewareFunc {
 				logEvent = reqLogger.Info()
 			}
 
+			if authParams, ok := c.Get(constant.CtxKeyAuthPayload).(service.AuthParams); ok && authParams.AccessTokenClaims != nil {
+				logEvent = logEvent.Field("user_id", authParams.AccessTokenClaims.UserID)
+
+				if authParams.AccessTokenClaims.Impersonated() {
+					logEvent = logEvent.Field("impersonator_id", authParams.AccessTokenClaims.Act.UserID)
+				}
+			}
+
 			logEvent.
 				Field("protocol", req.Proto).
 				Field("remote_ip", c.RealIP()).
@@ -81,11 +104,40 @@ func (s *echoServer) requestLoggerMiddleware() echo.MiddlewareFunc {
 	}
 }
 
+// metricsMiddleware records each request under its route template. The status of a failed request
+// is derived from the error, since the error handler only writes the response after the chain.
+func (s *echoServer) metricsMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			startTime := time.Now()
+			err := next(c)
+
+			status := c.Response().Status
+			if err != nil && !c.Response().Committed {
+				status = errorStatus(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			metrics.ObserveHTTPRequest(c.Request().Method, route, status, time.Since(startTime))
+
+			return err
+		}
+	}
+}
+
 func (s *echoServer) authMiddleware(autoDenied bool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			authHeader := c.Request().Header.Get(echo.HeaderAuthorization)
 			if authHeader == "" {
+				if apiKey := c.Request().Header.Get(constant.HeaderAPIKey); apiKey != "" {
+					return s.authenticateAPIKey(c, next, apiKey)
+				}
+
 				if autoDenied {
 					return exception.ErrAuthHeaderMissing
 				}
@@ -120,31 +172,101 @@ func (s *echoServer) authMiddleware(autoDenied bool) echo.MiddlewareFunc {
 				return exception.ErrAuthTokenBlacklisted
 			}
 
+			if claims.IssuedAt != nil {
+				revoked, err := s.redis.CheckUserTokenRevoked(ctx, claims.UserID, claims.IssuedAt.Time)
+				if err != nil {
+					return exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to verify token")
+				}
+
+				// Revoking the admin's tokens also ends the impersonation sessions they started.
+				if !revoked && claims.Impersonated() {
+					revoked, err = s.redis.CheckUserTokenRevoked(ctx, claims.Act.UserID, claims.IssuedAt.Time)
+					if err != nil {
+						return exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to verify token")
+					}
+				}
+
+				if revoked {
+					return exception.ErrUserTokensRevoked
+				}
+			}
+
 			authParam := service.AuthParams{
 				AccessToken:       accessToken,
 				AccessTokenClaims: claims,
 			}
 			c.Set(constant.CtxKeyAuthPayload, authParam)
 
+			if claims.Impersonated() {
+				c.SetRequest(c.Request().WithContext(service.WithImpersonator(ctx, claims.Act.UserID)))
+
+				if reqLogger, ok := c.Get(constant.CtxKeySubLogger).(logger.Logger); ok && reqLogger != nil {
+					c.Set(constant.CtxKeySubLogger, reqLogger.Field("impersonator_id", claims.Act.UserID))
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// authenticateAPIKey lets machine clients authenticate with X-API-Key. The key acts as the user who
+// issued it, and its scopes travel in the request context so AuthorizationCheck can enforce them.
+func (s *echoServer) authenticateAPIKey(c echo.Context, next echo.HandlerFunc, rawKey string) error {
+	ctx := c.Request().Context()
+
+	authParam, err := s.apiKeys.Authenticate(ctx, rawKey)
+	if err != nil {
+		return err
+	}
+
+	c.SetRequest(c.Request().WithContext(service.WithAPIKey(ctx, authParam.APIKey)))
+	c.Set(constant.CtxKeyAuthPayload, *authParam)
+
+	return next(c)
+}
+
+// denyImpersonationMiddleware blocks sensitive actions for impersonation tokens. It also inspects an
+// Authorization header on routes that do not run authMiddleware, such as the credential-based
+// password change.
+func (s *echoServer) denyImpersonationMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if authParams, ok := c.Get(constant.CtxKeyAuthPayload).(service.AuthParams); ok {
+				if authParams.AccessTokenClaims.Impersonated() {
+					return exception.ErrImpersonationDenied
+				}
+
+				return next(c)
+			}
+
+			parts := strings.Fields(c.Request().Header.Get(echo.HeaderAuthorization))
+			if len(parts) == 2 && strings.EqualFold(parts[0], constant.TokenType) {
+				if claims, err := s.token.VerifyAccessToken(parts[1]); err == nil && claims.Impersonated() {
+					return exception.ErrImpersonationDenied
+				}
+			}
+
 			return next(c)
 		}
 	}
 }
 
-func (s *echoServer) rateLimitMiddleware() echo.MiddlewareFunc {
+// loginBlockMiddleware rejects IPs currently blocked for repeated failed logins.
+func (s *echoServer) loginBlockMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			ctx := c.Request().Context()
 			ip := c.RealIP()
 
-			ttl, err := s.redis.GetBlockIPTTL(ctx, ip)
-			if err == nil && ttl > 0 {
-				retryAfterSeconds := int(ttl.Seconds())
-				c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+			// Allowlisted ranges are never blocked, even if a block was recorded before they were added.
+			if !s.policy.Allowlisted(ip) {
+				ttl, err := s.redis.GetBlockIPTTL(ctx, ip)
+				if err == nil && ttl > 0 {
+					c.Response().Header().Set(headerRetryAfter, strconv.Itoa(int(math.Ceil(ttl.Seconds()))))
 
-				return c.JSON(http.StatusTooManyRequests, map[string]string{
-					"message": "Too Many Requests",
-				})
+					return exception.New(exception.TypeRateLimitExceeded, exception.CodeRateLimitExceeded, "Too many failed login attempts, try again later")
+				}
 			}
 
 			newCtx := context.WithValue(ctx, constant.CtxKeyRequestIP, ip)
@@ -154,3 +276,68 @@ func (s *echoServer) rateLimitMiddleware() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// rateLimitKeyFunc picks the identity a quota is counted against.
+type rateLimitKeyFunc func(c echo.Context) string
+
+func keyByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// keyByUser counts authenticated requests per user and anonymous ones per IP, so it must run
+// after authMiddleware.
+func keyByUser(c echo.Context) string {
+	if authParams, ok := c.Get(constant.CtxKeyAuthPayload).(service.AuthParams); ok && authParams.AccessTokenClaims != nil {
+		return "user:" + strconv.FormatUint(uint64(authParams.AccessTokenClaims.UserID), 10)
+	}
+
+	return keyByIP(c)
+}
+
+// keyByClient gives every API key its own budget, separate from its owner's interactive use, and
+// otherwise falls back to keyByUser.
+func keyByClient(c echo.Context) string {
+	if authParams, ok := c.Get(constant.CtxKeyAuthPayload).(service.AuthParams); ok && authParams.APIKey != nil {
+		return "key:" + strconv.FormatUint(uint64(authParams.APIKey.ID), 10)
+	}
+
+	return keyByUser(c)
+}
+
+// rateLimitMiddleware spends one request of quota for the caller chosen by key and advertises the
+// remaining budget in the RateLimit-* headers. When Redis is unavailable requests are let through.
+func (s *echoServer) rateLimitMiddleware(quota ratelimit.Quota, key rateLimitKeyFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if s.config.RateLimit.Disabled {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			result, err := s.limiter.Allow(c.Request().Context(), quota, key(c))
+			if err != nil {
+				log, ok := c.Get(constant.CtxKeySubLogger).(logger.Logger)
+				if !ok || log == nil {
+					log = s.logger
+				}
+
+				log.Warn().Err(err).Field("quota", quota.Name).Msg("Rate limit check failed, allowing request")
+
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(headerRateLimitLimit, strconv.Itoa(result.Limit))
+			header.Set(headerRateLimitRemaining, strconv.Itoa(result.Remaining))
+			header.Set(headerRateLimitReset, strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds()))))
+			header.Set(headerRateLimitPolicy, quota.Policy())
+
+			if !result.Allowed {
+				header.Set(headerRetryAfter, strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+
+				return exception.New(exception.TypeRateLimitExceeded, exception.CodeRateLimitExceeded, "Too many requests, try again later")
+			}
+
+			return next(c)
+		}
+	}
+}
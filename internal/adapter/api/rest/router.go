@@ -1,8 +1,29 @@
 package rest
 
+import (
+	"goapptemp/pkg/metrics"
+	"goapptemp/pkg/ratelimit"
+	"time"
+
+	echo "github.com/labstack/echo/v4"
+)
+
 func (s *echoServer) setupRouter() {
+	// authQuota guards the unauthenticated auth endpoints per IP, on top of the failed-login blocks.
+	authQuota := ratelimit.Quota{Name: "auth", Limit: 30, Window: time.Minute, Algorithm: ratelimit.SlidingWindow}
+	// passwordResetQuota keeps reset emails from being triggered in bulk from one IP.
+	passwordResetQuota := ratelimit.Quota{Name: "password_reset", Limit: 5, Window: 15 * time.Minute, Algorithm: ratelimit.SlidingWindow}
+	// apiQuota is the general budget per API key, user or anonymous IP; the bucket absorbs short bursts.
+	apiQuota := ratelimit.Quota{Name: "api", Limit: 300, Window: time.Minute, Algorithm: ratelimit.TokenBucket, Burst: 100}
+	// heavyQuota covers endpoints that parse uploads or write many rows in one request.
+	heavyQuota := ratelimit.Quota{Name: "heavy", Limit: 10, Window: time.Minute, Algorithm: ratelimit.SlidingWindow}
+
 	s.echo.GET("/ping", s.handler.Health().CheckHealth)
 
+	if s.config.HTTP.EnableMetrics {
+		s.echo.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+	}
+
 	if s.config.HTTP.EnableMigrationAPI {
 		migrationGroup := s.echo.Group("/sql/migration")
 		{
@@ -17,73 +38,166 @@ func (s *echoServer) setupRouter() {
 	apiV1 := s.echo.Group("/api/v1")
 	{
 		authGroup := apiV1.Group("/auth")
+		authGroup.Use(s.rateLimitMiddleware(authQuota, keyByIP))
 		{
-			authGroup.POST("/login", s.handler.Auth().Login, s.rateLimitMiddleware())
+			authGroup.POST("/login", s.handler.Auth().Login, s.loginBlockMiddleware())
 			authGroup.POST("/refresh", s.handler.Auth().Refresh)
 			authGroup.POST("/logout", s.handler.Auth().Logout)
-			authGroup.POST("/forget-password", s.handler.Auth().ForgetPassword, s.rateLimitMiddleware())
+			authGroup.POST("/forget-password", s.handler.Auth().ForgetPassword, s.loginBlockMiddleware(), s.rateLimitMiddleware(passwordResetQuota, keyByIP))
 			authGroup.POST("/verify-reset-token", s.handler.Auth().VerifyResetToken)
 			authGroup.POST("/reset-password", s.handler.Auth().ResetPassword)
+			authGroup.POST("/change-password", s.handler.Auth().ChangePassword, s.denyImpersonationMiddleware(), s.loginBlockMiddleware())
+			authGroup.GET("/me", s.handler.Auth().Me, s.authMiddleware(true))
+			authGroup.POST("/invitations/verify", s.handler.Invitation().VerifyInvitation)
+			authGroup.POST("/invitations/accept", s.handler.Invitation().AcceptInvitation)
+			authGroup.GET("/sso/providers", s.handler.SSO().FindSSOProviders)
+			authGroup.GET("/sso/:provider/authorize", s.handler.SSO().AuthorizeSSO)
+			authGroup.POST("/sso/:provider/callback", s.handler.SSO().CallbackSSO)
 		}
 
 		webhookGroup := apiV1.Group("/webhook")
+		webhookGroup.Use(s.rateLimitMiddleware(apiQuota, keyByIP))
 		{
 			webhookGroup.POST("/update-icon", s.handler.Webhook().UpdateIcon)
 		}
 
 		provinceGroup := apiV1.Group("/provinces")
-		provinceGroup.Use(s.authMiddleware(false))
+		provinceGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
 		{
 			provinceGroup.GET("", s.handler.Province().FindProvinces)
 			provinceGroup.GET("/:id", s.handler.Province().FindOneProvince)
 		}
 
 		cityGroup := apiV1.Group("/cities")
-		cityGroup.Use(s.authMiddleware(false))
+		cityGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
 		{
 			cityGroup.GET("", s.handler.City().FindCities)
 			cityGroup.GET("/:id", s.handler.City().FindOneCity)
 		}
 
 		districtGroup := apiV1.Group("/districts")
-		districtGroup.Use(s.authMiddleware(false))
+		districtGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
 		{
 			districtGroup.GET("", s.handler.District().FindDistricts)
 			districtGroup.GET("/:id", s.handler.District().FindOneDistrict)
 		}
 
 		userGroup := apiV1.Group("/users")
-		userGroup.Use(s.authMiddleware(false))
+		userGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
 		{
 			userGroup.GET("", s.handler.User().FindUsers)
 			userGroup.GET("/:id", s.handler.User().FindOneUser)
 			userGroup.POST("", s.handler.User().CreateUser, s.authMiddleware(true))
 			userGroup.PUT("/:id", s.handler.User().UpdateUser, s.authMiddleware(true))
 			userGroup.DELETE("/:id", s.handler.User().DeleteUser, s.authMiddleware(true))
+			userGroup.GET("/:id/dependencies", s.handler.User().FindUserDependencies)
+			userGroup.POST("/:id/suspend", s.handler.User().SuspendUser, s.authMiddleware(true))
+			userGroup.POST("/:id/reactivate", s.handler.User().ReactivateUser, s.authMiddleware(true))
+			userGroup.POST("/:id/impersonate", s.handler.User().ImpersonateUser, s.authMiddleware(true), s.denyImpersonationMiddleware())
+			userGroup.POST("/invitations", s.handler.Invitation().InviteUser, s.authMiddleware(true))
+			userGroup.POST("/:id/invitation", s.handler.Invitation().ResendInvitation, s.authMiddleware(true))
+			userGroup.DELETE("/:id/invitation", s.handler.Invitation().RevokeInvitation, s.authMiddleware(true))
+			userGroup.GET("/template/import", s.handler.Invitation().TemplateImportUsers)
+			userGroup.POST("/import/preview", s.handler.Invitation().ImportPreviewUsers, s.rateLimitMiddleware(heavyQuota, keyByUser))
+			userGroup.POST("/import", s.handler.Invitation().ImportUsers, s.authMiddleware(true), s.rateLimitMiddleware(heavyQuota, keyByUser))
+			userGroup.POST("/bulk/delete", s.handler.User().BulkDeleteUsers, s.authMiddleware(true), s.rateLimitMiddleware(heavyQuota, keyByUser))
+			userGroup.POST("/bulk/activate", s.handler.User().BulkActivateUsers, s.authMiddleware(true), s.rateLimitMiddleware(heavyQuota, keyByUser))
+			userGroup.POST("/bulk/deactivate", s.handler.User().BulkDeactivateUsers, s.authMiddleware(true), s.rateLimitMiddleware(heavyQuota, keyByUser))
+			userGroup.POST("/bulk/roles", s.handler.User().BulkAssignUserRoles, s.authMiddleware(true), s.rateLimitMiddleware(heavyQuota, keyByUser))
 		}
 
 		roleGroup := apiV1.Group("/roles")
-		roleGroup.Use(s.authMiddleware(false))
+		roleGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
 		{
 			roleGroup.POST("", s.handler.Role().CreateRole)
 			roleGroup.GET("", s.handler.Role().FindRoles)
 			roleGroup.GET("/:id", s.handler.Role().FindOneRole)
 			roleGroup.PUT("/:id", s.handler.Role().UpdateRole)
 			roleGroup.DELETE("/:id", s.handler.Role().DeleteRole)
+			roleGroup.GET("/:id/permissions", s.handler.Role().FindRolePermissions)
+			roleGroup.GET("/:id/dependencies", s.handler.Role().FindRoleDependencies)
+			roleGroup.POST("/bulk/delete", s.handler.Role().BulkDeleteRoles, s.rateLimitMiddleware(heavyQuota, keyByUser))
+		}
+
+		permissionGroup := apiV1.Group("/permissions")
+		permissionGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
+		{
+			permissionGroup.GET("", s.handler.Permission().FindPermissions)
+			permissionGroup.GET("/groups", s.handler.Permission().FindPermissionGroups)
+			permissionGroup.GET("/:id", s.handler.Permission().FindOnePermission)
+		}
+
+		webhookSubscriptionGroup := apiV1.Group("/webhook-subscriptions")
+		webhookSubscriptionGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
+		{
+			webhookSubscriptionGroup.POST("", s.handler.WebhookSubscription().CreateWebhookSubscription)
+			webhookSubscriptionGroup.GET("", s.handler.WebhookSubscription().FindWebhookSubscriptions)
+			webhookSubscriptionGroup.GET("/:id", s.handler.WebhookSubscription().FindOneWebhookSubscription)
+			webhookSubscriptionGroup.PUT("/:id", s.handler.WebhookSubscription().UpdateWebhookSubscription)
+			webhookSubscriptionGroup.DELETE("/:id", s.handler.WebhookSubscription().DeleteWebhookSubscription)
+			webhookSubscriptionGroup.GET("/:id/deliveries", s.handler.WebhookSubscription().FindWebhookDeliveries)
+			webhookSubscriptionGroup.POST("/:id/deliveries/:deliveryId/replay", s.handler.WebhookSubscription().ReplayWebhookDelivery)
+		}
+
+		jobGroup := apiV1.Group("/jobs")
+		jobGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
+		{
+			jobGroup.GET("", s.handler.Job().FindJobs)
+			jobGroup.GET("/:name/runs", s.handler.Job().FindJobRuns)
+			jobGroup.POST("/:name/trigger", s.handler.Job().TriggerJob, s.rateLimitMiddleware(heavyQuota, keyByUser))
+		}
+
+		emailTemplateGroup := apiV1.Group("/email-templates")
+		emailTemplateGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
+		{
+			emailTemplateGroup.GET("", s.handler.EmailTemplate().FindEmailTemplates)
+			emailTemplateGroup.GET("/:name/preview", s.handler.EmailTemplate().PreviewEmailTemplate)
+			emailTemplateGroup.POST("/:name/preview", s.handler.EmailTemplate().PreviewEmailTemplate)
+		}
+
+		lockoutGroup := apiV1.Group("/lockouts")
+		lockoutGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
+		{
+			lockoutGroup.GET("/users", s.handler.Lockout().FindLockedUsers)
+			lockoutGroup.DELETE("/users/:username", s.handler.Lockout().UnlockUser)
+			lockoutGroup.GET("/ips", s.handler.Lockout().FindBlockedIPs)
+			lockoutGroup.DELETE("/ips/:ip", s.handler.Lockout().UnblockIP)
+		}
+
+		notificationGroup := apiV1.Group("/notifications")
+		notificationGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
+		{
+			notificationGroup.GET("", s.handler.Notification().FindNotifications)
+			notificationGroup.GET("/unread-count", s.handler.Notification().CountUnreadNotifications)
+			notificationGroup.POST("/read", s.handler.Notification().MarkNotificationsRead)
+			notificationGroup.POST("/read-all", s.handler.Notification().MarkAllNotificationsRead)
+			notificationGroup.POST("/:id/read", s.handler.Notification().MarkNotificationRead)
+			notificationGroup.GET("/preferences", s.handler.Notification().FindNotificationPreferences)
+			notificationGroup.PUT("/preferences", s.handler.Notification().UpdateNotificationPreferences)
+		}
+
+		apiKeyGroup := apiV1.Group("/api-keys")
+		apiKeyGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
+		{
+			apiKeyGroup.POST("", s.handler.APIKey().CreateAPIKey, s.denyImpersonationMiddleware())
+			apiKeyGroup.GET("", s.handler.APIKey().FindAPIKeys)
+			apiKeyGroup.GET("/:id", s.handler.APIKey().FindOneAPIKey)
+			apiKeyGroup.DELETE("/:id", s.handler.APIKey().RevokeAPIKey)
 		}
 
 		supportFeatureGroup := apiV1.Group("/help-services")
-		supportFeatureGroup.Use(s.authMiddleware(false))
+		supportFeatureGroup.Use(s.authMiddleware(false), s.rateLimitMiddleware(apiQuota, keyByClient))
 		{
 			supportFeatureGroup.POST("", s.handler.SupportFeature().CreateSupportFeature)
-			supportFeatureGroup.POST("/bulk", s.handler.SupportFeature().BulkCreateSupportFeatures)
+			supportFeatureGroup.POST("/bulk", s.handler.SupportFeature().BulkCreateSupportFeatures, s.rateLimitMiddleware(heavyQuota, keyByUser))
 			supportFeatureGroup.GET("", s.handler.SupportFeature().FindSupportFeatures)
 			supportFeatureGroup.GET("/:id", s.handler.SupportFeature().FindOneSupportFeature)
 			supportFeatureGroup.PUT("/:id", s.handler.SupportFeature().UpdateSupportFeature)
 			supportFeatureGroup.DELETE("/:id", s.handler.SupportFeature().DeleteSupportFeature)
 			supportFeatureGroup.GET("/:id/is-deletable", s.handler.SupportFeature().IsSupportFeatureDeletable)
+			supportFeatureGroup.GET("/:id/dependencies", s.handler.SupportFeature().FindSupportFeatureDependencies)
 			supportFeatureGroup.GET("/template/import", s.handler.SupportFeature().TemplateImportSupportFeature)
-			supportFeatureGroup.POST("/import/preview", s.handler.SupportFeature().ImportPreviewSupportFeature)
+			supportFeatureGroup.POST("/import/preview", s.handler.SupportFeature().ImportPreviewSupportFeature, s.rateLimitMiddleware(heavyQuota, keyByUser))
 		}
 	}
 }
@@ -0,0 +1,74 @@
+package serializer
+
+import (
+	"goapptemp/internal/domain/entity"
+	"time"
+)
+
+type APIKeyResponseData struct {
+	ID          uint     `json:"id"`
+	CompanyID   uint     `json:"company_id"`
+	UserID      uint     `json:"user_id"`
+	Name        string   `json:"name"`
+	Prefix      string   `json:"prefix"`
+	Key         string   `json:"key,omitempty"`
+	Permissions []string `json:"permissions"`
+	ExpiresAt   *string  `json:"expires_at,omitempty"`
+	LastUsedAt  *string  `json:"last_used_at,omitempty"`
+	RevokedAt   *string  `json:"revoked_at,omitempty"`
+	CreatedAt   string   `json:"created_at,omitempty"`
+	UpdatedAt   string   `json:"updated_at,omitempty"`
+}
+
+// SerializeAPIKey never includes the key itself; callers add it only when it is first issued.
+func SerializeAPIKey(arg *entity.APIKey) *APIKeyResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	res := &APIKeyResponseData{
+		ID:          arg.ID,
+		CompanyID:   arg.CompanyID,
+		UserID:      arg.UserID,
+		Name:        arg.Name,
+		Prefix:      arg.Prefix,
+		Permissions: arg.Permissions,
+		CreatedAt:   arg.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   arg.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if arg.ExpiresAt != nil {
+		expiresAt := arg.ExpiresAt.Format(time.RFC3339)
+		res.ExpiresAt = &expiresAt
+	}
+
+	if arg.LastUsedAt != nil {
+		lastUsedAt := arg.LastUsedAt.Format(time.RFC3339)
+		res.LastUsedAt = &lastUsedAt
+	}
+
+	if arg.RevokedAt != nil {
+		revokedAt := arg.RevokedAt.Format(time.RFC3339)
+		res.RevokedAt = &revokedAt
+	}
+
+	return res
+}
+
+func SerializeAPIKeys(arg []*entity.APIKey) []*APIKeyResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*APIKeyResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, SerializeAPIKey(arg[i]))
+	}
+
+	return res
+}
@@ -0,0 +1,61 @@
+package serializer
+
+import (
+	"goapptemp/internal/domain/service"
+	"goapptemp/internal/shared/exception"
+)
+
+type BulkErrorResponseData struct {
+	Type    exception.ErrorType   `json:"type"`
+	Code    string                `json:"code,omitempty"`
+	Message string                `json:"message"`
+	Errors  exception.FieldErrors `json:"errors,omitempty"`
+}
+
+type BulkResultResponseData struct {
+	ID      uint                   `json:"id"`
+	Success bool                   `json:"success"`
+	Error   *BulkErrorResponseData `json:"error,omitempty"`
+}
+
+func SerializeBulkResult(arg *service.BulkResult) *BulkResultResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	res := &BulkResultResponseData{
+		ID:      arg.ID,
+		Success: arg.Err == nil,
+	}
+
+	if arg.Err != nil {
+		res.Error = &BulkErrorResponseData{
+			Type:    exception.TypeInternalError,
+			Code:    exception.CodeInternalError,
+			Message: "Internal server error",
+		}
+
+		if ex, ok := exception.GetException(arg.Err); ok {
+			res.Error.Type = ex.Type
+			res.Error.Code = ex.Code
+			res.Error.Message = ex.Message
+			res.Error.Errors = ex.Errors
+		}
+	}
+
+	return res
+}
+
+func SerializeBulkResults(arg []*service.BulkResult) []*BulkResultResponseData {
+	res := make([]*BulkResultResponseData, 0, len(arg))
+
+	for _, item := range arg {
+		if item == nil {
+			continue
+		}
+
+		res = append(res, SerializeBulkResult(item))
+	}
+
+	return res
+}
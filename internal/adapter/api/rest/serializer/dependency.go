@@ -0,0 +1,44 @@
+package serializer
+
+import "goapptemp/internal/domain/entity"
+
+type DependencyResponseData struct {
+	Table         string `json:"table"`
+	Column        string `json:"column"`
+	Count         int    `json:"count"`
+	SampleIDs     []uint `json:"sample_ids"`
+	SoftDeletable bool   `json:"soft_deletable"`
+}
+
+func SerializeDependency(arg *entity.Dependency) *DependencyResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	sampleIDs := arg.SampleIDs
+	if sampleIDs == nil {
+		sampleIDs = []uint{}
+	}
+
+	return &DependencyResponseData{
+		Table:         arg.Table,
+		Column:        arg.Column,
+		Count:         arg.Count,
+		SampleIDs:     sampleIDs,
+		SoftDeletable: arg.SoftDeletable,
+	}
+}
+
+func SerializeDependencies(arg []*entity.Dependency) []*DependencyResponseData {
+	res := make([]*DependencyResponseData, 0, len(arg))
+
+	for _, item := range arg {
+		if item == nil {
+			continue
+		}
+
+		res = append(res, SerializeDependency(item))
+	}
+
+	return res
+}
@@ -0,0 +1,65 @@
+package serializer
+
+import (
+	"goapptemp/pkg/mailtemplate"
+)
+
+type EmailTemplateResponseData struct {
+	Name    string         `json:"name"`
+	Locales []string       `json:"locales"`
+	Sample  map[string]any `json:"sample,omitempty"`
+}
+
+func SerializeEmailTemplate(arg *mailtemplate.TemplateInfo) *EmailTemplateResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	return &EmailTemplateResponseData{
+		Name:    arg.Name,
+		Locales: arg.Locales,
+		Sample:  arg.Sample,
+	}
+}
+
+func SerializeEmailTemplates(arg []*mailtemplate.TemplateInfo) []*EmailTemplateResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*EmailTemplateResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, SerializeEmailTemplate(arg[i]))
+	}
+
+	return res
+}
+
+type EmailTemplatePreviewResponseData struct {
+	Name    string `json:"name"`
+	Locale  string `json:"locale"`
+	Subject string `json:"subject"`
+	Summary string `json:"summary,omitempty"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+func SerializeEmailTemplatePreview(arg *mailtemplate.Rendered) *EmailTemplatePreviewResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	return &EmailTemplatePreviewResponseData{
+		Name:    arg.Name,
+		Locale:  arg.Locale,
+		Subject: arg.Subject,
+		Summary: arg.Summary,
+		HTML:    arg.HTML,
+		Text:    arg.Text,
+	}
+}
@@ -0,0 +1,110 @@
+package serializer
+
+import (
+	"goapptemp/pkg/scheduler"
+	"time"
+)
+
+type JobResponseData struct {
+	Name           string              `json:"name"`
+	Description    string              `json:"description"`
+	Schedule       string              `json:"schedule"`
+	TimeoutSeconds int64               `json:"timeout_seconds"`
+	NextRunAt      *string             `json:"next_run_at,omitempty"`
+	Running        bool                `json:"running"`
+	LastRun        *JobRunResponseData `json:"last_run,omitempty"`
+}
+
+func SerializeJob(arg *scheduler.JobInfo) *JobResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	res := &JobResponseData{
+		Name:           arg.Name,
+		Description:    arg.Description,
+		Schedule:       arg.Schedule,
+		TimeoutSeconds: int64(arg.Timeout / time.Second),
+		Running:        arg.Running,
+		LastRun:        SerializeJobRun(arg.LastRun),
+	}
+
+	if arg.NextRunAt != nil {
+		nextRunAt := arg.NextRunAt.Format(time.RFC3339)
+		res.NextRunAt = &nextRunAt
+	}
+
+	return res
+}
+
+func SerializeJobs(arg []*scheduler.JobInfo) []*JobResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*JobResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, SerializeJob(arg[i]))
+	}
+
+	return res
+}
+
+type JobRunResponseData struct {
+	ID         string  `json:"id"`
+	Job        string  `json:"job"`
+	Trigger    string  `json:"trigger"`
+	Status     string  `json:"status"`
+	Node       string  `json:"node"`
+	StartedAt  string  `json:"started_at"`
+	FinishedAt *string `json:"finished_at,omitempty"`
+	DurationMs int64   `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+func SerializeJobRun(arg *scheduler.Run) *JobRunResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	res := &JobRunResponseData{
+		ID:         arg.ID,
+		Job:        arg.Job,
+		Trigger:    string(arg.Trigger),
+		Status:     string(arg.Status),
+		Node:       arg.Node,
+		StartedAt:  arg.StartedAt.Format(time.RFC3339),
+		DurationMs: arg.DurationMs,
+		Error:      arg.Error,
+	}
+
+	if arg.FinishedAt != nil {
+		finishedAt := arg.FinishedAt.Format(time.RFC3339)
+		res.FinishedAt = &finishedAt
+	}
+
+	return res
+}
+
+func SerializeJobRuns(arg []*scheduler.Run) []*JobRunResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*JobRunResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, SerializeJobRun(arg[i]))
+	}
+
+	return res
+}
@@ -0,0 +1,56 @@
+package serializer
+
+import (
+	"goapptemp/internal/domain/entity"
+	"math"
+	"time"
+)
+
+type LoginBlockResponseData struct {
+	Identifier string `json:"identifier"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+	ExpiresAt  string `json:"expires_at"`
+	Level      int    `json:"level,omitempty"`
+	UserID     *uint  `json:"user_id,omitempty"`
+	Fullname   string `json:"fullname,omitempty"`
+	Email      string `json:"email,omitempty"`
+}
+
+func SerializeLoginBlock(arg *entity.LoginBlock) *LoginBlockResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	res := &LoginBlockResponseData{
+		Identifier: arg.Identifier,
+		TTLSeconds: int64(math.Ceil(arg.TTL.Seconds())),
+		ExpiresAt:  arg.ExpiresAt.Format(time.RFC3339),
+		Level:      arg.Level,
+	}
+
+	if arg.User != nil {
+		res.UserID = &arg.User.ID
+		res.Fullname = arg.User.Fullname
+		res.Email = arg.User.Email
+	}
+
+	return res
+}
+
+func SerializeLoginBlocks(arg []*entity.LoginBlock) []*LoginBlockResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*LoginBlockResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, SerializeLoginBlock(arg[i]))
+	}
+
+	return res
+}
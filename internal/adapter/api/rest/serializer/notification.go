@@ -0,0 +1,89 @@
+package serializer
+
+import (
+	"goapptemp/internal/domain/entity"
+	"time"
+)
+
+type NotificationResponseData struct {
+	ID        uint64         `json:"id"`
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Body      string         `json:"body"`
+	Data      map[string]any `json:"data,omitempty"`
+	IsRead    bool           `json:"is_read"`
+	ReadAt    *string        `json:"read_at,omitempty"`
+	CreatedAt string         `json:"created_at,omitempty"`
+}
+
+func SerializeNotification(arg *entity.Notification) *NotificationResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	res := &NotificationResponseData{
+		ID:        arg.ID,
+		Type:      string(arg.Type),
+		Title:     arg.Title,
+		Body:      arg.Body,
+		Data:      arg.Data,
+		IsRead:    arg.ReadAt != nil,
+		CreatedAt: arg.CreatedAt.Format(time.RFC3339),
+	}
+
+	if arg.ReadAt != nil {
+		readAt := arg.ReadAt.Format(time.RFC3339)
+		res.ReadAt = &readAt
+	}
+
+	return res
+}
+
+func SerializeNotifications(arg []*entity.Notification) []*NotificationResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*NotificationResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, SerializeNotification(arg[i]))
+	}
+
+	return res
+}
+
+type NotificationPreferenceResponseData struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+	Enabled bool   `json:"enabled"`
+	// Required channels cannot be turned off by the user.
+	Required bool `json:"required"`
+}
+
+func SerializeNotificationPreferences(arg []*entity.NotificationPreference) []*NotificationPreferenceResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*NotificationPreferenceResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, &NotificationPreferenceResponseData{
+			Type:     string(arg[i].Type),
+			Channel:  string(arg[i].Channel),
+			Enabled:  arg[i].Enabled,
+			Required: arg[i].Type.Required(arg[i].Channel),
+		})
+	}
+
+	return res
+}
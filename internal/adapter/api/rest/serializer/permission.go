@@ -46,3 +46,25 @@ func SerializePermissions(arg []*entity.Permission) []*PermissionResponseData {
 
 	return res
 }
+
+type PermissionGroupResponseData struct {
+	Module      string                    `json:"module"`
+	Permissions []*PermissionResponseData `json:"permissions"`
+}
+
+func SerializePermissionGroups(arg []*entity.PermissionGroup) []*PermissionGroupResponseData {
+	res := make([]*PermissionGroupResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, &PermissionGroupResponseData{
+			Module:      arg[i].Module,
+			Permissions: SerializePermissions(arg[i].Permissions),
+		})
+	}
+
+	return res
+}
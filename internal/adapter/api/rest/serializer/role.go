@@ -13,6 +13,8 @@ type RoleResponseData struct {
 	Name          string                    `json:"name"`
 	Description   *string                   `json:"description,omitempty"`
 	SuperAdmin    bool                      `json:"super_admin"`
+	ParentID      *uint                     `json:"parent_id"`
+	CompanyID     *uint                     `json:"company_id"`
 	CreatedAt     string                    `json:"created_at,omitempty"`
 	UpdatedAt     string                    `json:"updated_at,omitempty"`
 }
@@ -30,6 +32,8 @@ func SerializeRole(arg *entity.Role) *RoleResponseData {
 		Name:          arg.Name,
 		Description:   arg.Description,
 		SuperAdmin:    arg.SuperAdmin,
+		ParentID:      arg.ParentID,
+		CompanyID:     arg.CompanyID,
 		CreatedAt:     arg.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:     arg.UpdatedAt.Format(time.RFC3339),
 	}
@@ -52,3 +56,42 @@ func SerializeRoles(arg []*entity.Role) []*RoleResponseData {
 
 	return res
 }
+
+type EffectivePermissionResponseData struct {
+	*PermissionResponseData
+	SourceRoleID uint `json:"source_role_id"`
+	Inherited    bool `json:"inherited"`
+}
+
+type RolePermissionsResponseData struct {
+	RoleID    uint                               `json:"role_id"`
+	Direct    []*PermissionResponseData          `json:"direct"`
+	Effective []*EffectivePermissionResponseData `json:"effective"`
+}
+
+func SerializeRolePermissions(roleID uint, arg []*entity.EffectivePermission) *RolePermissionsResponseData {
+	res := &RolePermissionsResponseData{
+		RoleID:    roleID,
+		Direct:    make([]*PermissionResponseData, 0, len(arg)),
+		Effective: make([]*EffectivePermissionResponseData, 0, len(arg)),
+	}
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		permission := SerializePermission(arg[i].Permission)
+		if !arg[i].Inherited {
+			res.Direct = append(res.Direct, permission)
+		}
+
+		res.Effective = append(res.Effective, &EffectivePermissionResponseData{
+			PermissionResponseData: permission,
+			SourceRoleID:           arg[i].SourceRoleID,
+			Inherited:              arg[i].Inherited,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,29 @@
+package serializer
+
+import "goapptemp/internal/domain/entity"
+
+type SSOProviderResponseData struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+func SerializeSSOProviders(arg []*entity.SSOProvider) []*SSOProviderResponseData {
+	res := make([]*SSOProviderResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, &SSOProviderResponseData{
+			Name:        arg[i].Name,
+			DisplayName: arg[i].DisplayName,
+		})
+	}
+
+	return res
+}
+
+type SSOAuthorizeResponseData struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
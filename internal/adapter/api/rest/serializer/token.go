@@ -8,8 +8,8 @@ import (
 type TokenResponseData struct {
 	AccessToken           string `json:"access_token"`
 	AccessTokenExpiresAt  string `json:"access_token_expires_at"`
-	RefreshToken          string `json:"refresh_token"`
-	RefreshTokenExpiresAt string `json:"refresh_token_expires_at"`
+	RefreshToken          string `json:"refresh_token,omitempty"`
+	RefreshTokenExpiresAt string `json:"refresh_token_expires_at,omitempty"`
 	TokenType             string `json:"token_type"`
 }
 
@@ -18,13 +18,19 @@ func SerializeToken(arg *entity.Token) *TokenResponseData {
 		return nil
 	}
 
-	return &TokenResponseData{
-		AccessToken:           arg.AccessToken,
-		AccessTokenExpiresAt:  arg.AccessTokenExpiresAt.Format(time.RFC3339),
-		RefreshToken:          arg.RefreshToken,
-		RefreshTokenExpiresAt: arg.RefreshTokenExpiresAt.Format(time.RFC3339),
-		TokenType:             arg.TokenType,
+	res := &TokenResponseData{
+		AccessToken:          arg.AccessToken,
+		AccessTokenExpiresAt: arg.AccessTokenExpiresAt.Format(time.RFC3339),
+		TokenType:            arg.TokenType,
 	}
+
+	// Impersonation tokens come without a refresh token.
+	if arg.RefreshToken != "" {
+		res.RefreshToken = arg.RefreshToken
+		res.RefreshTokenExpiresAt = arg.RefreshTokenExpiresAt.Format(time.RFC3339)
+	}
+
+	return res
 }
 
 func SerializeTokens(arg []*entity.Token) []*TokenResponseData {
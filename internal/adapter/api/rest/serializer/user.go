@@ -2,19 +2,25 @@ package serializer
 
 import (
 	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/domain/service"
 	"time"
 )
 
 type UserResponseData struct {
-	ID        uint                `json:"id"`
-	RoleIDs   []uint              `json:"role_ids"`
-	Roles     []*RoleResponseData `json:"roles"`
-	Email     string              `json:"email"`
-	Username  string              `json:"username"`
-	Fullname  string              `json:"fullname"`
-	Token     *TokenResponseData  `json:"token,omitempty"`
-	CreatedAt string              `json:"created_at,omitempty"`
-	UpdatedAt string              `json:"updated_at,omitempty"`
+	ID               uint                       `json:"id"`
+	RoleIDs          []uint                     `json:"role_ids"`
+	Roles            []*RoleResponseData        `json:"roles"`
+	Email            string                     `json:"email"`
+	Username         string                     `json:"username"`
+	Fullname         string                     `json:"fullname"`
+	Locale           string                     `json:"locale"`
+	Status           string                     `json:"status"`
+	SuspendedAt      string                     `json:"suspended_at,omitempty"`
+	SuspensionReason string                     `json:"suspension_reason,omitempty"`
+	Token            *TokenResponseData         `json:"token,omitempty"`
+	Impersonation    *ImpersonationResponseData `json:"impersonation,omitempty"`
+	CreatedAt        string                     `json:"created_at,omitempty"`
+	UpdatedAt        string                     `json:"updated_at,omitempty"`
 }
 
 func SerializeUser(arg *entity.User) *UserResponseData {
@@ -22,17 +28,54 @@ func SerializeUser(arg *entity.User) *UserResponseData {
 		return nil
 	}
 
+	var suspendedAt string
+	if arg.SuspendedAt != nil {
+		suspendedAt = arg.SuspendedAt.Format(time.RFC3339)
+	}
+
 	return &UserResponseData{
-		ID:        arg.ID,
-		RoleIDs:   arg.RoleIDs,
-		Roles:     SerializeRoles(arg.Roles),
-		Email:     arg.Email,
-		Username:  arg.Username,
-		Fullname:  arg.Fullname,
-		CreatedAt: arg.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: arg.UpdatedAt.Format(time.RFC3339),
-		Token:     SerializeToken(arg.Token),
+		ID:               arg.ID,
+		RoleIDs:          arg.RoleIDs,
+		Roles:            SerializeRoles(arg.Roles),
+		Email:            arg.Email,
+		Username:         arg.Username,
+		Fullname:         arg.Fullname,
+		Locale:           arg.Locale,
+		Status:           string(arg.Status),
+		SuspendedAt:      suspendedAt,
+		SuspensionReason: arg.SuspensionReason,
+		CreatedAt:        arg.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        arg.UpdatedAt.Format(time.RFC3339),
+		Token:            SerializeToken(arg.Token),
+		Impersonation:    SerializeImpersonation(arg.Impersonation),
+	}
+}
+
+type ImpersonationResponseData struct {
+	Impersonated         bool   `json:"impersonated"`
+	ImpersonatorID       uint   `json:"impersonator_id"`
+	ImpersonatorUsername string `json:"impersonator_username,omitempty"`
+	ImpersonatorFullname string `json:"impersonator_fullname,omitempty"`
+	ExpiresAt            string `json:"expires_at"`
+}
+
+func SerializeImpersonation(arg *entity.Impersonation) *ImpersonationResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	res := &ImpersonationResponseData{
+		Impersonated:   true,
+		ImpersonatorID: arg.ImpersonatorID,
+		ExpiresAt:      arg.ExpiresAt.Format(time.RFC3339),
+	}
+
+	if arg.Impersonator != nil {
+		res.ImpersonatorUsername = arg.Impersonator.Username
+		res.ImpersonatorFullname = arg.Impersonator.Fullname
 	}
+
+	return res
 }
 
 func SerializeUsers(arg []*entity.User) []*UserResponseData {
@@ -52,3 +95,71 @@ func SerializeUsers(arg []*entity.User) []*UserResponseData {
 
 	return res
 }
+
+type UserImportPreviewResponseData struct {
+	Row      int                           `json:"row"`
+	Fullname ValidatableStringResponseData `json:"fullname"`
+	Username ValidatableStringResponseData `json:"username"`
+	Email    ValidatableStringResponseData `json:"email"`
+	Role     ValidatableStringResponseData `json:"role"`
+}
+
+func SerializeUserImportPreview(arg *service.UserImportPreview) *UserImportPreviewResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	return &UserImportPreviewResponseData{
+		Row:      arg.Row,
+		Fullname: ValidatableStringResponseData{Value: arg.Fullname.Value, Message: arg.Fullname.Message},
+		Username: ValidatableStringResponseData{Value: arg.Username.Value, Message: arg.Username.Message},
+		Email:    ValidatableStringResponseData{Value: arg.Email.Value, Message: arg.Email.Message},
+		Role:     ValidatableStringResponseData{Value: arg.Role.Value, Message: arg.Role.Message},
+	}
+}
+
+func SerializeUserImportPreviews(arg []*service.UserImportPreview) []*UserImportPreviewResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*UserImportPreviewResponseData, 0, len(arg))
+
+	for _, item := range arg {
+		if item == nil {
+			continue
+		}
+
+		res = append(res, SerializeUserImportPreview(item))
+	}
+
+	return res
+}
+
+type ImportedUserResponseData struct {
+	*UserResponseData
+	InvitationSent  bool   `json:"invitation_sent"`
+	InvitationError string `json:"invitation_error,omitempty"`
+}
+
+func SerializeImportedUsers(arg []*service.ImportedUser) []*ImportedUserResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*ImportedUserResponseData, 0, len(arg))
+
+	for _, item := range arg {
+		if item == nil || item.User == nil {
+			continue
+		}
+
+		res = append(res, &ImportedUserResponseData{
+			UserResponseData: SerializeUser(item.User),
+			InvitationSent:   item.InvitationSent,
+			InvitationError:  item.InvitationError,
+		})
+	}
+
+	return res
+}
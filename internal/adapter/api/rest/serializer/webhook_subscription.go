@@ -0,0 +1,130 @@
+package serializer
+
+import (
+	"goapptemp/internal/domain/entity"
+	"time"
+)
+
+type WebhookSubscriptionResponseData struct {
+	ID                  uint     `json:"id"`
+	CompanyID           uint     `json:"company_id"`
+	Name                string   `json:"name"`
+	TargetURL           string   `json:"target_url"`
+	Secret              string   `json:"secret,omitempty"`
+	EventTypes          []string `json:"event_types"`
+	IsActive            bool     `json:"is_active"`
+	ConsecutiveFailures uint     `json:"consecutive_failures"`
+	DisabledAt          *string  `json:"disabled_at,omitempty"`
+	DisabledReason      *string  `json:"disabled_reason,omitempty"`
+	CreatedAt           string   `json:"created_at,omitempty"`
+	UpdatedAt           string   `json:"updated_at,omitempty"`
+}
+
+// SerializeWebhookSubscription never includes the signing secret; callers add it only when it is first issued.
+func SerializeWebhookSubscription(arg *entity.WebhookSubscription) *WebhookSubscriptionResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	res := &WebhookSubscriptionResponseData{
+		ID:                  arg.ID,
+		CompanyID:           arg.CompanyID,
+		Name:                arg.Name,
+		TargetURL:           arg.TargetURL,
+		EventTypes:          arg.EventTypes,
+		IsActive:            arg.IsActive,
+		ConsecutiveFailures: arg.ConsecutiveFailures,
+		DisabledReason:      arg.DisabledReason,
+		CreatedAt:           arg.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           arg.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if arg.DisabledAt != nil {
+		disabledAt := arg.DisabledAt.Format(time.RFC3339)
+		res.DisabledAt = &disabledAt
+	}
+
+	return res
+}
+
+func SerializeWebhookSubscriptions(arg []*entity.WebhookSubscription) []*WebhookSubscriptionResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*WebhookSubscriptionResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, SerializeWebhookSubscription(arg[i]))
+	}
+
+	return res
+}
+
+type WebhookDeliveryResponseData struct {
+	ID             uint64  `json:"id"`
+	SubscriptionID uint    `json:"subscription_id"`
+	EventID        string  `json:"event_id"`
+	EventType      string  `json:"event_type"`
+	Attempt        uint    `json:"attempt"`
+	Status         string  `json:"status"`
+	ResponseStatus *int    `json:"response_status,omitempty"`
+	ResponseBody   *string `json:"response_body,omitempty"`
+	Error          *string `json:"error,omitempty"`
+	DurationMs     *uint   `json:"duration_ms,omitempty"`
+	ScheduledAt    string  `json:"scheduled_at"`
+	AttemptedAt    *string `json:"attempted_at,omitempty"`
+	ReplayOfID     *uint64 `json:"replay_of_id,omitempty"`
+	CreatedAt      string  `json:"created_at,omitempty"`
+}
+
+func SerializeWebhookDelivery(arg *entity.WebhookDelivery) *WebhookDeliveryResponseData {
+	if arg == nil {
+		return nil
+	}
+
+	res := &WebhookDeliveryResponseData{
+		ID:             arg.ID,
+		SubscriptionID: arg.SubscriptionID,
+		EventID:        arg.EventID,
+		EventType:      arg.EventType,
+		Attempt:        arg.Attempt,
+		Status:         string(arg.Status),
+		ResponseStatus: arg.ResponseStatus,
+		ResponseBody:   arg.ResponseBody,
+		Error:          arg.Error,
+		DurationMs:     arg.DurationMs,
+		ScheduledAt:    arg.ScheduledAt.Format(time.RFC3339),
+		ReplayOfID:     arg.ReplayOfID,
+		CreatedAt:      arg.CreatedAt.Format(time.RFC3339),
+	}
+
+	if arg.AttemptedAt != nil {
+		attemptedAt := arg.AttemptedAt.Format(time.RFC3339)
+		res.AttemptedAt = &attemptedAt
+	}
+
+	return res
+}
+
+func SerializeWebhookDeliveries(arg []*entity.WebhookDelivery) []*WebhookDeliveryResponseData {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*WebhookDeliveryResponseData, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, SerializeWebhookDelivery(arg[i]))
+	}
+
+	return res
+}
@@ -0,0 +1,334 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"goapptemp/pkg/logger"
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+
+	pubsubClient "goapptemp/pkg/pubsub"
+
+	"cloud.google.com/go/pubsub"
+	cerrors "github.com/cockroachdb/errors"
+	apm "go.elastic.co/apm/v2"
+)
+
+const (
+	defaultMaxDeliveryAttempts = 5
+	attributeCommand           = "command"
+	attributeEventType         = "event_type"
+
+	// Local delivery counts are kept for messages redelivered within attemptsTTL, up to
+	// maxTrackedAttempts of them; a message that is lost track of simply starts counting again.
+	attemptsTTL        = 30 * time.Minute
+	maxTrackedAttempts = 10000
+)
+
+// ErrUnrecoverable marks a handler error that must not be retried; the message is dead-lettered right away.
+var ErrUnrecoverable = cerrors.New("unrecoverable message")
+
+type Message struct {
+	ID              string
+	Command         string
+	Data            []byte
+	Attributes      map[string]string
+	DeliveryAttempt int
+	PublishTime     time.Time
+}
+
+type MessageHandler func(ctx context.Context, msg *Message) error
+
+type Subscriber interface {
+	Register(command string, handler MessageHandler)
+	Start(ctx context.Context) error
+}
+
+type SubscriberOption func(s *subscriber)
+
+func WithDeadLetter(publisher Publisher) SubscriberOption {
+	return func(s *subscriber) {
+		s.deadLetter = publisher
+	}
+}
+
+func WithMaxDeliveryAttempts(attempts int) SubscriberOption {
+	return func(s *subscriber) {
+		if attempts > 0 {
+			s.maxDeliveryAttempts = attempts
+		}
+	}
+}
+
+func WithMaxOutstandingMessages(n int) SubscriberOption {
+	return func(s *subscriber) {
+		if n > 0 {
+			s.subscription.ReceiveSettings.MaxOutstandingMessages = n
+		}
+	}
+}
+
+func WithNumGoroutines(n int) SubscriberOption {
+	return func(s *subscriber) {
+		if n > 0 {
+			s.subscription.ReceiveSettings.NumGoroutines = n
+		}
+	}
+}
+
+type subscriber struct {
+	logger              logger.Logger
+	subscription        *pubsub.Subscription
+	deadLetter          Publisher
+	maxDeliveryAttempts int
+	mu                  sync.RWMutex
+	handlers            map[string]MessageHandler
+	attempts            *attemptCache
+}
+
+func NewSubscriber(logger logger.Logger, pubsub pubsubClient.Pubsub, subscriptionID string, opts ...SubscriberOption) (*subscriber, error) {
+	subscription, err := pubsub.NewSubscriber(context.Background(), subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &subscriber{
+		logger:              logger.NewInstance().Field("component", "pubsub_subscriber").Logger(),
+		subscription:        subscription,
+		maxDeliveryAttempts: defaultMaxDeliveryAttempts,
+		handlers:            make(map[string]MessageHandler),
+		attempts:            newAttemptCache(attemptsTTL, maxTrackedAttempts),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+func (s *subscriber) Register(command string, handler MessageHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.handlers[command]; exists {
+		s.logger.Warn().Msgf("Handler for command %q is being replaced", command)
+	}
+
+	s.handlers[command] = handler
+}
+
+// Start blocks until ctx is cancelled and every in-flight message handler has returned.
+func (s *subscriber) Start(ctx context.Context) error {
+	s.logger.Info().Msgf("Subscriber listening on %s", s.subscription.ID())
+
+	err := s.subscription.Receive(ctx, s.handle)
+	if err != nil && !cerrors.Is(err, context.Canceled) {
+		return cerrors.Wrap(err, "pubsub receive failed")
+	}
+
+	s.logger.Info().Msg("Subscriber stopped")
+
+	return nil
+}
+
+func (s *subscriber) handle(ctx context.Context, m *pubsub.Message) {
+	msg := s.decode(m)
+
+	tx := apm.DefaultTracer().StartTransaction("Pubsub "+msg.Command, "messaging")
+	defer tx.End()
+
+	ctx = apm.ContextWithTransaction(ctx, tx)
+	log := s.logger.NewInstance().
+		Field("message_id", msg.ID).
+		Field("command", msg.Command).
+		Field("delivery_attempt", msg.DeliveryAttempt).
+		Logger()
+
+	// Domain events may share a topic with commands; they are not addressed to this subscriber.
+	if msg.Command == "" && m.Attributes[attributeEventType] != "" {
+		s.forget(msg.ID)
+		m.Ack()
+		tx.Result = "skipped"
+
+		return
+	}
+
+	s.mu.RLock()
+	handler, ok := s.handlers[msg.Command]
+	s.mu.RUnlock()
+
+	if !ok {
+		log.Warn().Msg("No handler registered for command")
+		s.sendToDeadLetter(ctx, log, m, msg, "unknown command")
+
+		return
+	}
+
+	err := handler(ctx, msg)
+	if err == nil {
+		s.forget(msg.ID)
+		m.Ack()
+		tx.Result = "success"
+
+		return
+	}
+
+	tx.Result = "failure"
+
+	if apmErr := apm.CaptureError(ctx, err); apmErr != nil {
+		apmErr.Handled = true
+		apmErr.Send()
+	}
+
+	if cerrors.Is(err, ErrUnrecoverable) || msg.DeliveryAttempt >= s.maxDeliveryAttempts {
+		log.Error().Err(err).Msg("Message handling failed permanently")
+		s.sendToDeadLetter(ctx, log, m, msg, err.Error())
+
+		return
+	}
+
+	log.Warn().Err(err).Msg("Message handling failed, message will be redelivered")
+	m.Nack()
+}
+
+func (s *subscriber) decode(m *pubsub.Message) *Message {
+	msg := &Message{
+		ID:              m.ID,
+		Data:            m.Data,
+		Attributes:      m.Attributes,
+		PublishTime:     m.PublishTime,
+		DeliveryAttempt: s.deliveryAttempt(m),
+	}
+
+	if command, ok := m.Attributes[attributeCommand]; ok && command != "" {
+		msg.Command = command
+		return msg
+	}
+
+	var envelope struct {
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal(m.Data, &envelope); err == nil {
+		msg.Command = envelope.Command
+	}
+
+	return msg
+}
+
+// deliveryAttempt uses the server-side counter, which is populated when the subscription has a
+// dead-letter policy, and otherwise counts redeliveries locally. A message redelivered to another
+// instance, or never settled before shutdown, is not forgotten explicitly, so the local counts expire.
+func (s *subscriber) deliveryAttempt(m *pubsub.Message) int {
+	if m.DeliveryAttempt != nil {
+		return *m.DeliveryAttempt
+	}
+
+	return s.attempts.increment(m.ID, time.Now())
+}
+
+func (s *subscriber) forget(messageID string) {
+	s.attempts.delete(messageID)
+}
+
+func (s *subscriber) sendToDeadLetter(ctx context.Context, log logger.Logger, m *pubsub.Message, msg *Message, reason string) {
+	if s.deadLetter == nil {
+		log.Error().Msgf("Dropping message without dead-letter topic: %s", reason)
+		s.forget(msg.ID)
+		m.Ack()
+
+		return
+	}
+
+	attributes := make(map[string]string, len(msg.Attributes)+4)
+	maps.Copy(attributes, msg.Attributes)
+
+	attributes[attributeCommand] = msg.Command
+	attributes["dead_letter_reason"] = reason
+	attributes["original_message_id"] = msg.ID
+	attributes["delivery_attempt"] = strconv.Itoa(msg.DeliveryAttempt)
+
+	if _, err := s.deadLetter.Publish(ctx, msg.Data, attributes); err != nil {
+		log.Error().Err(err).Msg("Failed to publish message to dead-letter topic")
+		m.Nack()
+
+		return
+	}
+
+	log.Warn().Msgf("Message moved to dead-letter topic: %s", reason)
+	s.forget(msg.ID)
+	m.Ack()
+}
+
+type attemptEntry struct {
+	count  int
+	seenAt time.Time
+}
+
+// attemptCache counts deliveries per message ID, dropping counts not touched within ttl and the
+// oldest ones once size IDs are tracked.
+type attemptCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[string]attemptEntry
+}
+
+func newAttemptCache(ttl time.Duration, size int) *attemptCache {
+	return &attemptCache{
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[string]attemptEntry),
+	}
+}
+
+func (c *attemptCache) increment(id string, now time.Time) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[id]
+	if !ok || now.Sub(entry.seenAt) > c.ttl {
+		entry = attemptEntry{}
+
+		if !ok && len(c.entries) >= c.size {
+			c.evict(now)
+		}
+	}
+
+	entry.count++
+	entry.seenAt = now
+	c.entries[id] = entry
+
+	return entry.count
+}
+
+func (c *attemptCache) delete(id string) {
+	c.mu.Lock()
+	delete(c.entries, id)
+	c.mu.Unlock()
+}
+
+// evict drops expired counts and, when none have expired, the least recently seen one.
+func (c *attemptCache) evict(now time.Time) {
+	var (
+		oldestID string
+		oldestAt time.Time
+	)
+
+	for id, entry := range c.entries {
+		if now.Sub(entry.seenAt) > c.ttl {
+			delete(c.entries, id)
+			continue
+		}
+
+		if oldestID == "" || entry.seenAt.Before(oldestAt) {
+			oldestID, oldestAt = id, entry.seenAt
+		}
+	}
+
+	if len(c.entries) >= c.size {
+		delete(c.entries, oldestID)
+	}
+}
@@ -0,0 +1,173 @@
+package mysqlrepository
+
+import (
+	"context"
+	"database/sql"
+	"goapptemp/internal/adapter/repository/mysql/model"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+var _ APIKeyRepository = (*apiKeyRepository)(nil)
+
+type APIKeyRepository interface {
+	GetTableName() string
+	Create(ctx context.Context, req *entity.APIKey) (*entity.APIKey, error)
+	FindByID(ctx context.Context, id uint) (*entity.APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+	Find(ctx context.Context, filter *FilterAPIKeyPayload) ([]*entity.APIKey, int, error)
+	Revoke(ctx context.Context, id uint) error
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type apiKeyRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewAPIKeyRepository(db bun.IDB, logger logger.Logger) *apiKeyRepository {
+	return &apiKeyRepository{db: db, logger: logger}
+}
+
+func (r *apiKeyRepository) GetTableName() string {
+	return "api_keys"
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, req *entity.APIKey) (*entity.APIKey, error) {
+	if req == nil {
+		return nil, handleDBError(exception.ErrDataNull, r.GetTableName(), "create api key")
+	}
+
+	apiKey := model.AsAPIKey(req)
+	if _, err := r.db.NewInsert().Model(apiKey).Exec(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "create api key")
+	}
+
+	return r.FindByID(ctx, apiKey.ID)
+}
+
+func (r *apiKeyRepository) FindByID(ctx context.Context, id uint) (*entity.APIKey, error) {
+	if id == 0 {
+		return nil, handleDBError(exception.ErrIDNull, r.GetTableName(), "find api key by id")
+	}
+
+	apiKey := &model.APIKey{ID: id}
+	if err := r.db.NewSelect().Model(apiKey).WherePK().Scan(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "find api key by id")
+	}
+
+	return apiKey.ToDomain(), nil
+}
+
+func (r *apiKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	apiKey := new(model.APIKey)
+	if err := r.db.NewSelect().Model(apiKey).Where("apk.prefix = ?", prefix).Scan(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "find api key by prefix")
+	}
+
+	return apiKey.ToDomain(), nil
+}
+
+type FilterAPIKeyPayload struct {
+	IDs            []uint
+	CompanyIDs     []uint
+	IncludeRevoked bool
+	Search         string
+	Page           int
+	PerPage        int
+}
+
+func (r *apiKeyRepository) Find(ctx context.Context, filter *FilterAPIKeyPayload) ([]*entity.APIKey, int, error) {
+	var apiKeys []*model.APIKey
+
+	query := r.db.NewSelect().Model(&apiKeys)
+	if len(filter.IDs) > 0 {
+		query = query.Where("apk.id IN (?)", bun.In(filter.IDs))
+	}
+
+	if len(filter.CompanyIDs) > 0 {
+		query = query.Where("apk.company_id IN (?)", bun.In(filter.CompanyIDs))
+	}
+
+	if !filter.IncludeRevoked {
+		query = query.Where("apk.revoked_at IS NULL")
+	}
+
+	if filter.Search != "" {
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			q = q.WhereOr("LOWER(apk.name) LIKE LOWER(?)", "%"+filter.Search+"%")
+			q = q.WhereOr("apk.prefix LIKE ?", "%"+filter.Search+"%")
+
+			return q
+		})
+	}
+
+	totalCount, err := query.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, handleDBError(err, r.GetTableName(), "count api key")
+	}
+
+	if totalCount == 0 {
+		return []*entity.APIKey{}, 0, nil
+	}
+
+	if filter.PerPage > 0 {
+		query = query.Limit(filter.PerPage)
+	}
+
+	if filter.Page > 0 && filter.PerPage > 0 {
+		offset := (filter.Page - 1) * filter.PerPage
+		query = query.Offset(offset)
+	}
+
+	query = query.Order("apk.id DESC")
+	if err = query.Scan(ctx); err != nil {
+		return nil, 0, handleDBError(err, r.GetTableName(), "find api key")
+	}
+
+	return model.ToAPIKeysDomain(apiKeys), totalCount, nil
+}
+
+// Revoke keeps the row so the key's history stays visible; revoking twice is reported as not found.
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uint) error {
+	if id == 0 {
+		return handleDBError(exception.ErrIDNull, r.GetTableName(), "revoke api key")
+	}
+
+	res, err := r.db.NewUpdate().
+		Model((*model.APIKey)(nil)).
+		Set("revoked_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "revoke api key")
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected == 0 {
+		return handleDBError(sql.ErrNoRows, r.GetTableName(), "revoke api key")
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	if id == 0 {
+		return handleDBError(exception.ErrIDNull, r.GetTableName(), "touch api key")
+	}
+
+	_, err := r.db.NewUpdate().
+		Model((*model.APIKey)(nil)).
+		Set("last_used_at = ?", at).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "touch api key")
+	}
+
+	return nil
+}
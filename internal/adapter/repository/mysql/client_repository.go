@@ -7,6 +7,7 @@ import (
 	"goapptemp/internal/domain/entity"
 	"goapptemp/internal/shared/exception"
 	"goapptemp/pkg/logger"
+	"goapptemp/pkg/policy"
 	"time"
 
 	"github.com/uptrace/bun"
@@ -21,7 +22,7 @@ type ClientRepository interface {
 	Find(ctx context.Context, filter *FilterClientPayload) ([]*entity.Client, int, error)
 	Update(ctx context.Context, req *UpdateClientPayload) (*entity.Client, error)
 	Delete(ctx context.Context, id uint) error
-	UpdateStaleIcons(ctx context.Context) error
+	UpdateStaleIcons(ctx context.Context, staleAfter time.Duration) error
 	IsCodeExists(ctx context.Context, code string) (bool, error)
 }
 
@@ -58,10 +59,21 @@ type FilterClientPayload struct {
 	Names      []string
 	PICNames   []string
 	Search     string
+	Policy     *policy.Filter
 	Page       int
 	PerPage    int
 }
 
+// clientPolicyColumns maps the client attributes access policies may reference to their columns
+// in the Find query, including the joined location relations.
+var clientPolicyColumns = map[string]string{
+	"id":          "cli.id",
+	"company_id":  "cli.company_id",
+	"district_id": "cli.district_id",
+	"city_id":     "district.city_id",
+	"province_id": "district__city.province_id",
+}
+
 func (r *clientRepository) Find(ctx context.Context, filter *FilterClientPayload) ([]*entity.Client, int, error) {
 	var clients []*model.Client
 
@@ -91,6 +103,8 @@ func (r *clientRepository) Find(ctx context.Context, filter *FilterClientPayload
 		})
 	}
 
+	query = applyPolicyFilter(query, filter.Policy, clientPolicyColumns)
+
 	totalCount, err := query.Clone().Count(ctx)
 	if err != nil {
 		return nil, 0, handleDBError(err, r.GetTableName(), "count client")
@@ -263,14 +277,12 @@ func (r *clientRepository) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
-func (r *clientRepository) UpdateStaleIcons(ctx context.Context) error {
-	thirtySecondsAgo := time.Now().Add(-30 * time.Second)
-
+func (r *clientRepository) UpdateStaleIcons(ctx context.Context, staleAfter time.Duration) error {
 	query := r.db.NewUpdate().
 		Model((*model.Client)(nil)).
 		Set("icon = ?", "failed").
 		Where("icon = ?", "loading").
-		Where("icon_updated_at < ?", thirtySecondsAgo)
+		Where("icon_updated_at < ?", time.Now().Add(-staleAfter))
 	if _, err := query.Exec(ctx); err != nil {
 		return handleDBError(err, r.GetTableName(), "update stale icons")
 	}
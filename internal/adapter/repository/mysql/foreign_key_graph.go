@@ -0,0 +1,140 @@
+package mysqlrepository
+
+import (
+	"context"
+	"fmt"
+	"goapptemp/constant"
+	"sync"
+
+	"github.com/uptrace/bun"
+)
+
+// foreignKey is a column of ChildTable referencing the primary key of another table.
+type foreignKey struct {
+	ChildTable  string
+	ChildColumn string
+	// ChildPK is empty when the child's primary key spans several columns.
+	ChildPK    string
+	SoftDelete bool
+}
+
+// foreignKeyGraph caches which tables reference which, read once from information_schema, so the
+// dependency checks run a single query instead of introspecting the schema every time. It is shared
+// by the repository and the transactional copies Atomic creates.
+type foreignKeyGraph struct {
+	mu         sync.RWMutex
+	loaded     bool
+	references map[string][]foreignKey
+}
+
+func newForeignKeyGraph() *foreignKeyGraph {
+	return &foreignKeyGraph{}
+}
+
+// load reads the foreign keys of schema that point at single-column primary keys of the same schema,
+// replacing whatever was cached.
+func (g *foreignKeyGraph) load(ctx context.Context, db bun.IDB, schema string) error {
+	type keyColumn struct {
+		TableName           string `bun:"table_name"`
+		ColumnName          string `bun:"column_name"`
+		ReferencedTableName string `bun:"referenced_table_name"`
+		ReferencedColumn    string `bun:"referenced_column_name"`
+	}
+
+	// MySQL 8 reports information_schema columns in upper case, hence the explicit aliases.
+	var primaryColumns []keyColumn
+
+	err := db.NewSelect().
+		ColumnExpr("table_name AS table_name, column_name AS column_name").
+		Table("information_schema.key_column_usage").
+		Where("table_schema = ?", schema).
+		Where("constraint_name = 'PRIMARY'").
+		Scan(ctx, &primaryColumns)
+	if err != nil {
+		return fmt.Errorf("failed to read primary keys of schema '%s': %w", schema, err)
+	}
+
+	primaryKeys := make(map[string][]string)
+	for _, column := range primaryColumns {
+		primaryKeys[column.TableName] = append(primaryKeys[column.TableName], column.ColumnName)
+	}
+
+	var softDeleteTables []string
+
+	err = db.NewSelect().
+		Column("table_name").
+		Table("information_schema.columns").
+		Where("table_schema = ?", schema).
+		Where("column_name = ?", constant.SoftDeleteColumnName).
+		Scan(ctx, &softDeleteTables)
+	if err != nil {
+		return fmt.Errorf("failed to read soft delete columns of schema '%s': %w", schema, err)
+	}
+
+	softDelete := make(map[string]bool, len(softDeleteTables))
+	for _, table := range softDeleteTables {
+		softDelete[table] = true
+	}
+
+	var foreignColumns []keyColumn
+
+	err = db.NewSelect().
+		ColumnExpr("table_name AS table_name, column_name AS column_name").
+		ColumnExpr("referenced_table_name AS referenced_table_name, referenced_column_name AS referenced_column_name").
+		Table("information_schema.key_column_usage").
+		Where("table_schema = ?", schema).
+		Where("referenced_table_schema = ?", schema).
+		OrderExpr("referenced_table_name, table_name, column_name").
+		Scan(ctx, &foreignColumns)
+	if err != nil {
+		return fmt.Errorf("failed to read foreign keys of schema '%s': %w", schema, err)
+	}
+
+	references := make(map[string][]foreignKey)
+
+	for _, column := range foreignColumns {
+		parentPK := primaryKeys[column.ReferencedTableName]
+		if len(parentPK) != 1 || parentPK[0] != column.ReferencedColumn {
+			continue
+		}
+
+		reference := foreignKey{
+			ChildTable:  column.TableName,
+			ChildColumn: column.ColumnName,
+			SoftDelete:  softDelete[column.TableName],
+		}
+
+		if childPK := primaryKeys[column.TableName]; len(childPK) == 1 {
+			reference.ChildPK = childPK[0]
+		}
+
+		references[column.ReferencedTableName] = append(references[column.ReferencedTableName], reference)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.references = references
+	g.loaded = true
+
+	return nil
+}
+
+// referencesTo returns the foreign keys pointing at parentTable, loading the graph on first use when
+// it was not loaded at startup.
+func (g *foreignKeyGraph) referencesTo(ctx context.Context, db bun.IDB, schema, parentTable string) ([]foreignKey, error) {
+	g.mu.RLock()
+	loaded := g.loaded
+	g.mu.RUnlock()
+
+	if !loaded {
+		if err := g.load(ctx, db, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.references[parentTable], nil
+}
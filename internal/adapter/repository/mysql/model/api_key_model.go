@@ -0,0 +1,84 @@
+package model
+
+import (
+	"goapptemp/internal/domain/entity"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type APIKey struct {
+	bun.BaseModel `bun:"table:api_keys,alias:apk"`
+	ID            uint       `bun:"id,pk,autoincrement"`
+	CompanyID     uint       `bun:"company_id,notnull"`
+	UserID        uint       `bun:"user_id,notnull"`
+	Name          string     `bun:"name,notnull"`
+	Prefix        string     `bun:"prefix,notnull"`
+	SecretHash    string     `bun:"secret_hash,notnull"`
+	Permissions   []string   `bun:"permissions,type:json,notnull"`
+	ExpiresAt     *time.Time `bun:"expires_at"`
+	LastUsedAt    *time.Time `bun:"last_used_at"`
+	RevokedAt     *time.Time `bun:"revoked_at"`
+	CreatedAt     time.Time  `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time  `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (m *APIKey) ToDomain() *entity.APIKey {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.APIKey{
+		ID:          m.ID,
+		CompanyID:   m.CompanyID,
+		UserID:      m.UserID,
+		Name:        m.Name,
+		Prefix:      m.Prefix,
+		SecretHash:  m.SecretHash,
+		Permissions: m.Permissions,
+		ExpiresAt:   m.ExpiresAt,
+		LastUsedAt:  m.LastUsedAt,
+		RevokedAt:   m.RevokedAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func ToAPIKeysDomain(arg []*APIKey) []*entity.APIKey {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*entity.APIKey, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, arg[i].ToDomain())
+	}
+
+	return res
+}
+
+func AsAPIKey(arg *entity.APIKey) *APIKey {
+	if arg == nil {
+		return nil
+	}
+
+	return &APIKey{
+		ID:          arg.ID,
+		CompanyID:   arg.CompanyID,
+		UserID:      arg.UserID,
+		Name:        arg.Name,
+		Prefix:      arg.Prefix,
+		SecretHash:  arg.SecretHash,
+		Permissions: arg.Permissions,
+		ExpiresAt:   arg.ExpiresAt,
+		LastUsedAt:  arg.LastUsedAt,
+		RevokedAt:   arg.RevokedAt,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+	}
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"goapptemp/internal/domain/entity"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type Notification struct {
+	bun.BaseModel `bun:"table:notifications,alias:ntf"`
+	ID            uint64         `bun:"id,pk,autoincrement"`
+	UserID        uint           `bun:"user_id,notnull"`
+	Type          string         `bun:"type,notnull"`
+	Title         string         `bun:"title,notnull"`
+	Body          string         `bun:"body,notnull"`
+	Data          map[string]any `bun:"data,type:json"`
+	ReadAt        *time.Time     `bun:"read_at"`
+	CreatedAt     time.Time      `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time      `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (m *Notification) ToDomain() *entity.Notification {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.Notification{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Type:      entity.NotificationType(m.Type),
+		Title:     m.Title,
+		Body:      m.Body,
+		Data:      m.Data,
+		ReadAt:    m.ReadAt,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func ToNotificationsDomain(arg []*Notification) []*entity.Notification {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*entity.Notification, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, arg[i].ToDomain())
+	}
+
+	return res
+}
+
+func AsNotification(arg *entity.Notification) *Notification {
+	if arg == nil {
+		return nil
+	}
+
+	return &Notification{
+		ID:        arg.ID,
+		UserID:    arg.UserID,
+		Type:      string(arg.Type),
+		Title:     arg.Title,
+		Body:      arg.Body,
+		Data:      arg.Data,
+		ReadAt:    arg.ReadAt,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+	}
+}
+
+type NotificationPreference struct {
+	bun.BaseModel `bun:"table:notification_preferences,alias:ntp"`
+	ID            uint      `bun:"id,pk,autoincrement"`
+	UserID        uint      `bun:"user_id,notnull"`
+	Type          string    `bun:"type,notnull"`
+	Channel       string    `bun:"channel,notnull"`
+	Enabled       bool      `bun:"enabled,notnull"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (m *NotificationPreference) ToDomain() *entity.NotificationPreference {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.NotificationPreference{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Type:      entity.NotificationType(m.Type),
+		Channel:   entity.NotificationChannel(m.Channel),
+		Enabled:   m.Enabled,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func ToNotificationPreferencesDomain(arg []*NotificationPreference) []*entity.NotificationPreference {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*entity.NotificationPreference, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, arg[i].ToDomain())
+	}
+
+	return res
+}
+
+func AsNotificationPreferences(arg []*entity.NotificationPreference) []*NotificationPreference {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*NotificationPreference, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, &NotificationPreference{
+			ID:        arg[i].ID,
+			UserID:    arg[i].UserID,
+			Type:      string(arg[i].Type),
+			Channel:   string(arg[i].Channel),
+			Enabled:   arg[i].Enabled,
+			CreatedAt: arg[i].CreatedAt,
+			UpdatedAt: arg[i].UpdatedAt,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"goapptemp/internal/domain/entity"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type PasswordHistory struct {
+	bun.BaseModel `bun:"table:password_history,alias:pwh"`
+	ID            uint      `bun:"id,pk,autoincrement"`
+	UserID        uint      `bun:"user_id,notnull"`
+	PasswordHash  string    `bun:"password_hash,notnull"`
+	CreatedAt     time.Time `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+func (m *PasswordHistory) ToDomain() *entity.PasswordHistory {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.PasswordHistory{
+		ID:           m.ID,
+		UserID:       m.UserID,
+		PasswordHash: m.PasswordHash,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+func ToPasswordHistoriesDomain(arg []*PasswordHistory) []*entity.PasswordHistory {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*entity.PasswordHistory, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, arg[i].ToDomain())
+	}
+
+	return res
+}
+
+func AsPasswordHistory(arg *entity.PasswordHistory) *PasswordHistory {
+	if arg == nil {
+		return nil
+	}
+
+	return &PasswordHistory{
+		ID:           arg.ID,
+		UserID:       arg.UserID,
+		PasswordHash: arg.PasswordHash,
+		CreatedAt:    arg.CreatedAt,
+	}
+}
@@ -9,6 +9,8 @@ import (
 type Role struct {
 	bun.BaseModel `bun:"table:roles,alias:rol"`
 	Base
+	ParentID    *uint         `bun:"parent_id"`
+	CompanyID   *uint         `bun:"company_id"`
 	Permissions []*Permission `bun:"m2m:role_permissions,join:Role=Permission"`
 	Code        string        `bun:"code,notnull"`
 	Name        string        `bun:"name,notnull"`
@@ -34,6 +36,8 @@ func (m *Role) ToDomain() *entity.Role {
 	}
 
 	res := &entity.Role{
+		ParentID:      m.ParentID,
+		CompanyID:     m.CompanyID,
 		PermissionIDs: permissionIDs,
 		Permissions:   ToPermissionsDomain(m.Permissions),
 		Code:          m.Code,
@@ -75,6 +79,8 @@ func AsRole(arg *entity.Role) *Role {
 	}
 
 	return &Role{
+		ParentID:    arg.ParentID,
+		CompanyID:   arg.CompanyID,
 		Permissions: AsPermissions(arg.Permissions),
 		Code:        arg.Code,
 		Name:        arg.Name,
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"goapptemp/internal/domain/entity"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type UserIdentity struct {
+	bun.BaseModel `bun:"table:user_identities,alias:uid"`
+	ID            uint       `bun:"id,pk,autoincrement"`
+	UserID        uint       `bun:"user_id,notnull"`
+	Provider      string     `bun:"provider,notnull"`
+	Subject       string     `bun:"subject,notnull"`
+	Email         string     `bun:"email,notnull"`
+	LastLoginAt   *time.Time `bun:"last_login_at"`
+	CreatedAt     time.Time  `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time  `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (m *UserIdentity) ToDomain() *entity.UserIdentity {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.UserIdentity{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Provider:    m.Provider,
+		Subject:     m.Subject,
+		Email:       m.Email,
+		LastLoginAt: m.LastLoginAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func AsUserIdentity(arg *entity.UserIdentity) *UserIdentity {
+	if arg == nil {
+		return nil
+	}
+
+	return &UserIdentity{
+		ID:          arg.ID,
+		UserID:      arg.UserID,
+		Provider:    arg.Provider,
+		Subject:     arg.Subject,
+		Email:       arg.Email,
+		LastLoginAt: arg.LastLoginAt,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+	}
+}
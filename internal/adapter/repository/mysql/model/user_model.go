@@ -2,6 +2,7 @@ package model
 
 import (
 	"goapptemp/internal/domain/entity"
+	"time"
 
 	"github.com/uptrace/bun"
 )
@@ -9,15 +10,20 @@ import (
 type User struct {
 	bun.BaseModel `bun:"table:users,alias:usr"`
 	Base
-	Roles          []*Role `bun:"m2m:user_roles,join:User=Role"`
-	CompanyID      uint
-	Company        *Company `bun:"rel:belongs-to,join:company_id=id"`
-	Username       string   `bun:"username,notnull"`
-	Email          string   `bun:"email,notnull"`
-	Password       string   `bun:"password,notnull"`
-	Fullname       string   `bun:"fullname,notnull"`
-	UsernameActive *string  `bun:"username_active,unique:uq_users_company_username_active"`
-	EmailActive    *string  `bun:"email_active,unique:uq_users_company_email_active"`
+	Roles             []*Role `bun:"m2m:user_roles,join:User=Role"`
+	CompanyID         uint
+	Company           *Company   `bun:"rel:belongs-to,join:company_id=id"`
+	Username          string     `bun:"username,notnull"`
+	Email             string     `bun:"email,notnull"`
+	Password          string     `bun:"password,notnull"`
+	PasswordChangedAt *time.Time `bun:"password_changed_at"`
+	Fullname          string     `bun:"fullname,notnull"`
+	Locale            string     `bun:"locale,nullzero,notnull,default:'en'"`
+	Status            string     `bun:"status,nullzero,notnull,default:'active'"`
+	SuspendedAt       *time.Time `bun:"suspended_at"`
+	SuspensionReason  string     `bun:"suspension_reason,nullzero"`
+	UsernameActive    *string    `bun:"username_active,unique:uq_users_company_username_active"`
+	EmailActive       *string    `bun:"email_active,unique:uq_users_company_email_active"`
 }
 
 func (m *User) ToDomain() *entity.User {
@@ -36,13 +42,18 @@ func (m *User) ToDomain() *entity.User {
 	}
 
 	return &entity.User{
-		RoleIDs:   roleIDs,
-		Roles:     ToRolesDomain(m.Roles),
-		CompanyID: m.CompanyID,
-		Username:  m.Username,
-		Email:     m.Email,
-		Password:  m.Password,
-		Fullname:  m.Fullname,
+		RoleIDs:           roleIDs,
+		Roles:             ToRolesDomain(m.Roles),
+		CompanyID:         m.CompanyID,
+		Username:          m.Username,
+		Email:             m.Email,
+		Password:          m.Password,
+		Fullname:          m.Fullname,
+		Locale:            m.Locale,
+		Status:            entity.UserStatus(m.Status),
+		PasswordChangedAt: m.PasswordChangedAt,
+		SuspendedAt:       m.SuspendedAt,
+		SuspensionReason:  m.SuspensionReason,
 		Base: entity.Base{
 			ID:        m.ID,
 			CreatedAt: m.CreatedAt,
@@ -76,12 +87,17 @@ func AsUser(arg *entity.User) *User {
 	}
 
 	return &User{
-		Roles:     AsRoles(arg.Roles),
-		CompanyID: arg.CompanyID,
-		Username:  arg.Username,
-		Email:     arg.Email,
-		Password:  arg.Password,
-		Fullname:  arg.Fullname,
+		Roles:             AsRoles(arg.Roles),
+		CompanyID:         arg.CompanyID,
+		Username:          arg.Username,
+		Email:             arg.Email,
+		Password:          arg.Password,
+		Fullname:          arg.Fullname,
+		Locale:            arg.Locale,
+		Status:            string(arg.Status),
+		PasswordChangedAt: arg.PasswordChangedAt,
+		SuspendedAt:       arg.SuspendedAt,
+		SuspensionReason:  arg.SuspensionReason,
 		Base: Base{
 			ID:        arg.ID,
 			CreatedAt: arg.CreatedAt,
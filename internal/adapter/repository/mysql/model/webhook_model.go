@@ -0,0 +1,196 @@
+package model
+
+import (
+	"encoding/json"
+	"goapptemp/internal/domain/entity"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type WebhookSubscription struct {
+	bun.BaseModel `bun:"table:webhook_subscriptions,alias:whs"`
+	Base
+	CompanyID           uint       `bun:"company_id,notnull"`
+	Name                string     `bun:"name,notnull"`
+	TargetURL           string     `bun:"target_url,notnull"`
+	Secret              string     `bun:"secret,notnull"`
+	EventTypes          []string   `bun:"event_types,type:json,notnull"`
+	IsActive            bool       `bun:"is_active,notnull"`
+	ConsecutiveFailures uint       `bun:"consecutive_failures,notnull"`
+	DisabledAt          *time.Time `bun:"disabled_at"`
+	DisabledReason      *string    `bun:"disabled_reason"`
+	NameActive          *string    `bun:"name_active,scanonly"`
+}
+
+func (m *WebhookSubscription) ToDomain() *entity.WebhookSubscription {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.WebhookSubscription{
+		CompanyID:           m.CompanyID,
+		Name:                m.Name,
+		TargetURL:           m.TargetURL,
+		Secret:              m.Secret,
+		EventTypes:          m.EventTypes,
+		IsActive:            m.IsActive,
+		ConsecutiveFailures: m.ConsecutiveFailures,
+		DisabledAt:          m.DisabledAt,
+		DisabledReason:      m.DisabledReason,
+		Base: entity.Base{
+			ID:        m.ID,
+			CreatedAt: m.CreatedAt,
+			UpdatedAt: m.UpdatedAt,
+			DeletedAt: m.DeletedAt,
+		},
+	}
+}
+
+func ToWebhookSubscriptionsDomain(arg []*WebhookSubscription) []*entity.WebhookSubscription {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*entity.WebhookSubscription, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, arg[i].ToDomain())
+	}
+
+	return res
+}
+
+func AsWebhookSubscription(arg *entity.WebhookSubscription) *WebhookSubscription {
+	if arg == nil {
+		return nil
+	}
+
+	return &WebhookSubscription{
+		CompanyID:           arg.CompanyID,
+		Name:                arg.Name,
+		TargetURL:           arg.TargetURL,
+		Secret:              arg.Secret,
+		EventTypes:          arg.EventTypes,
+		IsActive:            arg.IsActive,
+		ConsecutiveFailures: arg.ConsecutiveFailures,
+		DisabledAt:          arg.DisabledAt,
+		DisabledReason:      arg.DisabledReason,
+		Base: Base{
+			ID:        arg.ID,
+			CreatedAt: arg.CreatedAt,
+			UpdatedAt: arg.UpdatedAt,
+			DeletedAt: arg.DeletedAt,
+		},
+	}
+}
+
+type WebhookDelivery struct {
+	bun.BaseModel  `bun:"table:webhook_deliveries,alias:whd"`
+	ID             uint64          `bun:"id,pk,autoincrement"`
+	SubscriptionID uint            `bun:"subscription_id,notnull"`
+	EventID        string          `bun:"event_id,notnull"`
+	EventType      string          `bun:"event_type,notnull"`
+	Payload        json.RawMessage `bun:"payload,type:json,notnull"`
+	Attempt        uint            `bun:"attempt,notnull"`
+	Status         string          `bun:"status,notnull"`
+	ResponseStatus *int            `bun:"response_status"`
+	ResponseBody   *string         `bun:"response_body"`
+	Error          *string         `bun:"error"`
+	DurationMs     *uint           `bun:"duration_ms"`
+	ScheduledAt    time.Time       `bun:"scheduled_at,notnull"`
+	AttemptedAt    *time.Time      `bun:"attempted_at"`
+	ReplayOfID     *uint64         `bun:"replay_of_id"`
+	CreatedAt      time.Time       `bun:"created_at,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time       `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (m *WebhookDelivery) ToDomain() *entity.WebhookDelivery {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.WebhookDelivery{
+		ID:             m.ID,
+		SubscriptionID: m.SubscriptionID,
+		EventID:        m.EventID,
+		EventType:      m.EventType,
+		Payload:        m.Payload,
+		Attempt:        m.Attempt,
+		Status:         entity.WebhookDeliveryStatus(m.Status),
+		ResponseStatus: m.ResponseStatus,
+		ResponseBody:   m.ResponseBody,
+		Error:          m.Error,
+		DurationMs:     m.DurationMs,
+		ScheduledAt:    m.ScheduledAt,
+		AttemptedAt:    m.AttemptedAt,
+		ReplayOfID:     m.ReplayOfID,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func ToWebhookDeliveriesDomain(arg []*WebhookDelivery) []*entity.WebhookDelivery {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*entity.WebhookDelivery, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, arg[i].ToDomain())
+	}
+
+	return res
+}
+
+func AsWebhookDelivery(arg *entity.WebhookDelivery) *WebhookDelivery {
+	if arg == nil {
+		return nil
+	}
+
+	return &WebhookDelivery{
+		ID:             arg.ID,
+		SubscriptionID: arg.SubscriptionID,
+		EventID:        arg.EventID,
+		EventType:      arg.EventType,
+		Payload:        arg.Payload,
+		Attempt:        arg.Attempt,
+		Status:         string(arg.Status),
+		ResponseStatus: arg.ResponseStatus,
+		ResponseBody:   arg.ResponseBody,
+		Error:          arg.Error,
+		DurationMs:     arg.DurationMs,
+		ScheduledAt:    arg.ScheduledAt,
+		AttemptedAt:    arg.AttemptedAt,
+		ReplayOfID:     arg.ReplayOfID,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+	}
+}
+
+func AsWebhookDeliveries(arg []*entity.WebhookDelivery) []*WebhookDelivery {
+	if len(arg) == 0 {
+		return nil
+	}
+
+	res := make([]*WebhookDelivery, 0, len(arg))
+
+	for i := range arg {
+		if arg[i] == nil {
+			continue
+		}
+
+		res = append(res, AsWebhookDelivery(arg[i]))
+	}
+
+	return res
+}
@@ -30,11 +30,19 @@ type MySQLRepository interface {
 	City() CityRepository
 	District() DistrictRepository
 	ClientSupportFeature() ClientSupportFeatureRepository
+	WebhookSubscription() WebhookSubscriptionRepository
+	WebhookDelivery() WebhookDeliveryRepository
+	Notification() NotificationRepository
+	NotificationPreference() NotificationPreferenceRepository
+	APIKey() APIKeyRepository
+	UserIdentity() UserIdentityRepository
+	PasswordHistory() PasswordHistoryRepository
 }
 
 type mysqlRepository struct {
 	db                             bun.IDB
 	logger                         logger.Logger
+	foreignKeys                    *foreignKeyGraph
 	userRepository                 UserRepository
 	companyRepository              CompanyRepository
 	clientRepository               ClientRepository
@@ -46,6 +54,13 @@ type mysqlRepository struct {
 	cityRepository                 CityRepository
 	clientSupportFeatureRepository ClientSupportFeatureRepository
 	storeProcedureRepository       StoreProcedureRepository
+	webhookSubscriptionRepository  WebhookSubscriptionRepository
+	webhookDeliveryRepository      WebhookDeliveryRepository
+	notificationRepository         NotificationRepository
+	notificationPrefRepository     NotificationPreferenceRepository
+	apiKeyRepository               APIKeyRepository
+	userIdentityRepository         UserIdentityRepository
+	passwordHistoryRepository      PasswordHistoryRepository
 }
 
 func NewMySQLRepository(config *config.Config, logger logger.Logger) (*mysqlRepository, error) {
@@ -67,9 +82,16 @@ func NewMySQLRepository(config *config.Config, logger logger.Logger) (*mysqlRepo
 		(*model.Role)(nil),
 		(*model.SupportFeature)(nil),
 		(*model.User)(nil),
+		(*model.WebhookSubscription)(nil),
+		(*model.WebhookDelivery)(nil),
+		(*model.Notification)(nil),
+		(*model.NotificationPreference)(nil),
+		(*model.APIKey)(nil),
+		(*model.UserIdentity)(nil),
+		(*model.PasswordHistory)(nil),
 	)
 
-	return create(config, db.DB(), logger), nil
+	return create(config, db.DB(), logger, newForeignKeyGraph()), nil
 }
 
 func (r *mysqlRepository) DB() *bun.DB {
@@ -91,7 +113,7 @@ func (r *mysqlRepository) Atomic(ctx context.Context, config *config.Config, fn
 		ctx,
 		&sql.TxOptions{Isolation: sql.LevelSerializable},
 		func(ctx context.Context, tx bun.Tx) error {
-			return fn(create(config, tx, r.logger))
+			return fn(create(config, tx, r.logger, r.foreignKeys))
 		},
 	)
 	if err != nil {
@@ -101,10 +123,11 @@ func (r *mysqlRepository) Atomic(ctx context.Context, config *config.Config, fn
 	return nil
 }
 
-func create(config *config.Config, db bun.IDB, logger logger.Logger) *mysqlRepository {
+func create(config *config.Config, db bun.IDB, logger logger.Logger, foreignKeys *foreignKeyGraph) *mysqlRepository {
 	return &mysqlRepository{
 		db:                             db,
 		logger:                         logger,
+		foreignKeys:                    foreignKeys,
 		userRepository:                 NewUserRepository(db, logger),
 		clientRepository:               NewClientRepository(db, logger),
 		roleRepository:                 NewRoleRepository(db, logger),
@@ -114,8 +137,15 @@ func create(config *config.Config, db bun.IDB, logger logger.Logger) *mysqlRepos
 		districtRepository:             NewDistrictRepository(db, logger),
 		companyRepository:              NewCompanyRepository(db, logger),
 		clientSupportFeatureRepository: NewClientSupportFeatureRepository(db, logger),
-		storeProcedureRepository:       NewStoreProcedureRepository(config.MySQL.DBName, db, logger),
+		storeProcedureRepository:       NewStoreProcedureRepository(config.MySQL.DBName, db, foreignKeys, logger),
 		permissionRepository:           NewPermissionRepository(db, logger),
+		webhookSubscriptionRepository:  NewWebhookSubscriptionRepository(db, logger),
+		webhookDeliveryRepository:      NewWebhookDeliveryRepository(db, logger),
+		notificationRepository:         NewNotificationRepository(db, logger),
+		notificationPrefRepository:     NewNotificationPreferenceRepository(db, logger),
+		apiKeyRepository:               NewAPIKeyRepository(db, logger),
+		userIdentityRepository:         NewUserIdentityRepository(db, logger),
+		passwordHistoryRepository:      NewPasswordHistoryRepository(db, logger),
 	}
 }
 
@@ -162,3 +192,31 @@ func (r *mysqlRepository) ClientSupportFeature() ClientSupportFeatureRepository
 func (r *mysqlRepository) StoreProcedure() StoreProcedureRepository {
 	return r.storeProcedureRepository
 }
+
+func (r *mysqlRepository) WebhookSubscription() WebhookSubscriptionRepository {
+	return r.webhookSubscriptionRepository
+}
+
+func (r *mysqlRepository) WebhookDelivery() WebhookDeliveryRepository {
+	return r.webhookDeliveryRepository
+}
+
+func (r *mysqlRepository) Notification() NotificationRepository {
+	return r.notificationRepository
+}
+
+func (r *mysqlRepository) NotificationPreference() NotificationPreferenceRepository {
+	return r.notificationPrefRepository
+}
+
+func (r *mysqlRepository) APIKey() APIKeyRepository {
+	return r.apiKeyRepository
+}
+
+func (r *mysqlRepository) UserIdentity() UserIdentityRepository {
+	return r.userIdentityRepository
+}
+
+func (r *mysqlRepository) PasswordHistory() PasswordHistoryRepository {
+	return r.passwordHistoryRepository
+}
@@ -0,0 +1,72 @@
+package mysqlrepository
+
+import (
+	"context"
+	"goapptemp/internal/adapter/repository/mysql/model"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+
+	"github.com/uptrace/bun"
+)
+
+var _ NotificationPreferenceRepository = (*notificationPreferenceRepository)(nil)
+
+type NotificationPreferenceRepository interface {
+	GetTableName() string
+	FindByUserID(ctx context.Context, userID uint) ([]*entity.NotificationPreference, error)
+	Upsert(ctx context.Context, req []*entity.NotificationPreference) error
+}
+
+type notificationPreferenceRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewNotificationPreferenceRepository(db bun.IDB, logger logger.Logger) *notificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db, logger: logger}
+}
+
+func (r *notificationPreferenceRepository) GetTableName() string {
+	return "notification_preferences"
+}
+
+func (r *notificationPreferenceRepository) FindByUserID(ctx context.Context, userID uint) ([]*entity.NotificationPreference, error) {
+	if userID == 0 {
+		return nil, handleDBError(exception.ErrIDNull, r.GetTableName(), "find notification preference by user id")
+	}
+
+	var preferences []*model.NotificationPreference
+
+	err := r.db.NewSelect().
+		Model(&preferences).
+		Where("ntp.user_id = ?", userID).
+		Order("ntp.type ASC", "ntp.channel ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "find notification preference by user id")
+	}
+
+	return model.ToNotificationPreferencesDomain(preferences), nil
+}
+
+// Upsert relies on the (user_id, type, channel) unique key, so saving a preference twice updates it in place.
+func (r *notificationPreferenceRepository) Upsert(ctx context.Context, req []*entity.NotificationPreference) error {
+	if len(req) == 0 {
+		return handleDBError(exception.ErrDataNull, r.GetTableName(), "upsert notification preferences")
+	}
+
+	preferences := model.AsNotificationPreferences(req)
+
+	_, err := r.db.NewInsert().
+		Model(&preferences).
+		On("DUPLICATE KEY UPDATE").
+		Set("enabled = VALUES(enabled)").
+		Set("updated_at = CURRENT_TIMESTAMP").
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "upsert notification preferences")
+	}
+
+	return nil
+}
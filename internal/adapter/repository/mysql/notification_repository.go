@@ -0,0 +1,163 @@
+package mysqlrepository
+
+import (
+	"context"
+	"goapptemp/internal/adapter/repository/mysql/model"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+var _ NotificationRepository = (*notificationRepository)(nil)
+
+type NotificationRepository interface {
+	GetTableName() string
+	Create(ctx context.Context, req *entity.Notification) (*entity.Notification, error)
+	Find(ctx context.Context, filter *FilterNotificationPayload) ([]*entity.Notification, int, error)
+	CountUnread(ctx context.Context, userID uint) (int, error)
+	MarkRead(ctx context.Context, userID uint, ids []uint64) (int, error)
+	MarkAllRead(ctx context.Context, userID uint) (int, error)
+}
+
+type notificationRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewNotificationRepository(db bun.IDB, logger logger.Logger) *notificationRepository {
+	return &notificationRepository{db: db, logger: logger}
+}
+
+func (r *notificationRepository) GetTableName() string {
+	return "notifications"
+}
+
+func (r *notificationRepository) Create(ctx context.Context, req *entity.Notification) (*entity.Notification, error) {
+	if req == nil {
+		return nil, handleDBError(exception.ErrDataNull, r.GetTableName(), "create notification")
+	}
+
+	notification := model.AsNotification(req)
+	if _, err := r.db.NewInsert().Model(notification).Exec(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "create notification")
+	}
+
+	return notification.ToDomain(), nil
+}
+
+type FilterNotificationPayload struct {
+	UserID     uint
+	UnreadOnly bool
+	Types      []string
+	Page       int
+	PerPage    int
+}
+
+func (r *notificationRepository) Find(ctx context.Context, filter *FilterNotificationPayload) ([]*entity.Notification, int, error) {
+	if filter == nil || filter.UserID == 0 {
+		return nil, 0, handleDBError(exception.ErrIDNull, r.GetTableName(), "find notification")
+	}
+
+	var notifications []*model.Notification
+
+	query := r.db.NewSelect().Model(&notifications).Where("ntf.user_id = ?", filter.UserID)
+	if filter.UnreadOnly {
+		query = query.Where("ntf.read_at IS NULL")
+	}
+
+	if len(filter.Types) > 0 {
+		query = query.Where("ntf.type IN (?)", bun.In(filter.Types))
+	}
+
+	totalCount, err := query.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, handleDBError(err, r.GetTableName(), "count notification")
+	}
+
+	if totalCount == 0 {
+		return []*entity.Notification{}, 0, nil
+	}
+
+	if filter.PerPage > 0 {
+		query = query.Limit(filter.PerPage)
+	}
+
+	if filter.Page > 0 && filter.PerPage > 0 {
+		offset := (filter.Page - 1) * filter.PerPage
+		query = query.Offset(offset)
+	}
+
+	query = query.Order("ntf.id DESC")
+	if err = query.Scan(ctx); err != nil {
+		return nil, 0, handleDBError(err, r.GetTableName(), "find notification")
+	}
+
+	return model.ToNotificationsDomain(notifications), totalCount, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uint) (int, error) {
+	if userID == 0 {
+		return 0, handleDBError(exception.ErrIDNull, r.GetTableName(), "count unread notification")
+	}
+
+	count, err := r.db.NewSelect().
+		Model((*model.Notification)(nil)).
+		Where("ntf.user_id = ?", userID).
+		Where("ntf.read_at IS NULL").
+		Count(ctx)
+	if err != nil {
+		return 0, handleDBError(err, r.GetTableName(), "count unread notification")
+	}
+
+	return count, nil
+}
+
+// MarkRead only touches the user's own unread notifications, so ids belonging to someone else are
+// silently skipped and the returned count tells how many actually changed.
+func (r *notificationRepository) MarkRead(ctx context.Context, userID uint, ids []uint64) (int, error) {
+	if userID == 0 {
+		return 0, handleDBError(exception.ErrIDNull, r.GetTableName(), "mark notification read")
+	}
+
+	if len(ids) == 0 {
+		return 0, handleDBError(exception.ErrDataNull, r.GetTableName(), "mark notification read")
+	}
+
+	res, err := r.db.NewUpdate().
+		Model((*model.Notification)(nil)).
+		Set("read_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("id IN (?)", bun.In(ids)).
+		Where("read_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return 0, handleDBError(err, r.GetTableName(), "mark notification read")
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+
+	return int(rowsAffected), nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uint) (int, error) {
+	if userID == 0 {
+		return 0, handleDBError(exception.ErrIDNull, r.GetTableName(), "mark all notification read")
+	}
+
+	res, err := r.db.NewUpdate().
+		Model((*model.Notification)(nil)).
+		Set("read_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("read_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return 0, handleDBError(err, r.GetTableName(), "mark all notification read")
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+
+	return int(rowsAffected), nil
+}
@@ -0,0 +1,101 @@
+package mysqlrepository
+
+import (
+	"context"
+	"database/sql"
+	"goapptemp/internal/adapter/repository/mysql/model"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+
+	"github.com/cockroachdb/errors"
+	"github.com/uptrace/bun"
+)
+
+var _ PasswordHistoryRepository = (*passwordHistoryRepository)(nil)
+
+type PasswordHistoryRepository interface {
+	GetTableName() string
+	Create(ctx context.Context, req *entity.PasswordHistory) (*entity.PasswordHistory, error)
+	FindRecent(ctx context.Context, userID uint, limit int) ([]*entity.PasswordHistory, error)
+	Prune(ctx context.Context, userID uint, keep int) error
+}
+
+type passwordHistoryRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewPasswordHistoryRepository(db bun.IDB, logger logger.Logger) *passwordHistoryRepository {
+	return &passwordHistoryRepository{db: db, logger: logger}
+}
+
+func (r *passwordHistoryRepository) GetTableName() string {
+	return "password_history"
+}
+
+func (r *passwordHistoryRepository) Create(ctx context.Context, req *entity.PasswordHistory) (*entity.PasswordHistory, error) {
+	if req == nil {
+		return nil, handleDBError(exception.ErrDataNull, r.GetTableName(), "create password history")
+	}
+
+	history := model.AsPasswordHistory(req)
+	if _, err := r.db.NewInsert().Model(history).Returning("*").Exec(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "create password history")
+	}
+
+	return history.ToDomain(), nil
+}
+
+// FindRecent returns the user's latest password hashes, newest first.
+func (r *passwordHistoryRepository) FindRecent(ctx context.Context, userID uint, limit int) ([]*entity.PasswordHistory, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	var histories []*model.PasswordHistory
+
+	err := r.db.NewSelect().
+		Model(&histories).
+		Where("pwh.user_id = ?", userID).
+		Order("pwh.id DESC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "find recent password history")
+	}
+
+	return model.ToPasswordHistoriesDomain(histories), nil
+}
+
+// Prune deletes everything but the user's latest keep entries.
+func (r *passwordHistoryRepository) Prune(ctx context.Context, userID uint, keep int) error {
+	var cutoff uint
+
+	err := r.db.NewSelect().
+		Model((*model.PasswordHistory)(nil)).
+		Column("pwh.id").
+		Where("pwh.user_id = ?", userID).
+		Order("pwh.id DESC").
+		Offset(max(keep, 0)).
+		Limit(1).
+		Scan(ctx, &cutoff)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return handleDBError(err, r.GetTableName(), "find password history cutoff")
+	}
+
+	_, err = r.db.NewDelete().
+		Model((*model.PasswordHistory)(nil)).
+		Where("user_id = ?", userID).
+		Where("id <= ?", cutoff).
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "prune password history")
+	}
+
+	return nil
+}
@@ -6,15 +6,20 @@ import (
 	"goapptemp/internal/domain/entity"
 	"goapptemp/internal/shared/exception"
 	"goapptemp/pkg/logger"
+	"strings"
 
 	"github.com/uptrace/bun"
 )
 
 var _ PermissionRepository = (*permissionRepository)(nil)
 
+// likeEscaper keeps underscores in module names such as API_KEY from acting as LIKE wildcards.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type PermissionRepository interface {
 	GetTableName() string
 	Create(ctx context.Context, req *entity.Permission) (*entity.Permission, error)
+	Upsert(ctx context.Context, req []*entity.Permission) error
 	FindByID(ctx context.Context, id uint) (*entity.Permission, error)
 	Find(ctx context.Context, filter *FilterPermissionPayload) ([]*entity.Permission, int, error)
 	Update(ctx context.Context, req *UpdatePermissionPayload) (*entity.Permission, error)
@@ -47,6 +52,51 @@ func (r *permissionRepository) Create(ctx context.Context, req *entity.Permissio
 	return permission.ToDomain(), nil
 }
 
+// Upsert inserts the permissions and, for codes that already exist, refreshes the name and description
+// and restores the row if it was soft-deleted. Codes are only unique among live rows, so the most
+// recently deleted row of a code without a live one is restored first and the insert then updates it.
+func (r *permissionRepository) Upsert(ctx context.Context, req []*entity.Permission) error {
+	permissions := model.AsPermissions(req)
+	if len(permissions) == 0 {
+		return handleDBError(exception.ErrDataNull, r.GetTableName(), "upsert permissions")
+	}
+
+	codes := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		codes = append(codes, permission.Code)
+	}
+
+	restorable := r.db.NewSelect().
+		ColumnExpr("MAX(id) AS id").
+		Table(r.GetTableName()).
+		Where("code IN (?)", bun.In(codes)).
+		Group("code").
+		Having("SUM(deleted_at IS NULL) = 0")
+
+	// MySQL only lets an UPDATE read its own table through a materialized derived table.
+	_, err := r.db.NewUpdate().
+		Table(r.GetTableName()).
+		Set("deleted_at = NULL").
+		Where("id IN (SELECT id FROM (?) AS restorable)", restorable).
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "restore permissions")
+	}
+
+	_, err = r.db.NewInsert().
+		Model(&permissions).
+		On("DUPLICATE KEY UPDATE").
+		Set("name = VALUES(name)").
+		Set("description = VALUES(description)").
+		Set("deleted_at = NULL").
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "upsert permissions")
+	}
+
+	return nil
+}
+
 func (r *permissionRepository) FindByID(ctx context.Context, id uint) (*entity.Permission, error) {
 	if id == 0 {
 		return nil, handleDBError(exception.ErrIDNull, r.GetTableName(), "find permission by id")
@@ -64,6 +114,7 @@ type FilterPermissionPayload struct {
 	IDs     []uint
 	Codes   []string
 	Names   []string
+	Modules []string
 	Search  string
 	Page    int
 	PerPage int
@@ -91,6 +142,16 @@ func (r *permissionRepository) Find(ctx context.Context, filter *FilterPermissio
 		})
 	}
 
+	if len(filter.Modules) > 0 {
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			for i := range filter.Modules {
+				q = q.WhereOr("code LIKE ?", likeEscaper.Replace(filter.Modules[i])+".%")
+			}
+
+			return q
+		})
+	}
+
 	if filter.Search != "" {
 		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
 			q = q.WhereOr("LOWER(code) LIKE LOWER(?)", "%"+filter.Search+"%")
@@ -3,10 +3,12 @@ package mysqlrepository
 import (
 	"context"
 	"database/sql"
+	"goapptemp/constant"
 	"goapptemp/internal/adapter/repository/mysql/model"
 	"goapptemp/internal/domain/entity"
 	"goapptemp/internal/shared/exception"
 	"goapptemp/pkg/logger"
+	"slices"
 
 	"github.com/uptrace/bun"
 )
@@ -56,9 +58,14 @@ type FilterRolePayload struct {
 	Codes      []string
 	Names      []string
 	SuperAdmin *bool
-	Search     string
-	Page       int
-	PerPage    int
+	ParentIDs  []uint
+	CompanyIDs []uint
+	// Global alone selects global (true) or company (false) roles. Together with CompanyIDs, true
+	// adds the global roles to those companies' roles.
+	Global  *bool
+	Search  string
+	Page    int
+	PerPage int
 }
 
 func (r *roleRepository) Find(ctx context.Context, filter *FilterRolePayload) ([]*entity.Role, int, error) {
@@ -96,6 +103,23 @@ func (r *roleRepository) Find(ctx context.Context, filter *FilterRolePayload) ([
 		query = query.Where("rol.super_admin = ?", *filter.SuperAdmin)
 	}
 
+	if len(filter.ParentIDs) > 0 {
+		query = query.Where("rol.parent_id IN (?)", bun.In(filter.ParentIDs))
+	}
+
+	switch {
+	case len(filter.CompanyIDs) > 0 && filter.Global != nil && *filter.Global:
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("rol.company_id IN (?)", bun.In(filter.CompanyIDs)).WhereOr("rol.company_id IS NULL")
+		})
+	case len(filter.CompanyIDs) > 0:
+		query = query.Where("rol.company_id IN (?)", bun.In(filter.CompanyIDs))
+	case filter.Global != nil && *filter.Global:
+		query = query.Where("rol.company_id IS NULL")
+	case filter.Global != nil:
+		query = query.Where("rol.company_id IS NOT NULL")
+	}
+
 	totalCount, err := query.Clone().Count(ctx)
 	if err != nil {
 		return nil, 0, handleDBError(err, r.GetTableName(), "count role")
@@ -136,7 +160,9 @@ func (r *roleRepository) FindByID(ctx context.Context, id uint) (*entity.Role, e
 }
 
 type UpdateRolePayload struct {
-	ID            uint
+	ID uint
+	// ParentID changes the parent role; 0 removes it. The company of a role never changes.
+	ParentID      *uint
 	PermissionIDs []*uint
 	Code          *string
 	Name          *string
@@ -171,6 +197,13 @@ func (r *roleRepository) Update(ctx context.Context, req *UpdateRolePayload) (*e
 		role.SuperAdmin = *req.SuperAdmin
 	}
 
+	if req.ParentID != nil {
+		role.ParentID = req.ParentID
+		if *req.ParentID == 0 {
+			role.ParentID = nil
+		}
+	}
+
 	if _, err := r.db.NewUpdate().Model(role).WherePK().Exec(ctx); err != nil {
 		return nil, handleDBError(err, r.GetTableName(), "update role")
 	}
@@ -264,3 +297,41 @@ func (r *roleRepository) SyncPermissions(ctx context.Context, roleID uint, permi
 
 	return rolePermissions, nil
 }
+
+// withAncestorRoles adds every ancestor of roleIDs, walking one level of parents per query. The walk
+// stops after constant.MaxRoleDepth levels, so a cycle that slipped into the data cannot loop forever.
+func withAncestorRoles(ctx context.Context, db bun.IDB, roleIDs []uint) ([]uint, error) {
+	seen := make(map[uint]bool, len(roleIDs))
+	for _, id := range roleIDs {
+		seen[id] = true
+	}
+
+	result := slices.Clone(roleIDs)
+	frontier := roleIDs
+
+	for depth := 1; depth < constant.MaxRoleDepth && len(frontier) > 0; depth++ {
+		var parentIDs []uint
+
+		err := db.NewSelect().
+			Model((*model.Role)(nil)).
+			Column("rol.parent_id").
+			Where("rol.id IN (?)", bun.In(frontier)).
+			Where("rol.parent_id IS NOT NULL").
+			Scan(ctx, &parentIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		frontier = frontier[:0:0]
+
+		for _, id := range parentIDs {
+			if !seen[id] {
+				seen[id] = true
+				result = append(result, id)
+				frontier = append(frontier, id)
+			}
+		}
+	}
+
+	return result, nil
+}
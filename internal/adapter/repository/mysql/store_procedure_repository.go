@@ -5,146 +5,245 @@ import (
 	"database/sql"
 	"fmt"
 	"goapptemp/constant"
+	"goapptemp/internal/domain/entity"
 	"goapptemp/pkg/logger"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/cockroachdb/errors"
 	"github.com/uptrace/bun"
 )
 
 var _ StoreProcedureRepository = (*storeProcedureRepository)(nil)
 
 type StoreProcedureRepository interface {
-	CheckIfRecordsAreDeletable(ctx context.Context, parentTableName string, parentRecordIDs []uint, ignoreTables string) (map[uint]int, error)
+	LoadForeignKeys(ctx context.Context) error
+	CheckIfRecordsAreDeletable(ctx context.Context, parentTableName string, parentRecordIDs []uint, ignoreTables ...string) (map[uint]int, error)
+	FindDependencies(ctx context.Context, parentTableName string, parentRecordIDs []uint, ignoreTables ...string) (map[uint][]*entity.Dependency, error)
+	CascadeSoftDelete(ctx context.Context, parentTableName string, parentRecordIDs []uint, ignoreTables ...string) (int64, error)
 }
 
 type storeProcedureRepository struct {
 	dbName string
 	db     bun.IDB
+	graph  *foreignKeyGraph
 	logger logger.Logger
 }
 
-func NewStoreProcedureRepository(dbName string, db bun.IDB, logger logger.Logger) *storeProcedureRepository {
-	return &storeProcedureRepository{dbName: dbName, db: db, logger: logger}
+func NewStoreProcedureRepository(dbName string, db bun.IDB, graph *foreignKeyGraph, logger logger.Logger) *storeProcedureRepository {
+	return &storeProcedureRepository{dbName: dbName, db: db, graph: graph, logger: logger}
 }
 
-func (r *storeProcedureRepository) CheckIfRecordsAreDeletable(ctx context.Context, parentTableName string, parentRecordIDs []uint, ignoreTables string) (map[uint]int, error) {
-	var parentPK string
-
-	err := r.db.NewSelect().
-		Column("column_name").
-		Table("information_schema.columns").
-		Where("table_schema = ?", constant.ParentSchema).
-		Where("table_name = ?", parentTableName).
-		Where("column_key = 'PRI'").
-		Limit(1).
-		Scan(ctx, &parentPK)
+// LoadForeignKeys (re)reads the foreign key graph of the configured database. It runs at startup and
+// must run again after a migration changes the foreign keys.
+func (r *storeProcedureRepository) LoadForeignKeys(ctx context.Context) error {
+	return r.graph.load(ctx, r.db, r.dbName)
+}
+
+func (r *storeProcedureRepository) CheckIfRecordsAreDeletable(ctx context.Context, parentTableName string, parentRecordIDs []uint, ignoreTables ...string) (map[uint]int, error) {
+	dependencies, err := r.FindDependencies(ctx, parentTableName, parentRecordIDs, ignoreTables...)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("failed to get parent PK for table '%s': primary key not found", parentTableName)
+		return nil, err
+	}
+
+	result := make(map[uint]int, len(dependencies))
+
+	for parentID, items := range dependencies {
+		for _, item := range items {
+			result[parentID] += item.Count
 		}
+	}
 
-		return nil, fmt.Errorf("failed to get parent PK for table '%s': %w", parentTableName, err)
+	return result, nil
+}
+
+// FindDependencies reports, per parent record, how many live rows of each referencing table point
+// at it, with a sample of their IDs. Records without dependencies are absent from the result.
+func (r *storeProcedureRepository) FindDependencies(ctx context.Context, parentTableName string, parentRecordIDs []uint, ignoreTables ...string) (map[uint][]*entity.Dependency, error) {
+	refs, err := r.references(ctx, parentTableName, ignoreTables)
+	if err != nil {
+		return nil, err
 	}
 
-	if parentPK == "" {
-		return nil, fmt.Errorf("failed to get parent PK for table '%s': primary key not found", parentTableName)
+	result := make(map[uint][]*entity.Dependency)
+	if len(refs) == 0 || len(parentRecordIDs) == 0 {
+		return result, nil
 	}
 
-	type FKReference struct {
-		ChildSchema   string       `bun:"child_schema"`
-		ChildTable    string       `bun:"child_table"`
-		ChildFKColumn string       `bun:"child_fk_column"`
-		HasSoftDelete sql.NullBool `bun:"has_soft_delete_column"`
+	var union *bun.SelectQuery
+
+	for _, ref := range refs {
+		q := r.db.NewSelect().
+			ColumnExpr("? AS parent_id", bun.Ident(ref.ChildColumn)).
+			ColumnExpr("? AS child_table", ref.ChildTable).
+			ColumnExpr("? AS child_column", ref.ChildColumn).
+			ColumnExpr("COUNT(*) AS dependency_count").
+			TableExpr("?.?", bun.Ident(r.dbName), bun.Ident(ref.ChildTable)).
+			Where("? IN (?)", bun.Ident(ref.ChildColumn), bun.In(parentRecordIDs)).
+			GroupExpr("?", bun.Ident(ref.ChildColumn))
+
+		if ref.ChildPK != "" {
+			q.ColumnExpr("SUBSTRING_INDEX(GROUP_CONCAT(? ORDER BY ?), ',', ?) AS sample_ids",
+				bun.Ident(ref.ChildPK), bun.Ident(ref.ChildPK), constant.DependencySampleSize)
+		} else {
+			q.ColumnExpr("CAST(NULL AS CHAR) AS sample_ids")
+		}
+
+		if ref.SoftDelete {
+			q.Where("? IS NULL", bun.Ident(constant.SoftDeleteColumnName))
+		}
+
+		if union == nil {
+			union = q
+		} else {
+			union.UnionAll(q)
+		}
 	}
 
-	var refs []FKReference
-
-	query := `
-     SELECT DISTINCT
-       kcu.table_schema AS child_schema,
-       kcu.table_name AS child_table,
-       kcu.column_name AS child_fk_column,
-       EXISTS (
-         SELECT 1
-         FROM information_schema.columns AS c
-         WHERE c.table_schema = kcu.table_schema
-         AND c.table_name = kcu.table_name
-         AND c.column_name = ?
-       ) AS has_soft_delete_column
-     FROM information_schema.key_column_usage AS kcu
-     WHERE kcu.referenced_table_schema = ?
-       AND kcu.referenced_table_name = ?
-       AND kcu.referenced_column_name = ?
-   `
-
-	if err := r.db.NewRaw(query, constant.SoftDeleteColumnName, constant.ParentSchema, parentTableName, parentPK).
-		Scan(ctx, &refs); err != nil {
-		return nil, fmt.Errorf("error fetching foreign key references: %w", err)
+	type DependencyResult struct {
+		ParentID        uint           `bun:"parent_id"`
+		ChildTable      string         `bun:"child_table"`
+		ChildColumn     string         `bun:"child_column"`
+		DependencyCount int            `bun:"dependency_count"`
+		SampleIDs       sql.NullString `bun:"sample_ids"`
 	}
 
-	if len(refs) == 0 {
-		return make(map[uint]int), nil
+	var scanResults []DependencyResult
+
+	err = r.db.NewSelect().
+		TableExpr("(?) AS dependency_counts", union).
+		OrderExpr("parent_id, child_table, child_column").
+		Scan(ctx, &scanResults)
+	if err != nil {
+		return nil, fmt.Errorf("executing dependency check failed: %w", err)
 	}
 
-	idStrings := make([]string, 0, len(parentRecordIDs))
-	for _, id := range parentRecordIDs {
-		idStrings = append(idStrings, strconv.FormatUint(uint64(id), 10))
+	softDelete := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		softDelete[ref.ChildTable] = ref.SoftDelete
 	}
 
-	idList := strings.Join(idStrings, ",")
-	if idList == "" {
-		return make(map[uint]int), nil
+	for _, item := range scanResults {
+		dependency := &entity.Dependency{
+			Table:         item.ChildTable,
+			Column:        item.ChildColumn,
+			Count:         item.DependencyCount,
+			SoftDeletable: softDelete[item.ChildTable],
+		}
+
+		if item.SampleIDs.Valid && item.SampleIDs.String != "" {
+			for _, raw := range strings.Split(item.SampleIDs.String, ",") {
+				id, err := strconv.ParseUint(raw, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse dependency sample of table '%s': %w", item.ChildTable, err)
+				}
+
+				dependency.SampleIDs = append(dependency.SampleIDs, uint(id))
+			}
+		}
+
+		result[item.ParentID] = append(result[item.ParentID], dependency)
 	}
 
-	unionQueries := make([]string, 0)
+	return result, nil
+}
+
+// CascadeSoftDelete soft-deletes the live rows referencing the parent records in tables that have a
+// deleted_at column, then the rows referencing those, and so on. Tables without the column are left
+// alone, so callers check them with FindDependencies first. The ignored tables only apply to the
+// parent's direct references. It returns the number of rows soft-deleted.
+func (r *storeProcedureRepository) CascadeSoftDelete(ctx context.Context, parentTableName string, parentRecordIDs []uint, ignoreTables ...string) (int64, error) {
+	return r.cascadeSoftDelete(ctx, parentTableName, parentRecordIDs, ignoreTables, time.Now())
+}
+
+func (r *storeProcedureRepository) cascadeSoftDelete(ctx context.Context, parentTableName string, parentRecordIDs []uint, ignoreTables []string, now time.Time) (int64, error) {
+	if len(parentRecordIDs) == 0 {
+		return 0, nil
+	}
+
+	refs, err := r.references(ctx, parentTableName, ignoreTables)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
 
 	for _, ref := range refs {
-		if ignoreTables != "" && strings.Contains(ignoreTables, ref.ChildTable) {
+		if !ref.SoftDelete {
 			continue
 		}
 
-		q := fmt.Sprintf(`
-          SELECT %s AS parent_id, COUNT(*) AS dependency_count
-          FROM %s.%s
-          WHERE %s IN (%s)`,
-			ref.ChildFKColumn,
-			ref.ChildSchema,
-			ref.ChildTable,
-			ref.ChildFKColumn,
-			idList,
-		)
-
-		if ref.HasSoftDelete.Valid && ref.HasSoftDelete.Bool {
-			q += fmt.Sprintf(" AND %s IS NULL", constant.SoftDeleteColumnName)
+		table := bun.Ident(ref.ChildTable)
+		column := bun.Ident(ref.ChildColumn)
+		deletedAt := bun.Ident(constant.SoftDeleteColumnName)
+
+		// Rows of tables without a single-column key cannot be referenced in turn, so there is
+		// nothing to recurse into.
+		if ref.ChildPK == "" {
+			res, err := r.db.NewUpdate().
+				TableExpr("?.?", bun.Ident(r.dbName), table).
+				Set("? = ?", deletedAt, now).
+				Where("? IN (?)", column, bun.In(parentRecordIDs)).
+				Where("? IS NULL", deletedAt).
+				Exec(ctx)
+			if err != nil {
+				return total, fmt.Errorf("failed to cascade soft delete to table '%s': %w", ref.ChildTable, err)
+			}
+
+			affected, _ := res.RowsAffected()
+			total += affected
+
+			continue
 		}
 
-		q += " GROUP BY " + ref.ChildFKColumn
-		unionQueries = append(unionQueries, q)
-	}
+		var childIDs []uint
 
-	if len(unionQueries) == 0 {
-		return make(map[uint]int), nil
-	}
+		err := r.db.NewSelect().
+			ColumnExpr("?", bun.Ident(ref.ChildPK)).
+			TableExpr("?.?", bun.Ident(r.dbName), table).
+			Where("? IN (?)", column, bun.In(parentRecordIDs)).
+			Where("? IS NULL", deletedAt).
+			Scan(ctx, &childIDs)
+		if err != nil {
+			return total, fmt.Errorf("failed to find rows of table '%s' to cascade soft delete: %w", ref.ChildTable, err)
+		}
 
-	fullQuery := "SELECT parent_id, SUM(dependency_count) AS total_dependencies FROM (" +
-		strings.Join(unionQueries, " UNION ALL ") + ") AS final_counts GROUP BY parent_id"
+		if len(childIDs) == 0 {
+			continue
+		}
 
-	type DependencyResult struct {
-		ParentID          uint `bun:"parent_id"`
-		TotalDependencies int  `bun:"total_dependencies"`
-	}
+		_, err = r.db.NewUpdate().
+			TableExpr("?.?", bun.Ident(r.dbName), table).
+			Set("? = ?", deletedAt, now).
+			Where("? IN (?)", bun.Ident(ref.ChildPK), bun.In(childIDs)).
+			Exec(ctx)
+		if err != nil {
+			return total, fmt.Errorf("failed to cascade soft delete to table '%s': %w", ref.ChildTable, err)
+		}
 
-	var scanResults []DependencyResult
-	if err := r.db.NewRaw(fullQuery).Scan(ctx, &scanResults); err != nil {
-		return nil, fmt.Errorf("executing dependency check failed: %w", err)
+		total += int64(len(childIDs))
+
+		// Only rows that were still live are selected, so self references such as roles.parent_id
+		// terminate once a subtree is fully deleted.
+		affected, err := r.cascadeSoftDelete(ctx, ref.ChildTable, childIDs, nil, now)
+		total += affected
+
+		if err != nil {
+			return total, err
+		}
 	}
 
-	result := make(map[uint]int)
-	for _, item := range scanResults {
-		result[item.ParentID] = item.TotalDependencies
+	return total, nil
+}
+
+func (r *storeProcedureRepository) references(ctx context.Context, parentTableName string, ignoreTables []string) ([]foreignKey, error) {
+	refs, err := r.graph.referencesTo(ctx, r.db, r.dbName, parentTableName)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return slices.DeleteFunc(slices.Clone(refs), func(ref foreignKey) bool {
+		return slices.Contains(ignoreTables, ref.ChildTable)
+	}), nil
 }
@@ -0,0 +1,81 @@
+package mysqlrepository
+
+import (
+	"context"
+	"goapptemp/internal/adapter/repository/mysql/model"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+var _ UserIdentityRepository = (*userIdentityRepository)(nil)
+
+type UserIdentityRepository interface {
+	GetTableName() string
+	Create(ctx context.Context, req *entity.UserIdentity) (*entity.UserIdentity, error)
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+	TouchLogin(ctx context.Context, id uint, email string, at time.Time) error
+}
+
+type userIdentityRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewUserIdentityRepository(db bun.IDB, logger logger.Logger) *userIdentityRepository {
+	return &userIdentityRepository{db: db, logger: logger}
+}
+
+func (r *userIdentityRepository) GetTableName() string {
+	return "user_identities"
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, req *entity.UserIdentity) (*entity.UserIdentity, error) {
+	if req == nil {
+		return nil, handleDBError(exception.ErrDataNull, r.GetTableName(), "create user identity")
+	}
+
+	identity := model.AsUserIdentity(req)
+	if _, err := r.db.NewInsert().Model(identity).Returning("*").Exec(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "create user identity")
+	}
+
+	return identity.ToDomain(), nil
+}
+
+func (r *userIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	identity := new(model.UserIdentity)
+
+	err := r.db.NewSelect().
+		Model(identity).
+		Where("uid.provider = ?", provider).
+		Where("uid.subject = ?", subject).
+		Scan(ctx)
+	if err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "find user identity by subject")
+	}
+
+	return identity.ToDomain(), nil
+}
+
+// TouchLogin records the login and keeps the email in sync with what the provider last asserted.
+func (r *userIdentityRepository) TouchLogin(ctx context.Context, id uint, email string, at time.Time) error {
+	if id == 0 {
+		return handleDBError(exception.ErrIDNull, r.GetTableName(), "touch user identity")
+	}
+
+	_, err := r.db.NewUpdate().
+		Model((*model.UserIdentity)(nil)).
+		Set("email = ?", email).
+		Set("last_login_at = ?", at).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "touch user identity")
+	}
+
+	return nil
+}
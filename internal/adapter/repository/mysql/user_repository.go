@@ -7,6 +7,9 @@ import (
 	"goapptemp/internal/domain/entity"
 	"goapptemp/internal/shared/exception"
 	"goapptemp/pkg/logger"
+	"goapptemp/pkg/policy"
+	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/uptrace/bun"
@@ -21,10 +24,13 @@ type UserRepository interface {
 	Find(ctx context.Context, filter *FilterUserPayload) ([]*entity.User, int, error)
 	Update(ctx context.Context, req *UpdateUserPayload) (*entity.User, error)
 	Delete(ctx context.Context, id uint) error
+	SetStatus(ctx context.Context, req *SetUserStatusPayload) error
+	Activate(ctx context.Context, id uint, password string, changedAt time.Time) error
 	AttachRoles(ctx context.Context, userID uint, roleIDs []uint) ([]*entity.UserRole, error)
 	DetachRoles(ctx context.Context, userID uint, roleIDs []uint) error
 	SyncRoles(ctx context.Context, userID uint, roleIDs []uint) ([]*entity.UserRole, error)
 	HasPermission(ctx context.Context, userID uint, permissionCode string) (bool, error)
+	FindExistingUsernamesAndEmails(ctx context.Context, companyID uint, usernames []string, emails []string) (existingUsernames map[string]struct{}, existingEmails map[string]struct{}, err error)
 }
 
 type userRepository struct {
@@ -54,13 +60,23 @@ func (r *userRepository) Create(ctx context.Context, req *entity.User) (*entity.
 }
 
 type FilterUserPayload struct {
-	IDs       []uint
-	Fullnames []string
-	Usernames []string
-	Emails    []string
-	Search    string
-	Page      int
-	PerPage   int
+	IDs        []uint
+	CompanyIDs []uint
+	Fullnames  []string
+	Usernames  []string
+	Emails     []string
+	Statuses   []entity.UserStatus
+	Search     string
+	Policy     *policy.Filter
+	Page       int
+	PerPage    int
+}
+
+// userPolicyColumns maps the user attributes access policies may reference to their columns.
+var userPolicyColumns = map[string]string{
+	"id":         "usr.id",
+	"company_id": "usr.company_id",
+	"status":     "usr.status",
 }
 
 func (r *userRepository) Find(ctx context.Context, filter *FilterUserPayload) ([]*entity.User, int, error) {
@@ -71,6 +87,10 @@ func (r *userRepository) Find(ctx context.Context, filter *FilterUserPayload) ([
 		query = query.Where("usr.id IN (?)", bun.In(filter.IDs))
 	}
 
+	if len(filter.CompanyIDs) > 0 {
+		query = query.Where("usr.company_id IN (?)", bun.In(filter.CompanyIDs))
+	}
+
 	if len(filter.Emails) > 0 {
 		query = query.Where("usr.email IN (?)", bun.In(filter.Emails))
 	}
@@ -79,6 +99,10 @@ func (r *userRepository) Find(ctx context.Context, filter *FilterUserPayload) ([
 		query = query.Where("usr.username IN (?)", bun.In(filter.Usernames))
 	}
 
+	if len(filter.Statuses) > 0 {
+		query = query.Where("usr.status IN (?)", bun.In(filter.Statuses))
+	}
+
 	if len(filter.Fullnames) > 0 {
 		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
 			for i := range filter.Fullnames {
@@ -99,6 +123,8 @@ func (r *userRepository) Find(ctx context.Context, filter *FilterUserPayload) ([
 		})
 	}
 
+	query = applyPolicyFilter(query, filter.Policy, userPolicyColumns)
+
 	totalCount, err := query.Clone().Count(ctx)
 	if err != nil {
 		return nil, 0, handleDBError(err, r.GetTableName(), "count user")
@@ -139,12 +165,15 @@ func (r *userRepository) FindByID(ctx context.Context, id uint) (*entity.User, e
 }
 
 type UpdateUserPayload struct {
-	ID       uint
-	RoleIDs  []*uint
-	Fullname *string
-	Username *string
-	Email    *string
-	Password *string
+	ID                uint
+	RoleIDs           []*uint
+	Fullname          *string
+	Username          *string
+	Email             *string
+	Password          *string
+	Locale            *string
+	Status            *entity.UserStatus
+	PasswordChangedAt *time.Time
 }
 
 func (r *userRepository) Update(ctx context.Context, req *UpdateUserPayload) (*entity.User, error) {
@@ -182,6 +211,24 @@ func (r *userRepository) Update(ctx context.Context, req *UpdateUserPayload) (*e
 		columnsToUpdate = append(columnsToUpdate, "password")
 	}
 
+	if req.Status != nil && *req.Status != "" {
+		userModel.Status = string(*req.Status)
+
+		columnsToUpdate = append(columnsToUpdate, "status")
+	}
+
+	if req.PasswordChangedAt != nil {
+		userModel.PasswordChangedAt = req.PasswordChangedAt
+
+		columnsToUpdate = append(columnsToUpdate, "password_changed_at")
+	}
+
+	if req.Locale != nil && *req.Locale != "" {
+		userModel.Locale = *req.Locale
+
+		columnsToUpdate = append(columnsToUpdate, "locale")
+	}
+
 	if len(columnsToUpdate) == 0 {
 		currentUser, err := r.FindByID(ctx, req.ID)
 		if err != nil {
@@ -202,6 +249,73 @@ func (r *userRepository) Update(ctx context.Context, req *UpdateUserPayload) (*e
 	return userModel.ToDomain(), nil
 }
 
+type SetUserStatusPayload struct {
+	ID               uint
+	Status           entity.UserStatus
+	SuspendedAt      *time.Time
+	SuspensionReason string
+}
+
+// SetStatus writes the status together with the suspension details, clearing them when they are unset.
+func (r *userRepository) SetStatus(ctx context.Context, req *SetUserStatusPayload) error {
+	if req.ID == 0 {
+		return handleDBError(exception.ErrIDNull, r.GetTableName(), "set user status")
+	}
+
+	userModel := &model.User{
+		Base:             model.Base{ID: req.ID},
+		Status:           string(req.Status),
+		SuspendedAt:      req.SuspendedAt,
+		SuspensionReason: req.SuspensionReason,
+	}
+
+	res, err := r.db.NewUpdate().
+		Model(userModel).
+		Column("status", "suspended_at", "suspension_reason").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "set user status")
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected == 0 {
+		return handleDBError(sql.ErrNoRows, r.GetTableName(), "set user status")
+	}
+
+	return nil
+}
+
+// Activate sets the password of a pending user and makes it active. It fails with not found when the
+// user is no longer pending, so only one of two concurrent activations succeeds.
+func (r *userRepository) Activate(ctx context.Context, id uint, password string, changedAt time.Time) error {
+	if id == 0 {
+		return handleDBError(exception.ErrIDNull, r.GetTableName(), "activate user")
+	}
+
+	userModel := &model.User{
+		Base:              model.Base{ID: id},
+		Password:          password,
+		PasswordChangedAt: &changedAt,
+		Status:            string(entity.UserStatusActive),
+	}
+
+	res, err := r.db.NewUpdate().
+		Model(userModel).
+		Column("password", "password_changed_at", "status").
+		WherePK().
+		Where("status = ?", entity.UserStatusPending).
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "activate user")
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected == 0 {
+		return handleDBError(sql.ErrNoRows, r.GetTableName(), "activate user")
+	}
+
+	return nil
+}
+
 func (r *userRepository) Delete(ctx context.Context, id uint) error {
 	if id == 0 {
 		return handleDBError(exception.ErrIDNull, r.GetTableName(), "delete user")
@@ -317,21 +431,39 @@ func (r *userRepository) HasPermission(ctx context.Context, userID uint, permiss
 		return false, handleDBError(exception.ErrDataNull, r.GetTableName(), "check permission: permission code is empty")
 	}
 
-	superAdminQuery := r.db.NewSelect().
-		Model((*model.UserRole)(nil)).
-		Join("JOIN ? AS r ON r.id = user_role.role_id", r.db.NewSelect().Model((*model.Role)(nil))).
-		Where("user_role.user_id = ?", userID).
-		Where("r.super_admin = ?", true)
+	var roleIDs []uint
+	if err := r.db.NewSelect().Model((*model.UserRole)(nil)).Column("role_id").Where("user_id = ?", userID).Scan(ctx, &roleIDs); err != nil {
+		return false, handleDBError(err, r.GetTableName(), "check permission")
+	}
 
-	permissionQuery := r.db.NewSelect().
-		Model((*model.UserRole)(nil)).
-		Join("JOIN ? AS rp ON rp.role_id = user_role.role_id", r.db.NewSelect().Model((*model.RolePermission)(nil))).
-		Join("JOIN ? AS p ON p.id = rp.permission_id", r.db.NewSelect().Model((*model.Permission)(nil))).
-		Where("user_role.user_id = ?", userID).
-		Where("p.code = ?", permissionCode)
+	if len(roleIDs) == 0 {
+		return false, nil
+	}
+
+	// super_admin is not inherited, so only the user's own roles are checked for it.
+	isSuperAdmin, err := r.db.NewSelect().
+		Model((*model.Role)(nil)).
+		Where("rol.id IN (?)", bun.In(roleIDs)).
+		Where("rol.super_admin = ?", true).
+		Exists(ctx)
+	if err != nil {
+		return false, handleDBError(err, r.GetTableName(), "check permission")
+	}
+
+	if isSuperAdmin {
+		return true, nil
+	}
+
+	roleIDs, err = withAncestorRoles(ctx, r.db, roleIDs)
+	if err != nil {
+		return false, handleDBError(err, r.GetTableName(), "check permission")
+	}
 
 	hasPermission, err := r.db.NewSelect().
-		TableExpr("(?) AS combined_query", superAdminQuery.Union(permissionQuery)).
+		Model((*model.RolePermission)(nil)).
+		Join("JOIN permissions AS p ON p.id = rolperm.permission_id AND p.deleted_at IS NULL").
+		Where("rolperm.role_id IN (?)", bun.In(roleIDs)).
+		Where("p.code = ?", permissionCode).
 		Exists(ctx)
 	if err != nil {
 		return false, handleDBError(err, r.GetTableName(), "check permission")
@@ -339,3 +471,49 @@ func (r *userRepository) HasPermission(ctx context.Context, userID uint, permiss
 
 	return hasPermission, nil
 }
+
+// FindExistingUsernamesAndEmails reports which of the given usernames and emails are already taken
+// by users of the company, keyed in lower case.
+func (r *userRepository) FindExistingUsernamesAndEmails(ctx context.Context, companyID uint, usernames []string, emails []string) (map[string]struct{}, map[string]struct{}, error) {
+	existingUsernames := make(map[string]struct{})
+	existingEmails := make(map[string]struct{})
+
+	if len(usernames) == 0 && len(emails) == 0 {
+		return existingUsernames, existingEmails, nil
+	}
+
+	var results []struct {
+		Username string `bun:"username"`
+		Email    string `bun:"email"`
+	}
+
+	query := r.db.NewSelect().Model((*model.User)(nil)).
+		Column("username", "email").
+		Where("usr.company_id = ?", companyID).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			if len(usernames) > 0 {
+				q = q.WhereOr("usr.username_active IN (?)", bun.In(usernames))
+			}
+
+			if len(emails) > 0 {
+				q = q.WhereOr("usr.email_active IN (?)", bun.In(emails))
+			}
+
+			return q
+		})
+
+	if err := query.Scan(ctx, &results); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return existingUsernames, existingEmails, nil
+		}
+
+		return nil, nil, handleDBError(err, r.GetTableName(), "find existing usernames and emails")
+	}
+
+	for _, result := range results {
+		existingUsernames[strings.ToLower(result.Username)] = struct{}{}
+		existingEmails[strings.ToLower(result.Email)] = struct{}{}
+	}
+
+	return existingUsernames, existingEmails, nil
+}
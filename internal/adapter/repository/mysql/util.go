@@ -1,6 +1,11 @@
 package mysqlrepository
 
-import "github.com/uptrace/bun"
+import (
+	"goapptemp/pkg/policy"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
 
 func applyMultiLikeFilter(q *bun.SelectQuery, fieldExpr string, values []string) *bun.SelectQuery {
 	if len(values) == 0 {
@@ -15,3 +20,67 @@ func applyMultiLikeFilter(q *bun.SelectQuery, fieldExpr string, values []string)
 		return sq
 	})
 }
+
+// applyPolicyFilter restricts q to the rows the access policies allow. columns maps policy field
+// names to column expressions; a predicate on an unmapped field matches nothing, so a policy can
+// never widen access by naming a field the repository does not know.
+func applyPolicyFilter(q *bun.SelectQuery, filter *policy.Filter, columns map[string]string) *bun.SelectQuery {
+	if filter == nil {
+		return q
+	}
+
+	if filter.Allow != nil {
+		clauses := make([]string, 0, len(filter.Allow))
+		args := []any{}
+
+		for _, clause := range filter.Allow {
+			expr, clauseArgs := policyClauseExpr(clause, columns)
+			clauses = append(clauses, expr)
+			args = append(args, clauseArgs...)
+		}
+
+		if len(clauses) == 0 {
+			clauses = append(clauses, "1 = 0")
+		}
+
+		q = q.Where(strings.Join(clauses, " OR "), args...)
+	}
+
+	for _, clause := range filter.Deny {
+		expr, args := policyClauseExpr(clause, columns)
+		q = q.Where("NOT "+expr, args...)
+	}
+
+	return q
+}
+
+// policyClauseExpr renders the predicates of one clause as a parenthesized conjunction.
+func policyClauseExpr(clause []policy.Predicate, columns map[string]string) (string, []any) {
+	if len(clause) == 0 {
+		return "(1 = 1)", nil
+	}
+
+	parts := make([]string, 0, len(clause))
+	args := []any{}
+
+	for _, predicate := range clause {
+		column, ok := columns[predicate.Field]
+
+		switch {
+		case !ok:
+			parts = append(parts, "1 = 0")
+		case predicate.Op == policy.OpNe && len(predicate.Values) == 0:
+			parts = append(parts, "1 = 1")
+		case predicate.Op == policy.OpNe:
+			parts = append(parts, "(? IS NULL OR ? NOT IN (?))")
+			args = append(args, bun.Safe(column), bun.Safe(column), bun.In(predicate.Values))
+		case len(predicate.Values) == 0:
+			parts = append(parts, "1 = 0")
+		default:
+			parts = append(parts, "? IN (?)")
+			args = append(args, bun.Safe(column), bun.In(predicate.Values))
+		}
+	}
+
+	return "(" + strings.Join(parts, " AND ") + ")", args
+}
@@ -0,0 +1,237 @@
+package mysqlrepository
+
+import (
+	"context"
+	"goapptemp/internal/adapter/repository/mysql/model"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+var _ WebhookDeliveryRepository = (*webhookDeliveryRepository)(nil)
+
+type WebhookDeliveryRepository interface {
+	GetTableName() string
+	Create(ctx context.Context, req *entity.WebhookDelivery) (*entity.WebhookDelivery, error)
+	BulkCreate(ctx context.Context, req []*entity.WebhookDelivery) error
+	FindByID(ctx context.Context, id uint64) (*entity.WebhookDelivery, error)
+	Find(ctx context.Context, filter *FilterWebhookDeliveryPayload) ([]*entity.WebhookDelivery, int, error)
+	FindDue(ctx context.Context, limit int) ([]*entity.WebhookDelivery, error)
+	Claim(ctx context.Context, id uint64) (bool, error)
+	Complete(ctx context.Context, req *entity.WebhookDelivery) error
+	CancelPending(ctx context.Context, subscriptionID uint, reason string) error
+	ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error)
+}
+
+type webhookDeliveryRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewWebhookDeliveryRepository(db bun.IDB, logger logger.Logger) *webhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db, logger: logger}
+}
+
+func (r *webhookDeliveryRepository) GetTableName() string {
+	return "webhook_deliveries"
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, req *entity.WebhookDelivery) (*entity.WebhookDelivery, error) {
+	if req == nil {
+		return nil, handleDBError(exception.ErrDataNull, r.GetTableName(), "create webhook delivery")
+	}
+
+	delivery := model.AsWebhookDelivery(req)
+	if _, err := r.db.NewInsert().Model(delivery).Exec(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "create webhook delivery")
+	}
+
+	return delivery.ToDomain(), nil
+}
+
+// BulkCreate skips deliveries that already exist for the same subscription, event and attempt, so
+// concurrent dispatches of one event queue it once.
+func (r *webhookDeliveryRepository) BulkCreate(ctx context.Context, req []*entity.WebhookDelivery) error {
+	if len(req) == 0 {
+		return handleDBError(exception.ErrDataNull, r.GetTableName(), "bulk create webhook deliveries")
+	}
+
+	deliveries := model.AsWebhookDeliveries(req)
+	_, err := r.db.NewInsert().
+		Model(&deliveries).
+		On("DUPLICATE KEY UPDATE").
+		Set("id = id").
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "bulk create webhook deliveries")
+	}
+
+	return nil
+}
+
+func (r *webhookDeliveryRepository) FindByID(ctx context.Context, id uint64) (*entity.WebhookDelivery, error) {
+	if id == 0 {
+		return nil, handleDBError(exception.ErrIDNull, r.GetTableName(), "find webhook delivery by id")
+	}
+
+	delivery := &model.WebhookDelivery{ID: id}
+	if err := r.db.NewSelect().Model(delivery).WherePK().Scan(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "find webhook delivery by id")
+	}
+
+	return delivery.ToDomain(), nil
+}
+
+type FilterWebhookDeliveryPayload struct {
+	SubscriptionIDs []uint
+	Statuses        []string
+	EventID         string
+	EventTypes      []string
+	Page            int
+	PerPage         int
+}
+
+func (r *webhookDeliveryRepository) Find(ctx context.Context, filter *FilterWebhookDeliveryPayload) ([]*entity.WebhookDelivery, int, error) {
+	var deliveries []*model.WebhookDelivery
+
+	query := r.db.NewSelect().Model(&deliveries)
+	if len(filter.SubscriptionIDs) > 0 {
+		query = query.Where("whd.subscription_id IN (?)", bun.In(filter.SubscriptionIDs))
+	}
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where("whd.status IN (?)", bun.In(filter.Statuses))
+	}
+
+	if filter.EventID != "" {
+		query = query.Where("whd.event_id = ?", filter.EventID)
+	}
+
+	if len(filter.EventTypes) > 0 {
+		query = query.Where("whd.event_type IN (?)", bun.In(filter.EventTypes))
+	}
+
+	totalCount, err := query.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, handleDBError(err, r.GetTableName(), "count webhook delivery")
+	}
+
+	if totalCount == 0 {
+		return []*entity.WebhookDelivery{}, 0, nil
+	}
+
+	if filter.PerPage > 0 {
+		query = query.Limit(filter.PerPage)
+	}
+
+	if filter.Page > 0 && filter.PerPage > 0 {
+		offset := (filter.Page - 1) * filter.PerPage
+		query = query.Offset(offset)
+	}
+
+	query = query.Order("whd.id DESC")
+	if err = query.Scan(ctx); err != nil {
+		return nil, 0, handleDBError(err, r.GetTableName(), "find webhook delivery")
+	}
+
+	return model.ToWebhookDeliveriesDomain(deliveries), totalCount, nil
+}
+
+func (r *webhookDeliveryRepository) FindDue(ctx context.Context, limit int) ([]*entity.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+
+	query := r.db.NewSelect().
+		Model(&deliveries).
+		Where("whd.status = ?", string(entity.WebhookDeliveryPending)).
+		Where("whd.scheduled_at <= ?", time.Now()).
+		Order("whd.scheduled_at ASC", "whd.id ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "find due webhook deliveries")
+	}
+
+	return model.ToWebhookDeliveriesDomain(deliveries), nil
+}
+
+// Claim moves a pending delivery to processing; false means another worker got it first.
+func (r *webhookDeliveryRepository) Claim(ctx context.Context, id uint64) (bool, error) {
+	if id == 0 {
+		return false, handleDBError(exception.ErrIDNull, r.GetTableName(), "claim webhook delivery")
+	}
+
+	res, err := r.db.NewUpdate().
+		Model((*model.WebhookDelivery)(nil)).
+		Set("status = ?", string(entity.WebhookDeliveryProcessing)).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("status = ?", string(entity.WebhookDeliveryPending)).
+		Exec(ctx)
+	if err != nil {
+		return false, handleDBError(err, r.GetTableName(), "claim webhook delivery")
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+
+	return rowsAffected == 1, nil
+}
+
+func (r *webhookDeliveryRepository) Complete(ctx context.Context, req *entity.WebhookDelivery) error {
+	if req == nil || req.ID == 0 {
+		return handleDBError(exception.ErrIDNull, r.GetTableName(), "complete webhook delivery")
+	}
+
+	delivery := model.AsWebhookDelivery(req)
+
+	_, err := r.db.NewUpdate().
+		Model(delivery).
+		Column("status", "response_status", "response_body", "error", "duration_ms", "attempted_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "complete webhook delivery")
+	}
+
+	return nil
+}
+
+func (r *webhookDeliveryRepository) CancelPending(ctx context.Context, subscriptionID uint, reason string) error {
+	if subscriptionID == 0 {
+		return handleDBError(exception.ErrIDNull, r.GetTableName(), "cancel pending webhook deliveries")
+	}
+
+	_, err := r.db.NewUpdate().
+		Model((*model.WebhookDelivery)(nil)).
+		Set("status = ?", string(entity.WebhookDeliveryCancelled)).
+		Set("error = ?", reason).
+		Where("subscription_id = ?", subscriptionID).
+		Where("status = ?", string(entity.WebhookDeliveryPending)).
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "cancel pending webhook deliveries")
+	}
+
+	return nil
+}
+
+// ReleaseStale hands deliveries claimed by a worker that died mid-attempt back to the queue.
+func (r *webhookDeliveryRepository) ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error) {
+	res, err := r.db.NewUpdate().
+		Model((*model.WebhookDelivery)(nil)).
+		Set("status = ?", string(entity.WebhookDeliveryPending)).
+		Where("status = ?", string(entity.WebhookDeliveryProcessing)).
+		Where("updated_at < ?", claimedBefore).
+		Exec(ctx)
+	if err != nil {
+		return 0, handleDBError(err, r.GetTableName(), "release stale webhook deliveries")
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+
+	return int(rowsAffected), nil
+}
@@ -0,0 +1,271 @@
+package mysqlrepository
+
+import (
+	"context"
+	"database/sql"
+	"goapptemp/internal/adapter/repository/mysql/model"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+var _ WebhookSubscriptionRepository = (*webhookSubscriptionRepository)(nil)
+
+type WebhookSubscriptionRepository interface {
+	GetTableName() string
+	Create(ctx context.Context, req *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	FindByID(ctx context.Context, id uint) (*entity.WebhookSubscription, error)
+	Find(ctx context.Context, filter *FilterWebhookSubscriptionPayload) ([]*entity.WebhookSubscription, int, error)
+	Update(ctx context.Context, req *UpdateWebhookSubscriptionPayload) (*entity.WebhookSubscription, error)
+	Delete(ctx context.Context, id uint) error
+	IncrementFailures(ctx context.Context, id uint) (uint, error)
+	ResetFailures(ctx context.Context, id uint) error
+	Disable(ctx context.Context, id uint, reason string) error
+}
+
+type webhookSubscriptionRepository struct {
+	db     bun.IDB
+	logger logger.Logger
+}
+
+func NewWebhookSubscriptionRepository(db bun.IDB, logger logger.Logger) *webhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{db: db, logger: logger}
+}
+
+func (r *webhookSubscriptionRepository) GetTableName() string {
+	return "webhook_subscriptions"
+}
+
+func (r *webhookSubscriptionRepository) Create(ctx context.Context, req *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	if req == nil {
+		return nil, handleDBError(exception.ErrDataNull, r.GetTableName(), "create webhook subscription")
+	}
+
+	subscription := model.AsWebhookSubscription(req)
+	if _, err := r.db.NewInsert().Model(subscription).Exec(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "create webhook subscription")
+	}
+
+	return r.FindByID(ctx, subscription.ID)
+}
+
+func (r *webhookSubscriptionRepository) FindByID(ctx context.Context, id uint) (*entity.WebhookSubscription, error) {
+	if id == 0 {
+		return nil, handleDBError(exception.ErrIDNull, r.GetTableName(), "find webhook subscription by id")
+	}
+
+	subscription := &model.WebhookSubscription{Base: model.Base{ID: id}}
+	if err := r.db.NewSelect().Model(subscription).WherePK().Scan(ctx); err != nil {
+		return nil, handleDBError(err, r.GetTableName(), "find webhook subscription by id")
+	}
+
+	return subscription.ToDomain(), nil
+}
+
+type FilterWebhookSubscriptionPayload struct {
+	IDs        []uint
+	CompanyIDs []uint
+	IsActive   *bool
+	Search     string
+	Page       int
+	PerPage    int
+}
+
+func (r *webhookSubscriptionRepository) Find(ctx context.Context, filter *FilterWebhookSubscriptionPayload) ([]*entity.WebhookSubscription, int, error) {
+	var subscriptions []*model.WebhookSubscription
+
+	query := r.db.NewSelect().Model(&subscriptions)
+	if len(filter.IDs) > 0 {
+		query = query.Where("whs.id IN (?)", bun.In(filter.IDs))
+	}
+
+	if len(filter.CompanyIDs) > 0 {
+		query = query.Where("whs.company_id IN (?)", bun.In(filter.CompanyIDs))
+	}
+
+	if filter.IsActive != nil {
+		query = query.Where("whs.is_active = ?", *filter.IsActive)
+	}
+
+	if filter.Search != "" {
+		query = query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			q = q.WhereOr("LOWER(whs.name) LIKE LOWER(?)", "%"+filter.Search+"%")
+			q = q.WhereOr("LOWER(whs.target_url) LIKE LOWER(?)", "%"+filter.Search+"%")
+
+			return q
+		})
+	}
+
+	totalCount, err := query.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, handleDBError(err, r.GetTableName(), "count webhook subscription")
+	}
+
+	if totalCount == 0 {
+		return []*entity.WebhookSubscription{}, 0, nil
+	}
+
+	if filter.PerPage > 0 {
+		query = query.Limit(filter.PerPage)
+	}
+
+	if filter.Page > 0 && filter.PerPage > 0 {
+		offset := (filter.Page - 1) * filter.PerPage
+		query = query.Offset(offset)
+	}
+
+	query = query.Order("whs.id DESC")
+	if err = query.Scan(ctx); err != nil {
+		return nil, 0, handleDBError(err, r.GetTableName(), "find webhook subscription")
+	}
+
+	return model.ToWebhookSubscriptionsDomain(subscriptions), totalCount, nil
+}
+
+type UpdateWebhookSubscriptionPayload struct {
+	ID         uint
+	Name       *string
+	TargetURL  *string
+	Secret     *string
+	EventTypes []string
+	IsActive   *bool
+}
+
+// Update clears the failure counter and disabled marker whenever the subscription is (re)activated.
+func (r *webhookSubscriptionRepository) Update(ctx context.Context, req *UpdateWebhookSubscriptionPayload) (*entity.WebhookSubscription, error) {
+	if req.ID == 0 {
+		return nil, handleDBError(exception.ErrIDNull, r.GetTableName(), "update webhook subscription")
+	}
+
+	subscription := &model.WebhookSubscription{Base: model.Base{ID: req.ID}}
+
+	var columnsToUpdate []string
+
+	if req.Name != nil {
+		subscription.Name = *req.Name
+
+		columnsToUpdate = append(columnsToUpdate, "name")
+	}
+
+	if req.TargetURL != nil {
+		subscription.TargetURL = *req.TargetURL
+
+		columnsToUpdate = append(columnsToUpdate, "target_url")
+	}
+
+	if req.Secret != nil {
+		subscription.Secret = *req.Secret
+
+		columnsToUpdate = append(columnsToUpdate, "secret")
+	}
+
+	if req.EventTypes != nil {
+		subscription.EventTypes = req.EventTypes
+
+		columnsToUpdate = append(columnsToUpdate, "event_types")
+	}
+
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+
+		columnsToUpdate = append(columnsToUpdate, "is_active")
+
+		if *req.IsActive {
+			columnsToUpdate = append(columnsToUpdate, "consecutive_failures", "disabled_at", "disabled_reason")
+		}
+	}
+
+	if len(columnsToUpdate) != 0 {
+		query := r.db.NewUpdate().Model(subscription).Column(columnsToUpdate...).WherePK()
+		if _, err := query.Exec(ctx); err != nil {
+			return nil, handleDBError(err, r.GetTableName(), "update webhook subscription")
+		}
+	}
+
+	return r.FindByID(ctx, req.ID)
+}
+
+func (r *webhookSubscriptionRepository) Delete(ctx context.Context, id uint) error {
+	if id == 0 {
+		return handleDBError(exception.ErrIDNull, r.GetTableName(), "delete webhook subscription")
+	}
+
+	subscription := &model.WebhookSubscription{Base: model.Base{ID: id}}
+
+	res, err := r.db.NewDelete().Model(subscription).WherePK().Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "delete webhook subscription")
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected == 0 {
+		return handleDBError(sql.ErrNoRows, r.GetTableName(), "delete webhook subscription")
+	}
+
+	return nil
+}
+
+func (r *webhookSubscriptionRepository) IncrementFailures(ctx context.Context, id uint) (uint, error) {
+	if id == 0 {
+		return 0, handleDBError(exception.ErrIDNull, r.GetTableName(), "increment webhook subscription failures")
+	}
+
+	_, err := r.db.NewUpdate().
+		Model((*model.WebhookSubscription)(nil)).
+		Set("consecutive_failures = consecutive_failures + 1").
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return 0, handleDBError(err, r.GetTableName(), "increment webhook subscription failures")
+	}
+
+	var failures uint
+	if err := r.db.NewSelect().
+		Model((*model.WebhookSubscription)(nil)).
+		Column("consecutive_failures").
+		Where("id = ?", id).
+		Scan(ctx, &failures); err != nil {
+		return 0, handleDBError(err, r.GetTableName(), "increment webhook subscription failures")
+	}
+
+	return failures, nil
+}
+
+func (r *webhookSubscriptionRepository) ResetFailures(ctx context.Context, id uint) error {
+	if id == 0 {
+		return handleDBError(exception.ErrIDNull, r.GetTableName(), "reset webhook subscription failures")
+	}
+
+	_, err := r.db.NewUpdate().
+		Model((*model.WebhookSubscription)(nil)).
+		Set("consecutive_failures = 0").
+		Where("id = ?", id).
+		Where("consecutive_failures > 0").
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "reset webhook subscription failures")
+	}
+
+	return nil
+}
+
+func (r *webhookSubscriptionRepository) Disable(ctx context.Context, id uint, reason string) error {
+	if id == 0 {
+		return handleDBError(exception.ErrIDNull, r.GetTableName(), "disable webhook subscription")
+	}
+
+	_, err := r.db.NewUpdate().
+		Model((*model.WebhookSubscription)(nil)).
+		Set("is_active = ?", false).
+		Set("disabled_at = ?", time.Now()).
+		Set("disabled_reason = ?", reason).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return handleDBError(err, r.GetTableName(), "disable webhook subscription")
+	}
+
+	return nil
+}
@@ -1,11 +1,24 @@
 package redisrepository
 
 const (
-	KeyPatternUserLock       = "lock:user:%s"
-	KeyPatternBlockIP        = "block:ip:%s"
-	KeyPatternUserAttempts   = "attempts:user:%s"
-	KeyPatternIPAttempts     = "attempts:ip:%s"
-	KeyPatternBlockCountIP   = "blockcount:ip:%s"
-	KeyPatternBlacklistToken = "blacklist:token:%s"
-	KeyPatternResetPassword  = "reset:password:%s"
+	KeyPatternUserLock        = "lock:user:%s"
+	KeyPatternBlockIP         = "block:ip:%s"
+	KeyPatternUserAttempts    = "attempts:user:%s"
+	KeyPatternIPAttempts      = "attempts:ip:%s"
+	KeyPatternBlockCountIP    = "blockcount:ip:%s"
+	KeyPatternBlacklistToken  = "blacklist:token:%s"
+	KeyPatternBlacklistUser   = "blacklist:user:%d"
+	KeyPatternResetPassword   = "reset:password:%s"
+	KeyPatternLock            = "lock:%s"
+	KeyPatternJobRuns         = "job:runs:%s"
+	KeyPatternTaskReady       = "queue:%s:ready"
+	KeyPatternTaskProcessing  = "queue:%s:processing"
+	KeyPatternTaskDelayed     = "queue:%s:delayed"
+	KeyPatternTaskDead        = "queue:%s:dead"
+	KeyPatternTaskExpired     = "queue:%s:expired"
+	KeyPatternRateLimitWindow = "ratelimit:%s:%d"
+	KeyPatternRateLimitBucket = "ratelimit:%s:bucket"
+	KeyPatternSSOState        = "sso:state:%s"
+	KeyPatternInviteToken     = "invite:token:%s"
+	KeyPatternInviteUser      = "invite:user:%d"
 )
@@ -0,0 +1,65 @@
+package redisrepository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// storeInviteScript keeps a single live invite per user: the previous token, found through the
+// per-user key, is deleted before the new one is written.
+var storeInviteScript = redis.NewScript(`
+local previous = redis.call("GET", KEYS[1])
+if previous then
+	redis.call("DEL", string.format(ARGV[1], previous))
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[4])
+redis.call("SET", KEYS[2], ARGV[3], "PX", ARGV[4])
+return 1
+`)
+
+var deleteInviteScript = redis.NewScript(`
+local token = redis.call("GET", KEYS[1])
+if token then
+	redis.call("DEL", string.format(ARGV[1], token))
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+func (r *redisRepository) StoreInviteToken(ctx context.Context, token string, userID uint, ttl time.Duration) error {
+	keys := []string{
+		fmt.Sprintf(KeyPatternInviteUser, userID),
+		fmt.Sprintf(KeyPatternInviteToken, token),
+	}
+
+	err := storeInviteScript.Run(ctx, r.db, keys, KeyPatternInviteToken, token, userID, ttl.Milliseconds()).Err()
+
+	return handleRedisError(err, "store invite token")
+}
+
+func (r *redisRepository) GetUserIDFromInviteToken(ctx context.Context, token string) (uint, error) {
+	userIDStr, err := r.db.Get(ctx, fmt.Sprintf(KeyPatternInviteToken, token)).Result()
+	if err != nil {
+		return 0, handleRedisError(err, "get invite token")
+	}
+
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse user ID from redis")
+	}
+
+	return uint(userID), nil
+}
+
+// DeleteInviteToken invalidates the user's outstanding invite, if any.
+func (r *redisRepository) DeleteInviteToken(ctx context.Context, userID uint) error {
+	keys := []string{fmt.Sprintf(KeyPatternInviteUser, userID)}
+
+	err := deleteInviteScript.Run(ctx, r.db, keys, KeyPatternInviteToken).Err()
+
+	return handleRedisError(err, "delete invite token")
+}
@@ -0,0 +1,79 @@
+package redisrepository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"goapptemp/pkg/scheduler"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseLockScript deletes the lock only if it still holds the caller's token, so an expired
+// lock that another replica has since taken is left alone.
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (r *redisRepository) AcquireLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	lockKey := fmt.Sprintf(KeyPatternLock, key)
+
+	acquired, err := r.db.SetNX(ctx, lockKey, token, ttl).Result()
+	if err != nil {
+		return false, handleRedisError(err, "acquire lock")
+	}
+
+	return acquired, nil
+}
+
+func (r *redisRepository) ReleaseLock(ctx context.Context, key, token string) error {
+	lockKey := fmt.Sprintf(KeyPatternLock, key)
+
+	err := releaseLockScript.Run(ctx, r.db, []string{lockKey}, token).Err()
+
+	return handleRedisError(err, "release lock")
+}
+
+func (r *redisRepository) SaveJobRun(ctx context.Context, run *scheduler.Run, limit int) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode job run")
+	}
+
+	key := fmt.Sprintf(KeyPatternJobRuns, run.Job)
+
+	pipe := r.db.TxPipeline()
+	pipe.LPush(ctx, key, data)
+	pipe.LTrim(ctx, key, 0, int64(limit-1))
+
+	_, err = pipe.Exec(ctx)
+
+	return handleRedisError(err, "save job run")
+}
+
+func (r *redisRepository) FindJobRuns(ctx context.Context, job string, limit int) ([]*scheduler.Run, error) {
+	key := fmt.Sprintf(KeyPatternJobRuns, job)
+
+	items, err := r.db.LRange(ctx, key, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, handleRedisError(err, "find job runs")
+	}
+
+	runs := make([]*scheduler.Run, 0, len(items))
+
+	for _, item := range items {
+		run := new(scheduler.Run)
+		if err := json.Unmarshal([]byte(item), run); err != nil {
+			continue
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
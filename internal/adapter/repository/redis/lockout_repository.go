@@ -0,0 +1,144 @@
+package redisrepository
+
+import (
+	"context"
+	"fmt"
+	"goapptemp/internal/domain/entity"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const lockoutScanCount = 200
+
+func (r *redisRepository) FindLockedUsers(ctx context.Context, limit int) ([]*entity.LoginBlock, error) {
+	return r.scanLoginBlocks(ctx, KeyPatternUserLock, entity.LoginBlockUser, limit)
+}
+
+func (r *redisRepository) FindBlockedIPs(ctx context.Context, limit int) ([]*entity.LoginBlock, error) {
+	blocks, err := r.scanLoginBlocks(ctx, KeyPatternBlockIP, entity.LoginBlockIP, limit)
+	if err != nil || len(blocks) == 0 {
+		return blocks, err
+	}
+
+	pipe := r.db.Pipeline()
+
+	levels := make([]*redis.StringCmd, len(blocks))
+	for i := range blocks {
+		levels[i] = pipe.Get(ctx, fmt.Sprintf(KeyPatternBlockCountIP, blocks[i].Identifier))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, handleRedisError(err, "get IP block levels")
+	}
+
+	for i := range blocks {
+		blocks[i].Level, _ = strconv.Atoi(levels[i].Val())
+	}
+
+	return blocks, nil
+}
+
+func (r *redisRepository) GetUserLockTTL(ctx context.Context, identifier string) (time.Duration, error) {
+	ttl, err := r.db.TTL(ctx, fmt.Sprintf(KeyPatternUserLock, identifier)).Result()
+	if err != nil {
+		return 0, handleRedisError(err, "get user lock TTL")
+	}
+
+	return ttl, nil
+}
+
+// UnlockUser lifts a username lock together with its failure counter and reports whether a lock existed.
+func (r *redisRepository) UnlockUser(ctx context.Context, identifier string) (bool, error) {
+	pipe := r.db.TxPipeline()
+	deleted := pipe.Del(ctx, fmt.Sprintf(KeyPatternUserLock, identifier))
+	pipe.Del(ctx, fmt.Sprintf(KeyPatternUserAttempts, identifier))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, handleRedisError(err, "unlock user")
+	}
+
+	return deleted.Val() > 0, nil
+}
+
+// UnblockIP lifts an IP block and resets its backoff level, so the next block starts from the base duration.
+func (r *redisRepository) UnblockIP(ctx context.Context, ip string) (bool, error) {
+	pipe := r.db.TxPipeline()
+	deleted := pipe.Del(ctx, fmt.Sprintf(KeyPatternBlockIP, ip))
+	pipe.Del(ctx, fmt.Sprintf(KeyPatternIPAttempts, ip))
+	pipe.Del(ctx, fmt.Sprintf(KeyPatternBlockCountIP, ip))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, handleRedisError(err, "unblock IP")
+	}
+
+	return deleted.Val() > 0, nil
+}
+
+// scanLoginBlocks walks the keyspace with SCAN rather than KEYS so a large keyspace does not stall
+// Redis, then reads the TTLs in one round trip. Keys that expire in between are skipped.
+func (r *redisRepository) scanLoginBlocks(ctx context.Context, keyPattern string, kind entity.LoginBlockKind, limit int) ([]*entity.LoginBlock, error) {
+	prefix := fmt.Sprintf(keyPattern, "")
+	seen := make(map[string]struct{})
+
+	var keys []string
+
+	iter := r.db.Scan(ctx, 0, prefix+"*", lockoutScanCount).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+
+		if limit > 0 && len(keys) >= limit {
+			break
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, handleRedisError(err, "scan login blocks")
+	}
+
+	if len(keys) == 0 {
+		return []*entity.LoginBlock{}, nil
+	}
+
+	pipe := r.db.Pipeline()
+
+	ttls := make([]*redis.DurationCmd, len(keys))
+	for i := range keys {
+		ttls[i] = pipe.TTL(ctx, keys[i])
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, handleRedisError(err, "get login block TTLs")
+	}
+
+	now := time.Now()
+	res := make([]*entity.LoginBlock, 0, len(keys))
+
+	for i := range keys {
+		ttl := ttls[i].Val()
+		if ttl <= 0 {
+			continue
+		}
+
+		res = append(res, &entity.LoginBlock{
+			Kind:       kind,
+			Identifier: strings.TrimPrefix(keys[i], prefix),
+			TTL:        ttl,
+			ExpiresAt:  now.Add(ttl),
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].TTL > res[j].TTL })
+
+	return res, nil
+}
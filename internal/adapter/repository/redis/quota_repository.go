@@ -0,0 +1,106 @@
+package redisrepository
+
+import (
+	"context"
+	"fmt"
+	"goapptemp/pkg/ratelimit"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript weights the previous fixed window by the share that still overlaps the
+// rolling window. A denied request is not counted; its retry is the time until enough of the
+// previous window slides out, or until the next window when the current one is already full.
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+local previous = tonumber(redis.call("GET", KEYS[2]) or "0")
+local estimate = previous * (window - elapsed) / window + current
+if estimate + 1 > limit then
+	local retry = window - elapsed
+	if previous > 0 and current < limit then
+		retry = math.ceil(retry - (limit - 1 - current) * window / previous)
+	end
+	return {0, 0, window - elapsed, math.max(retry, 1)}
+end
+current = redis.call("INCR", KEYS[1])
+if current == 1 then
+	redis.call("PEXPIRE", KEYS[1], window * 2)
+end
+return {1, math.floor(limit - estimate - 1), window - elapsed, 0}
+`)
+
+// tokenBucketScript refills the bucket for the time since the last call and takes one token.
+// The bucket expires once it would be full again, since a missing bucket starts full.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(capacity / rate))
+return {allowed, math.floor(tokens), math.ceil((capacity - tokens) / rate), retry}
+`)
+
+func (r *redisRepository) AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*ratelimit.Result, error) {
+	windowMs := window.Milliseconds()
+	index := now.UnixMilli() / windowMs
+	keys := []string{
+		fmt.Sprintf(KeyPatternRateLimitWindow, key, index),
+		fmt.Sprintf(KeyPatternRateLimitWindow, key, index-1),
+	}
+
+	res, err := slidingWindowScript.Run(ctx, r.db, keys, limit, windowMs, now.UnixMilli()%windowMs).Int64Slice()
+	if err != nil {
+		return nil, handleRedisError(err, "apply sliding window rate limit")
+	}
+
+	return quotaResult(res, limit)
+}
+
+func (r *redisRepository) AllowTokenBucket(ctx context.Context, key string, capacity, limit int, window time.Duration, now time.Time) (*ratelimit.Result, error) {
+	ratePerMs := float64(limit) / float64(window.Milliseconds())
+	keys := []string{fmt.Sprintf(KeyPatternRateLimitBucket, key)}
+
+	res, err := tokenBucketScript.Run(ctx, r.db, keys,
+		capacity,
+		strconv.FormatFloat(ratePerMs, 'g', -1, 64),
+		now.UnixMilli(),
+	).Int64Slice()
+	if err != nil {
+		return nil, handleRedisError(err, "apply token bucket rate limit")
+	}
+
+	return quotaResult(res, capacity)
+}
+
+// quotaResult decodes the {allowed, remaining, reset ms, retry ms} reply shared by the scripts.
+func quotaResult(res []int64, limit int) (*ratelimit.Result, error) {
+	if len(res) != 4 {
+		return nil, errors.Newf("unexpected rate limit reply with %d values", len(res))
+	}
+
+	return &ratelimit.Result{
+		Allowed:    res[0] == 1,
+		Limit:      limit,
+		Remaining:  max(int(res[1]), 0),
+		ResetAfter: time.Duration(res[2]) * time.Millisecond,
+		RetryAfter: time.Duration(res[3]) * time.Millisecond,
+	}, nil
+}
@@ -3,7 +3,6 @@ package redisrepository
 import (
 	"context"
 	"fmt"
-	"goapptemp/constant"
 	"math"
 	"time"
 
@@ -33,59 +32,100 @@ func (r *redisRepository) GetBlockIPTTL(ctx context.Context, ip string) (time.Du
 	return ttl, nil
 }
 
-func (r *redisRepository) RecordUserFailure(ctx context.Context, identifier string) error {
-	userAttemptKey := fmt.Sprintf(KeyPatternUserAttempts, identifier)
+// UserFailurePolicy locks a username for Lockout once MaxAttempts failures happen within Window.
+type UserFailurePolicy struct {
+	MaxAttempts int
+	Window      time.Duration
+	Lockout     time.Duration
+}
 
-	failCount, err := r.db.Incr(ctx, userAttemptKey).Result()
-	if err != nil {
-		return handleRedisError(err, "increment failed user attempts")
-	}
+// IPFailurePolicy blocks an IP once MaxAttempts failures happen within Window. Every consecutive
+// block doubles from BackoffBase up to BackoffMax; the level is forgotten after LevelReset.
+type IPFailurePolicy struct {
+	MaxAttempts int
+	Window      time.Duration
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	LevelReset  time.Duration
+}
 
-	if failCount == 1 {
-		r.db.Expire(ctx, userAttemptKey, constant.UserFailedWindow)
+// recordUserFailureScript counts a failure and, once the limit is hit, swaps the counter for a lock
+// in one step so concurrent failures cannot lose an increment or skip the lock.
+var recordUserFailureScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+if count >= tonumber(ARGV[2]) then
+	redis.call("SET", KEYS[2], "1", "PX", ARGV[3])
+	redis.call("DEL", KEYS[1])
+	return 1
+end
+return 0
+`)
+
+// recordIPFailureScript counts a failure and, once the limit is hit, raises the block level and
+// blocks the IP for base * 2^(level-1), capped at the maximum. It returns the block in milliseconds.
+var recordIPFailureScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+if count < tonumber(ARGV[2]) then
+	return 0
+end
+local level = redis.call("INCR", KEYS[2])
+redis.call("PEXPIRE", KEYS[2], ARGV[5])
+local duration = math.min(tonumber(ARGV[3]) * 2 ^ (level - 1), tonumber(ARGV[4]))
+duration = math.floor(duration)
+redis.call("SET", KEYS[3], "1", "PX", duration)
+redis.call("DEL", KEYS[1])
+return duration
+`)
+
+// RecordUserFailure counts a failed login and reports whether this failure locked the account.
+func (r *redisRepository) RecordUserFailure(ctx context.Context, identifier string, policy UserFailurePolicy) (locked bool, err error) {
+	keys := []string{
+		fmt.Sprintf(KeyPatternUserAttempts, identifier),
+		fmt.Sprintf(KeyPatternUserLock, identifier),
 	}
 
-	if failCount >= int64(constant.UserFailedAttemptsLimit) {
-		lockKey := fmt.Sprintf(KeyPatternUserLock, identifier)
-
-		r.db.Set(ctx, lockKey, "1", constant.UserLockoutDuration)
-		r.db.Del(ctx, userAttemptKey)
+	res, err := recordUserFailureScript.Run(ctx, r.db, keys,
+		policy.Window.Milliseconds(),
+		policy.MaxAttempts,
+		policy.Lockout.Milliseconds(),
+	).Int()
+	if err != nil {
+		return false, handleRedisError(err, "record user failure")
 	}
 
-	return nil
+	return res == 1, nil
 }
 
-func (r *redisRepository) RecordIPFailure(ctx context.Context, ip string) (blockNow bool, retryAfter int, err error) {
-	ipAttemptKey := fmt.Sprintf(KeyPatternIPAttempts, ip)
-
-	failCount, err := r.db.Incr(ctx, ipAttemptKey).Result()
-	if err != nil {
-		return false, 0, handleRedisError(err, "increment failed IP attempts")
+// RecordIPFailure counts a failed login from ip and, when it triggers a block, returns its length in seconds.
+func (r *redisRepository) RecordIPFailure(ctx context.Context, ip string, policy IPFailurePolicy) (blockNow bool, retryAfter int, err error) {
+	keys := []string{
+		fmt.Sprintf(KeyPatternIPAttempts, ip),
+		fmt.Sprintf(KeyPatternBlockCountIP, ip),
+		fmt.Sprintf(KeyPatternBlockIP, ip),
 	}
 
-	if failCount == 1 {
-		r.db.Expire(ctx, ipAttemptKey, constant.IpRateLimitWindow)
+	blockMs, err := recordIPFailureScript.Run(ctx, r.db, keys,
+		policy.Window.Milliseconds(),
+		policy.MaxAttempts,
+		policy.BackoffBase.Milliseconds(),
+		policy.BackoffMax.Milliseconds(),
+		policy.LevelReset.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return false, 0, handleRedisError(err, "record IP failure")
 	}
 
-	if failCount >= int64(constant.IpRateLimitAttempts) {
-		blockCountKey := fmt.Sprintf(KeyPatternBlockCountIP, ip)
-
-		blockLevel, err := r.db.Incr(ctx, blockCountKey).Result()
-		if err != nil {
-			return false, 0, handleRedisError(err, "increment block count for IP")
-		}
-
-		durationSeconds := int(float64(constant.IpBackoffBaseSeconds) * math.Pow(2, float64(blockLevel-1)))
-		blockDuration := time.Duration(durationSeconds) * time.Second
-
-		ipBlockKey := fmt.Sprintf(KeyPatternBlockIP, ip)
-		r.db.Set(ctx, ipBlockKey, "1", blockDuration)
-		r.db.Del(ctx, ipAttemptKey)
-
-		return true, durationSeconds, nil
+	if blockMs == 0 {
+		return false, 0, nil
 	}
 
-	return false, 0, nil
+	return true, int(math.Ceil(float64(blockMs) / 1000)), nil
 }
 
 func (r *redisRepository) DeleteUserAttempts(ctx context.Context, identifier string) error {
@@ -130,3 +170,38 @@ func (r *redisRepository) CheckTokenBlacklisted(ctx context.Context, jti string)
 
 	return true, nil
 }
+
+// secondsMarkerLimit separates revocation markers stored in seconds, before they carried
+// milliseconds, from current ones; in milliseconds it lies in 1973.
+const secondsMarkerLimit = 100_000_000_000
+
+// RevokeUserTokens blacklists every token issued to the user before at, to the millisecond. The
+// marker only has to live as long as the longest token lifetime, after which those tokens have
+// expired anyway.
+func (r *redisRepository) RevokeUserTokens(ctx context.Context, userID uint, at time.Time, ttl time.Duration) error {
+	key := fmt.Sprintf(KeyPatternBlacklistUser, userID)
+	err := r.db.Set(ctx, key, at.UnixMilli(), ttl).Err()
+
+	return handleRedisError(err, "revoke user tokens")
+}
+
+// CheckUserTokenRevoked reports whether a token issued to the user at issuedAt has been revoked.
+func (r *redisRepository) CheckUserTokenRevoked(ctx context.Context, userID uint, issuedAt time.Time) (bool, error) {
+	key := fmt.Sprintf(KeyPatternBlacklistUser, userID)
+
+	revokedAt, err := r.db.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, handleRedisError(err, "check if user tokens are revoked")
+	}
+
+	// A marker in seconds revoked the whole second it was written in.
+	if revokedAt < secondsMarkerLimit {
+		revokedAt = time.Unix(revokedAt+1, 0).UnixMilli()
+	}
+
+	return issuedAt.UnixMilli() < revokedAt, nil
+}
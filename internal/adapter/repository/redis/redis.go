@@ -3,8 +3,11 @@ package redisrepository
 import (
 	"context"
 	"goapptemp/config"
+	"goapptemp/internal/domain/entity"
 	"goapptemp/pkg/logger"
+	"goapptemp/pkg/ratelimit"
 	redisclient "goapptemp/pkg/redis"
+	"goapptemp/pkg/scheduler"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -16,16 +19,39 @@ type RedisRepository interface {
 	Close() error
 	CheckLockedUserExists(ctx context.Context, phone string) (bool, error)
 	GetBlockIPTTL(ctx context.Context, ip string) (time.Duration, error)
-	RecordUserFailure(ctx context.Context, phone string) error
-	RecordIPFailure(ctx context.Context, ip string) (blockNow bool, retryAfter int, err error)
+	RecordUserFailure(ctx context.Context, phone string, policy UserFailurePolicy) (locked bool, err error)
+	RecordIPFailure(ctx context.Context, ip string, policy IPFailurePolicy) (blockNow bool, retryAfter int, err error)
 	DeleteUserAttempts(ctx context.Context, identifier string) error
 	DeleteIPAttempts(ctx context.Context, ip string) error
 	DeleteBlockCount(ctx context.Context, ip string) error
+	FindLockedUsers(ctx context.Context, limit int) ([]*entity.LoginBlock, error)
+	FindBlockedIPs(ctx context.Context, limit int) ([]*entity.LoginBlock, error)
+	GetUserLockTTL(ctx context.Context, identifier string) (time.Duration, error)
+	UnlockUser(ctx context.Context, identifier string) (bool, error)
+	UnblockIP(ctx context.Context, ip string) (bool, error)
 	BlacklistToken(ctx context.Context, jti string, ttl time.Duration) error
 	CheckTokenBlacklisted(ctx context.Context, jti string) (bool, error)
+	RevokeUserTokens(ctx context.Context, userID uint, at time.Time, ttl time.Duration) error
+	CheckUserTokenRevoked(ctx context.Context, userID uint, issuedAt time.Time) (bool, error)
 	StoreResetToken(ctx context.Context, token string, userID uint, ttl time.Duration) error
 	GetUserIDFromResetToken(ctx context.Context, token string) (uint, error)
 	DeleteResetToken(ctx context.Context, token string) error
+	StoreInviteToken(ctx context.Context, token string, userID uint, ttl time.Duration) error
+	GetUserIDFromInviteToken(ctx context.Context, token string) (uint, error)
+	DeleteInviteToken(ctx context.Context, userID uint) error
+	StoreSSOState(ctx context.Context, state string, payload *entity.SSOState, ttl time.Duration) error
+	ConsumeSSOState(ctx context.Context, state string) (*entity.SSOState, error)
+	AcquireLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, key, token string) error
+	SaveJobRun(ctx context.Context, run *scheduler.Run, limit int) error
+	FindJobRuns(ctx context.Context, job string, limit int) ([]*scheduler.Run, error)
+	PushTasks(ctx context.Context, queue string, tasks ...[]byte) error
+	ClaimTask(ctx context.Context, queue string, visibilityTimeout time.Duration) ([]byte, int, error)
+	AckTask(ctx context.Context, queue string, task []byte) error
+	RetryTask(ctx context.Context, queue string, task, next []byte, at time.Time) error
+	DeadLetterTask(ctx context.Context, queue string, task, dead []byte, limit int) error
+	AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*ratelimit.Result, error)
+	AllowTokenBucket(ctx context.Context, key string, capacity, limit int, window time.Duration, now time.Time) (*ratelimit.Result, error)
 }
 
 type redisRepository struct {
@@ -0,0 +1,37 @@
+package redisrepository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"goapptemp/internal/domain/entity"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+func (r *redisRepository) StoreSSOState(ctx context.Context, state string, payload *entity.SSOState, ttl time.Duration) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode sso state")
+	}
+
+	err = r.db.Set(ctx, fmt.Sprintf(KeyPatternSSOState, state), data, ttl).Err()
+
+	return handleRedisError(err, "store sso state")
+}
+
+// ConsumeSSOState reads and deletes the state in one step so a callback cannot be replayed.
+func (r *redisRepository) ConsumeSSOState(ctx context.Context, state string) (*entity.SSOState, error) {
+	data, err := r.db.GetDel(ctx, fmt.Sprintf(KeyPatternSSOState, state)).Bytes()
+	if err != nil {
+		return nil, handleRedisError(err, "consume sso state")
+	}
+
+	payload := new(entity.SSOState)
+	if err := json.Unmarshal(data, payload); err != nil {
+		return nil, errors.Wrap(err, "failed to decode sso state")
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,175 @@
+package redisrepository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// taskID reads the ID of an encoded task without re-encoding it, which would lose precision on large
+// numbers in the payload. Undecodable tasks have no ID and are not counted.
+const taskID = `
+local function task_id(task)
+	local ok, decoded = pcall(cjson.decode, task)
+	if ok and type(decoded) == "table" and type(decoded.id) == "string" then
+		return decoded.id
+	end
+	return nil
+end
+`
+
+// claimTaskScript first promotes retries that are due and claims whose visibility timeout has
+// expired back onto the ready list, counting the expiry against the task, then pops the oldest ready
+// task into the processing set with its visibility deadline as the score. It returns the task and
+// how often its claims expired.
+var claimTaskScript = redis.NewScript(taskID + `
+local due = redis.call("ZRANGEBYSCORE", KEYS[3], "-inf", ARGV[1], "LIMIT", 0, 100)
+for _, task in ipairs(due) do
+	redis.call("ZREM", KEYS[3], task)
+	redis.call("LPUSH", KEYS[1], task)
+end
+local expired = redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", ARGV[1], "LIMIT", 0, 100)
+for _, task in ipairs(expired) do
+	redis.call("ZREM", KEYS[2], task)
+	redis.call("RPUSH", KEYS[1], task)
+	local id = task_id(task)
+	if id then
+		redis.call("HINCRBY", KEYS[4], id, 1)
+	end
+end
+local task = redis.call("RPOP", KEYS[1])
+if not task then
+	return nil
+end
+redis.call("ZADD", KEYS[2], ARGV[2], task)
+local id = task_id(task)
+local expirations = 0
+if id then
+	expirations = tonumber(redis.call("HGET", KEYS[4], id) or 0)
+end
+return {task, expirations}
+`)
+
+// ackTaskScript removes a finished task. Its expiry count is kept when the claim already expired,
+// as the task is then back on the ready list.
+var ackTaskScript = redis.NewScript(taskID + `
+if redis.call("ZREM", KEYS[1], ARGV[1]) == 1 then
+	local id = task_id(ARGV[1])
+	if id then
+		redis.call("HDEL", KEYS[2], id)
+	end
+end
+return 0
+`)
+
+// retryTaskScript only reschedules a task this worker still owns; if its claim already expired
+// the task is back on the ready list and must not be duplicated. The rescheduled task carries the
+// expired claims in its attempt count, so the separate count is dropped.
+var retryTaskScript = redis.NewScript(taskID + `
+if redis.call("ZREM", KEYS[1], ARGV[1]) == 1 then
+	redis.call("ZADD", KEYS[2], ARGV[3], ARGV[2])
+	local id = task_id(ARGV[1])
+	if id then
+		redis.call("HDEL", KEYS[3], id)
+	end
+end
+return 0
+`)
+
+// deadLetterTaskScript, like retryTaskScript, only acts on a task this worker still owns, so a task
+// whose claim expired is not dead-lettered while another worker runs it again.
+var deadLetterTaskScript = redis.NewScript(taskID + `
+if redis.call("ZREM", KEYS[1], ARGV[1]) == 1 then
+	redis.call("LPUSH", KEYS[2], ARGV[2])
+	redis.call("LTRIM", KEYS[2], 0, tonumber(ARGV[3]) - 1)
+	local id = task_id(ARGV[1])
+	if id then
+		redis.call("HDEL", KEYS[3], id)
+	end
+end
+return 0
+`)
+
+// PushTasks queues the tasks with a single LPUSH, which Redis applies atomically.
+func (r *redisRepository) PushTasks(ctx context.Context, queue string, tasks ...[]byte) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	values := make([]any, 0, len(tasks))
+	for _, task := range tasks {
+		values = append(values, task)
+	}
+
+	err := r.db.LPush(ctx, fmt.Sprintf(KeyPatternTaskReady, queue), values...).Err()
+
+	return handleRedisError(err, "push tasks")
+}
+
+// ClaimTask returns the next task, or nil when none is ready, and how often earlier claims of it
+// expired without being settled.
+func (r *redisRepository) ClaimTask(ctx context.Context, queue string, visibilityTimeout time.Duration) ([]byte, int, error) {
+	now := time.Now()
+	keys := []string{
+		fmt.Sprintf(KeyPatternTaskReady, queue),
+		fmt.Sprintf(KeyPatternTaskProcessing, queue),
+		fmt.Sprintf(KeyPatternTaskDelayed, queue),
+		fmt.Sprintf(KeyPatternTaskExpired, queue),
+	}
+
+	res, err := claimTaskScript.Run(ctx, r.db, keys, now.UnixMilli(), now.Add(visibilityTimeout).UnixMilli()).Slice()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, 0, nil
+		}
+
+		return nil, 0, handleRedisError(err, "claim task")
+	}
+
+	if len(res) != 2 {
+		return nil, 0, handleRedisError(errors.Newf("unexpected claim result of length %d", len(res)), "claim task")
+	}
+
+	task, _ := res[0].(string)
+	expirations, _ := res[1].(int64)
+
+	return []byte(task), int(expirations), nil
+}
+
+func (r *redisRepository) AckTask(ctx context.Context, queue string, task []byte) error {
+	keys := []string{
+		fmt.Sprintf(KeyPatternTaskProcessing, queue),
+		fmt.Sprintf(KeyPatternTaskExpired, queue),
+	}
+
+	err := ackTaskScript.Run(ctx, r.db, keys, task).Err()
+
+	return handleRedisError(err, "ack task")
+}
+
+func (r *redisRepository) RetryTask(ctx context.Context, queue string, task, next []byte, at time.Time) error {
+	keys := []string{
+		fmt.Sprintf(KeyPatternTaskProcessing, queue),
+		fmt.Sprintf(KeyPatternTaskDelayed, queue),
+		fmt.Sprintf(KeyPatternTaskExpired, queue),
+	}
+
+	err := retryTaskScript.Run(ctx, r.db, keys, task, next, at.UnixMilli()).Err()
+
+	return handleRedisError(err, "retry task")
+}
+
+func (r *redisRepository) DeadLetterTask(ctx context.Context, queue string, task, dead []byte, limit int) error {
+	keys := []string{
+		fmt.Sprintf(KeyPatternTaskProcessing, queue),
+		fmt.Sprintf(KeyPatternTaskDead, queue),
+		fmt.Sprintf(KeyPatternTaskExpired, queue),
+	}
+
+	err := deadLetterTaskScript.Run(ctx, r.db, keys, task, dead, limit).Err()
+
+	return handleRedisError(err, "dead-letter task")
+}
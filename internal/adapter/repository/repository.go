@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"goapptemp/config"
 	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
 	redisrepository "goapptemp/internal/adapter/repository/redis"
@@ -44,5 +45,5 @@ func (r *repository) Redis() redisrepository.RedisRepository {
 }
 
 func (r *repository) Close() error {
-	return r.mysql.Close()
+	return errors.Join(r.mysql.Close(), r.redis.Close())
 }
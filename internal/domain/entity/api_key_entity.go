@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// APIKey lets a machine client act as the user who issued it, limited to Permissions.
+type APIKey struct {
+	ID          uint
+	CompanyID   uint
+	UserID      uint
+	Name        string
+	Prefix      string
+	SecretHash  string
+	Permissions []string
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (e *APIKey) Active(now time.Time) bool {
+	if e.RevokedAt != nil {
+		return false
+	}
+
+	return e.ExpiresAt == nil || now.Before(*e.ExpiresAt)
+}
+
+func (e *APIKey) Allows(permissionCode string) bool {
+	return slices.Contains(e.Permissions, permissionCode)
+}
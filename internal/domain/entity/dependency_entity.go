@@ -0,0 +1,12 @@
+package entity
+
+// Dependency counts the rows of one table that still reference a record through a foreign key.
+type Dependency struct {
+	Table  string
+	Column string
+	Count  int
+	// SoftDeletable is set when the table has a deleted_at column, so a cascading delete can clear it.
+	SoftDeletable bool
+	// SampleIDs holds the first few referencing IDs; it is empty for tables without a single-column primary key.
+	SampleIDs []uint
+}
@@ -0,0 +1,81 @@
+package entity
+
+import "time"
+
+type DomainEventType string
+
+const (
+	EventClientCreated          DomainEventType = "client.created"
+	EventClientUpdated          DomainEventType = "client.updated"
+	EventClientDeleted          DomainEventType = "client.deleted"
+	EventUserCreated            DomainEventType = "user.created"
+	EventUserUpdated            DomainEventType = "user.updated"
+	EventUserDeleted            DomainEventType = "user.deleted"
+	EventUserDeactivated        DomainEventType = "user.deactivated"
+	EventUserInvited            DomainEventType = "user.invited"
+	EventUserActivated          DomainEventType = "user.activated"
+	EventUserRolesChanged       DomainEventType = "user.roles_changed"
+	EventUserImpersonated       DomainEventType = "user.impersonated"
+	EventRoleCreated            DomainEventType = "role.created"
+	EventRoleUpdated            DomainEventType = "role.updated"
+	EventRoleDeleted            DomainEventType = "role.deleted"
+	EventRolePermissionsChanged DomainEventType = "role.permissions_changed"
+	EventNotificationCreated    DomainEventType = "notification.created"
+)
+
+const DomainEventVersion = 1
+
+type DomainEvent struct {
+	ID         string
+	Type       DomainEventType
+	Version    int
+	OccurredAt time.Time
+	TenantID   uint
+	ActorID    uint
+	// ImpersonatorID is the admin behind ActorID when the change was made under impersonation.
+	ImpersonatorID uint
+	Payload        any
+}
+
+type ClientEventPayload struct {
+	ID         uint   `json:"id"`
+	CompanyID  uint   `json:"company_id"`
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	DistrictID uint   `json:"district_id"`
+}
+
+type UserEventPayload struct {
+	ID        uint   `json:"id"`
+	CompanyID uint   `json:"company_id"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Fullname  string `json:"fullname"`
+	RoleIDs   []uint `json:"role_ids,omitempty"`
+}
+
+type ImpersonationEventPayload struct {
+	UserID         uint      `json:"user_id"`
+	ImpersonatorID uint      `json:"impersonator_id"`
+	Reason         string    `json:"reason"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type RoleEventPayload struct {
+	ID            uint   `json:"id"`
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	SuperAdmin    bool   `json:"super_admin"`
+	ParentID      *uint  `json:"parent_id,omitempty"`
+	CompanyID     *uint  `json:"company_id,omitempty"`
+	PermissionIDs []uint `json:"permission_ids,omitempty"`
+}
+
+type NotificationEventPayload struct {
+	ID     uint64         `json:"id,omitempty"`
+	UserID uint           `json:"user_id"`
+	Type   string         `json:"type"`
+	Title  string         `json:"title"`
+	Body   string         `json:"body"`
+	Data   map[string]any `json:"data,omitempty"`
+}
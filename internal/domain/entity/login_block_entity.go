@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+type LoginBlockKind string
+
+const (
+	LoginBlockUser LoginBlockKind = "user"
+	LoginBlockIP   LoginBlockKind = "ip"
+)
+
+// LoginBlock is an active brute-force lock on a username or an IP address.
+type LoginBlock struct {
+	Kind       LoginBlockKind
+	Identifier string
+	TTL        time.Duration
+	ExpiresAt  time.Time
+	// Level is how many times in a row the IP has been blocked; each level doubles the next block.
+	Level int
+	// User is the account behind a locked username, nil when the username does not exist.
+	User *User
+}
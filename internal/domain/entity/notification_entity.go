@@ -0,0 +1,96 @@
+package entity
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationUserRolesChanged NotificationType = "user.roles_changed"
+	NotificationAccountLocked    NotificationType = "user.account_locked"
+)
+
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail   NotificationChannel = "email"
+	NotificationChannelInApp   NotificationChannel = "in_app"
+	NotificationChannelWebhook NotificationChannel = "webhook"
+)
+
+var NotificationChannels = []NotificationChannel{
+	NotificationChannelEmail,
+	NotificationChannelInApp,
+	NotificationChannelWebhook,
+}
+
+// NotificationDefaults lists, per notification type, the channels used when the user has not set a
+// preference. Webhook delivery is opt-in because it leaves the application.
+var NotificationDefaults = map[NotificationType][]NotificationChannel{
+	NotificationUserRolesChanged: {NotificationChannelInApp, NotificationChannelEmail},
+	NotificationAccountLocked:    {NotificationChannelInApp, NotificationChannelEmail},
+}
+
+// NotificationRequired lists channels that users cannot turn off, used for security notices that
+// must reach the account owner.
+var NotificationRequired = map[NotificationType][]NotificationChannel{
+	NotificationAccountLocked: {NotificationChannelEmail},
+}
+
+func (t NotificationType) Valid() bool {
+	_, ok := NotificationDefaults[t]
+	return ok
+}
+
+func (c NotificationChannel) Valid() bool {
+	for i := range NotificationChannels {
+		if NotificationChannels[i] == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnabledByDefault reports whether channel is used for t when the user has no preference for it.
+func (t NotificationType) EnabledByDefault(channel NotificationChannel) bool {
+	for _, c := range NotificationDefaults[t] {
+		if c == channel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Required reports whether channel is always used for t, regardless of the user's preferences.
+func (t NotificationType) Required(channel NotificationChannel) bool {
+	for _, c := range NotificationRequired[t] {
+		if c == channel {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Notification struct {
+	ID        uint64
+	UserID    uint
+	Type      NotificationType
+	Title     string
+	Body      string
+	Data      map[string]any
+	ReadAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type NotificationPreference struct {
+	ID        uint
+	UserID    uint
+	Type      NotificationType
+	Channel   NotificationChannel
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -1,8 +1,23 @@
 package entity
 
+import "strings"
+
 type Permission struct {
 	Base
 	Code        string
 	Name        string
 	Description *string
 }
+
+// Module is the part of the code before the dot, e.g. CLIENT for CLIENT.READ.
+func (e *Permission) Module() string {
+	module, _, _ := strings.Cut(e.Code, ".")
+
+	return module
+}
+
+// PermissionGroup holds the permissions of one module, as shown when editing a role.
+type PermissionGroup struct {
+	Module      string
+	Permissions []*Permission
+}
@@ -2,6 +2,10 @@ package entity
 
 type Role struct {
 	Base
+	// ParentID names the role this one extends; its permissions are inherited.
+	ParentID *uint
+	// CompanyID is nil for global template roles and set for a company's custom roles.
+	CompanyID     *uint
 	PermissionIDs []uint
 	Permissions   []*Permission
 	Code          string
@@ -10,6 +14,19 @@ type Role struct {
 	SuperAdmin    bool
 }
 
+// Global reports whether the role is a template shared by all companies.
+func (e *Role) Global() bool {
+	return e.CompanyID == nil
+}
+
+// EffectivePermission is a permission a role holds, either directly or through an ancestor.
+type EffectivePermission struct {
+	Permission *Permission
+	// SourceRoleID is the role that grants the permission directly.
+	SourceRoleID uint
+	Inherited    bool
+}
+
 type RolePermission struct {
 	RoleId       uint
 	Role         *Role
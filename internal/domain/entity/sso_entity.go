@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+type SSOProvider struct {
+	Name        string
+	DisplayName string
+}
+
+// SSOState is kept server side between the authorization redirect and the callback.
+type SSOState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// UserIdentity links a user to the subject an identity provider knows them by.
+type UserIdentity struct {
+	ID          uint
+	UserID      uint
+	Provider    string
+	Subject     string
+	Email       string
+	LastLoginAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -2,6 +2,17 @@ package entity
 
 import (
 	"goapptemp/internal/shared"
+	"time"
+)
+
+type UserStatus string
+
+const (
+	UserStatusActive UserStatus = "active"
+	// UserStatusPending marks an invited user who has not set a password yet and cannot log in.
+	UserStatusPending UserStatus = "pending"
+	// UserStatusSuspended blocks login and token refresh until an admin reactivates the user.
+	UserStatusSuspended UserStatus = "suspended"
 )
 
 type User struct {
@@ -10,10 +21,18 @@ type User struct {
 	Roles     []*Role
 	CompanyID uint
 	Fullname  string
+	Locale    string
+	Status    UserStatus
 	Username  string
 	Email     string
 	Password  string
 	Token     *Token
+	// Impersonation is set when the user is being viewed through an admin's impersonation token.
+	Impersonation *Impersonation
+	// PasswordChangedAt drives password expiry; nil means the password never expires.
+	PasswordChangedAt *time.Time
+	SuspendedAt       *time.Time
+	SuspensionReason  string
 }
 
 func (e *User) SetPassword(password string) error {
@@ -27,9 +46,35 @@ func (e *User) SetPassword(password string) error {
 	return nil
 }
 
+// IsSuperAdmin reports whether any of the user's loaded roles is a super admin role.
+func (e *User) IsSuperAdmin() bool {
+	for _, role := range e.Roles {
+		if role != nil && role.SuperAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Impersonation describes an admin acting as another user.
+type Impersonation struct {
+	ImpersonatorID uint
+	Impersonator   *User
+	ExpiresAt      time.Time
+}
+
 type UserRole struct {
 	UserID uint
 	User   *User
 	RoleID uint
 	Role   *Role
 }
+
+// PasswordHistory is a previous password hash, kept so it cannot be reused.
+type PasswordHistory struct {
+	ID           uint
+	UserID       uint
+	PasswordHash string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending    WebhookDeliveryStatus = "pending"
+	WebhookDeliveryProcessing WebhookDeliveryStatus = "processing"
+	WebhookDeliverySucceeded  WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed     WebhookDeliveryStatus = "failed"
+	WebhookDeliveryCancelled  WebhookDeliveryStatus = "cancelled"
+)
+
+// WebhookEventTypes lists the domain events tenants can subscribe to.
+var WebhookEventTypes = []DomainEventType{
+	EventClientCreated,
+	EventClientUpdated,
+	EventClientDeleted,
+	EventNotificationCreated,
+}
+
+type WebhookSubscription struct {
+	Base
+	CompanyID           uint
+	Name                string
+	TargetURL           string
+	Secret              string
+	EventTypes          []string
+	IsActive            bool
+	ConsecutiveFailures uint
+	DisabledAt          *time.Time
+	DisabledReason      *string
+}
+
+func (e *WebhookSubscription) Subscribes(eventType DomainEventType) bool {
+	for i := range e.EventTypes {
+		if e.EventTypes[i] == string(eventType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WebhookDelivery struct {
+	ID             uint64
+	SubscriptionID uint
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Attempt        uint
+	Status         WebhookDeliveryStatus
+	ResponseStatus *int
+	ResponseBody   *string
+	Error          *string
+	DurationMs     *uint
+	ScheduledAt    time.Time
+	AttemptedAt    *time.Time
+	ReplayOfID     *uint64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
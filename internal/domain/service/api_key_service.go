@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"goapptemp/config"
+	"goapptemp/constant"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/internal/shared/token"
+	"goapptemp/pkg/logger"
+	"slices"
+	"strings"
+	"time"
+
+	repo "goapptemp/internal/adapter/repository"
+
+	serror "goapptemp/internal/domain/service/error"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	apiKeyScheme = "gak"
+	// apiKeyTouchInterval bounds how often last_used_at is written for a busy key.
+	apiKeyTouchInterval = time.Minute
+)
+
+var _ APIKeyService = (*apiKeyService)(nil)
+
+type APIKeyService interface {
+	Create(ctx context.Context, req *CreateAPIKeyRequest) (*entity.APIKey, string, error)
+	Find(ctx context.Context, req *FindAPIKeysRequest) ([]*entity.APIKey, int, error)
+	FindOne(ctx context.Context, req *FindOneAPIKeyRequest) (*entity.APIKey, error)
+	Revoke(ctx context.Context, req *RevokeAPIKeyRequest) error
+	Authenticate(ctx context.Context, rawKey string) (*AuthParams, error)
+}
+
+type apiKeyService struct {
+	config *config.Config
+	repo   repo.Repository
+	logger logger.Logger
+	auth   AuthService
+}
+
+func NewAPIKeyService(config *config.Config, repo repo.Repository, logger logger.Logger, auth AuthService) *apiKeyService {
+	return &apiKeyService{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		auth:   auth,
+	}
+}
+
+// WithAPIKey marks ctx as acting through apiKey, which limits AuthorizationCheck to the key's permissions.
+func WithAPIKey(ctx context.Context, apiKey *entity.APIKey) context.Context {
+	return context.WithValue(ctx, constant.CtxKeyAPIKey, apiKey)
+}
+
+func APIKeyFromContext(ctx context.Context) *entity.APIKey {
+	apiKey, _ := ctx.Value(constant.CtxKeyAPIKey).(*entity.APIKey)
+
+	return apiKey
+}
+
+type CreateAPIKeyRequest struct {
+	AuthParams  *AuthParams
+	Name        string
+	Permissions []string
+	ExpiresAt   *time.Time
+}
+
+// Create issues a key acting as the caller. The returned secret is the only time the full key is
+// available; only its hash is stored.
+func (s *apiKeyService) Create(ctx context.Context, req *CreateAPIKeyRequest) (*entity.APIKey, string, error) {
+	companyID, err := s.authorize(ctx, req.AuthParams, "API_KEY.CREATE")
+	if err != nil {
+		return nil, "", err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Expiry must be in the future")
+	}
+
+	permissions, err := s.grantablePermissions(ctx, req.AuthParams.AccessTokenClaims.UserID, req.Permissions)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix, secret, err := generateAPIKey()
+	if err != nil {
+		return nil, "", exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to generate API key")
+	}
+
+	apiKey, err := s.repo.MySQL().APIKey().Create(ctx, &entity.APIKey{
+		CompanyID:   companyID,
+		UserID:      req.AuthParams.AccessTokenClaims.UserID,
+		Name:        req.Name,
+		Prefix:      prefix,
+		SecretHash:  hashAPIKey(secret),
+		Permissions: permissions,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		return nil, "", serror.TranslateRepoError(err)
+	}
+
+	return apiKey, secret, nil
+}
+
+type FindAPIKeysRequest struct {
+	AuthParams *AuthParams
+	Filter     *mysqlrepository.FilterAPIKeyPayload
+}
+
+func (s *apiKeyService) Find(ctx context.Context, req *FindAPIKeysRequest) ([]*entity.APIKey, int, error) {
+	companyID, err := s.authorize(ctx, req.AuthParams, "API_KEY.READ")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filter := req.Filter
+	if filter == nil {
+		filter = &mysqlrepository.FilterAPIKeyPayload{}
+	}
+
+	filter.CompanyIDs = []uint{companyID}
+
+	apiKeys, totalCount, err := s.repo.MySQL().APIKey().Find(ctx, filter)
+	if err != nil {
+		return nil, 0, serror.TranslateRepoError(err)
+	}
+
+	return apiKeys, totalCount, nil
+}
+
+type FindOneAPIKeyRequest struct {
+	AuthParams *AuthParams
+	APIKeyID   uint
+}
+
+func (s *apiKeyService) FindOne(ctx context.Context, req *FindOneAPIKeyRequest) (*entity.APIKey, error) {
+	companyID, err := s.authorize(ctx, req.AuthParams, "API_KEY.READ")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findOwned(ctx, companyID, req.APIKeyID)
+}
+
+type RevokeAPIKeyRequest struct {
+	AuthParams *AuthParams
+	APIKeyID   uint
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, req *RevokeAPIKeyRequest) error {
+	companyID, err := s.authorize(ctx, req.AuthParams, "API_KEY.DELETE")
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.findOwned(ctx, companyID, req.APIKeyID); err != nil {
+		return err
+	}
+
+	if err := s.repo.MySQL().APIKey().Revoke(ctx, req.APIKeyID); err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	return nil
+}
+
+// Authenticate resolves a raw X-API-Key value into auth params for the user the key acts as.
+// Every failure reads the same so callers cannot probe which prefixes exist.
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey string) (*AuthParams, error) {
+	prefix, ok := parseAPIKeyPrefix(rawKey)
+	if !ok {
+		return nil, exception.ErrAPIKeyInvalid
+	}
+
+	apiKey, err := s.repo.MySQL().APIKey().FindByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, exception.ErrNotFound) {
+			return nil, exception.ErrAPIKeyInvalid
+		}
+
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey.SecretHash), []byte(hashAPIKey(rawKey))) != 1 {
+		return nil, exception.ErrAPIKeyInvalid
+	}
+
+	now := time.Now()
+	if !apiKey.Active(now) {
+		return nil, exception.ErrAPIKeyInvalid
+	}
+
+	// A key stops working while the user it acts as is suspended, like their tokens do.
+	owner, err := s.repo.MySQL().User().FindByID(ctx, apiKey.UserID)
+	if err != nil {
+		if errors.Is(err, exception.ErrNotFound) {
+			return nil, exception.ErrAPIKeyInvalid
+		}
+
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if owner.Status != entity.UserStatusActive {
+		return nil, exception.ErrAPIKeyInvalid
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.repo.MySQL().APIKey().TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			s.logger.Warn().Err(err).Msgf("Failed to record last use of API key %s", apiKey.Prefix)
+		}
+	}
+
+	return &AuthParams{
+		AccessTokenClaims: &token.AccessTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{Subject: apiKey.Prefix},
+			UserID:           apiKey.UserID,
+		},
+		APIKey: apiKey,
+	}, nil
+}
+
+// grantablePermissions validates the requested scopes; a key can never hold a permission its creator lacks.
+func (s *apiKeyService) grantablePermissions(ctx context.Context, userID uint, requested []string) ([]string, error) {
+	permissions := make([]string, 0, len(requested))
+
+	for _, code := range requested {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if _, ok := constant.PermissionCodes[code]; !ok {
+			return nil, exception.Newf(exception.TypeBadRequest, exception.CodeBadRequest, "Unknown permission %q", code)
+		}
+
+		if slices.Contains(permissions, code) {
+			continue
+		}
+
+		ok, err := s.auth.AuthorizationCheck(ctx, userID, code)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, exception.Newf(exception.TypeForbidden, exception.CodeForbidden, "Cannot grant permission %s", code)
+		}
+
+		permissions = append(permissions, code)
+	}
+
+	if len(permissions) == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "At least one permission is required")
+	}
+
+	slices.Sort(permissions)
+
+	return permissions, nil
+}
+
+// authorize checks the permission and returns the caller's company, which scopes every key they can see.
+func (s *apiKeyService) authorize(ctx context.Context, authParams *AuthParams, permissionCode string) (uint, error) {
+	if authParams == nil || authParams.AccessTokenClaims == nil {
+		return 0, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, authParams.AccessTokenClaims.UserID, permissionCode)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		return 0, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	user, err := s.repo.MySQL().User().FindByID(ctx, authParams.AccessTokenClaims.UserID)
+	if err != nil {
+		return 0, serror.TranslateRepoError(err)
+	}
+
+	return user.CompanyID, nil
+}
+
+func (s *apiKeyService) findOwned(ctx context.Context, companyID, apiKeyID uint) (*entity.APIKey, error) {
+	apiKey, err := s.repo.MySQL().APIKey().FindByID(ctx, apiKeyID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if apiKey.CompanyID != companyID {
+		return nil, exception.New(exception.TypeNotFound, exception.CodeNotFound, "API key not found")
+	}
+
+	return apiKey, nil
+}
+
+// generateAPIKey returns the lookup prefix and the full key, formatted as "gak_<id>_<secret>".
+func generateAPIKey() (string, string, error) {
+	b := make([]byte, 38)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	prefix := apiKeyScheme + "_" + hex.EncodeToString(b[:6])
+
+	return prefix, prefix + "_" + hex.EncodeToString(b[6:]), nil
+}
+
+func parseAPIKeyPrefix(rawKey string) (string, bool) {
+	scheme, rest, ok := strings.Cut(rawKey, "_")
+	if !ok || scheme != apiKeyScheme {
+		return "", false
+	}
+
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", false
+	}
+
+	return scheme + "_" + id, true
+}
+
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -13,12 +13,16 @@ import (
 	"goapptemp/internal/shared/exception"
 	"goapptemp/internal/shared/token"
 	"goapptemp/pkg/logger"
+	"goapptemp/pkg/metrics"
+	"goapptemp/pkg/taskqueue"
 	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/uuid"
 )
 
+const passwordResetTokenTTL = 15 * time.Minute
+
 var _ AuthService = (*authService)(nil)
 
 type AuthService interface {
@@ -29,35 +33,48 @@ type AuthService interface {
 	ForgetPassword(ctx context.Context, req *ForgetPasswordRequest) error
 	VerifyResetToken(ctx context.Context, req *VerifyResetTokenRequest) error
 	ResetPassword(ctx context.Context, req *ResetPasswordRequest) error
+	ChangePassword(ctx context.Context, req *ChangePasswordRequest) error
+	RevokeUserTokens(ctx context.Context, userID uint) error
+	Me(ctx context.Context, req *MeRequest) (*entity.User, error)
 }
 
 type authService struct {
-	config          *config.Config
-	token           token.Token
-	repository      repository.Repository
-	logger          logger.Logger
-	notificationSvc NotificationService
+	config     *config.Config
+	token      token.Token
+	repository repository.Repository
+	logger     logger.Logger
+	tasks      taskqueue.Queue
+	policy     *LoginPolicy
+	passwords  *PasswordPolicy
 }
 
+// NewAuthService hands emails and notifications to the task queue instead of calling the
+// notification service, whose handlers are registered on the same queue.
 func NewAuthService(
 	config *config.Config,
 	token token.Token,
 	repo repository.Repository,
 	log logger.Logger,
-	notificationSvc NotificationService,
+	tasks taskqueue.Queue,
+	policy *LoginPolicy,
+	passwords *PasswordPolicy,
 ) *authService {
 	return &authService{
-		config:          config,
-		token:           token,
-		repository:      repo,
-		logger:          log,
-		notificationSvc: notificationSvc,
+		config:     config,
+		token:      token,
+		repository: repo,
+		logger:     log,
+		tasks:      tasks,
+		policy:     policy,
+		passwords:  passwords,
 	}
 }
 
 type AuthParams struct {
 	AccessToken       string
 	AccessTokenClaims *token.AccessTokenClaims
+	// APIKey is set when the caller authenticated with an API key instead of a bearer token.
+	APIKey *entity.APIKey
 }
 
 type LoginRequest struct {
@@ -65,7 +82,31 @@ type LoginRequest struct {
 	Password string
 }
 
-func (s *authService) Login(ctx context.Context, req *LoginRequest) (*entity.User, error) {
+// Login rejects an expired password only after the credentials check out, so expiry reveals nothing
+// to someone guessing; the user then has to go through ChangePassword.
+func (s *authService) Login(ctx context.Context, req *LoginRequest) (user *entity.User, err error) {
+	defer func() { metrics.ObserveLogin(metrics.LoginPassword, err == nil) }()
+
+	user, err = s.verifyCredentials(ctx, req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.passwords.Expired(user, time.Now()) {
+		return nil, exception.ErrPasswordExpired
+	}
+
+	user.Token, err = issueToken(s.token, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// verifyCredentials checks a username and password the same way for every path that accepts them,
+// recording failures for the brute-force limits and clearing them on success.
+func (s *authService) verifyCredentials(ctx context.Context, username, password string) (*entity.User, error) {
 	var (
 		user                  *entity.User
 		passwordHashToCompare string
@@ -76,17 +117,18 @@ func (s *authService) Login(ctx context.Context, req *LoginRequest) (*entity.Use
 
 	errGenericLogin := exception.New(exception.TypeBadRequest, exception.CodeUserInvalidLogin, "Invalid username or password")
 
-	isLocked, err := s.repository.Redis().CheckLockedUserExists(ctx, req.Username)
+	isLocked, err := s.repository.Redis().CheckLockedUserExists(ctx, username)
 	if err != nil {
 		return nil, serror.TranslateRepoError(err)
 	}
 
-	users, _, err := s.repository.MySQL().User().Find(ctx, &mysqlrepository.FilterUserPayload{Usernames: []string{req.Username}})
+	users, _, err := s.repository.MySQL().User().Find(ctx, &mysqlrepository.FilterUserPayload{Usernames: []string{username}})
 	if err != nil {
 		return nil, serror.TranslateRepoError(err)
 	}
 
-	if len(users) == 0 || users[0] == nil || isLocked {
+	// Invited users have not chosen a password yet, so they are treated like unknown usernames.
+	if len(users) == 0 || users[0] == nil || isLocked || users[0].Status == entity.UserStatusPending {
 		passwordHashToCompare = constant.DummyPasswordHash
 		user = nil
 	} else {
@@ -94,7 +136,7 @@ func (s *authService) Login(ctx context.Context, req *LoginRequest) (*entity.Use
 		passwordHashToCompare = user.Password
 	}
 
-	errPass := shared.CheckPassword(req.Password, passwordHashToCompare)
+	errPass := shared.CheckPassword(password, passwordHashToCompare)
 	if errPass == nil {
 		if user != nil && !isLocked {
 			loginSuccessful = true
@@ -102,80 +144,122 @@ func (s *authService) Login(ctx context.Context, req *LoginRequest) (*entity.Use
 	}
 
 	if !loginSuccessful {
-		go func() {
-			bgCtx := context.Background()
-
-			errUser := s.repository.Redis().RecordUserFailure(bgCtx, req.Username)
-			if errUser != nil {
-				s.logger.Error().Msgf("Failed to record user failure: %v", errUser)
-			}
+		s.enqueue(ctx, TaskRecordUserFailure, &loginAttemptTask{Username: username})
 
-			_, _, errIP := s.repository.Redis().RecordIPFailure(bgCtx, ip)
-			if errIP != nil {
-				s.logger.Error().Msgf(fmt.Sprintf("Failed to record IP failure: %v", errIP))
-			}
-		}()
+		if ip != "" {
+			s.enqueue(ctx, TaskRecordIPFailure, &loginAttemptTask{IP: ip})
+		}
 
 		return nil, errGenericLogin
 	}
 
-	go func() {
-		bgCtx := context.Background()
-		if err := s.repository.Redis().DeleteUserAttempts(bgCtx, req.Username); err != nil {
-			s.logger.Error().Err(err).Msgf("Failed to delete user attempts for %s", req.Username)
-		}
+	s.enqueue(ctx, TaskClearLoginAttempts, &loginAttemptTask{Username: username, IP: ip})
+
+	// Suspension is only revealed to someone who already knows the password.
+	if user.Status != entity.UserStatusActive {
+		return nil, exception.ErrUserInactive
+	}
+
+	return user, nil
+}
+
+type RefreshRequest struct {
+	RefreshToken string
+}
+
+func (s *authService) Refresh(ctx context.Context, req *RefreshRequest) (*entity.Token, error) {
+	refreshTokenClaims, err := s.token.VerifyRefreshToken(req.RefreshToken)
+	if err != nil {
+		return nil, exception.Wrap(err, exception.TypeUnauthorized, exception.CodeUnauthorized, "invalid refresh token")
+	}
 
-		if err := s.repository.Redis().DeleteIPAttempts(bgCtx, ip); err != nil {
-			s.logger.Error().Err(err).Msgf("Failed to delete IP attempts for %s", ip)
+	if refreshTokenClaims.IssuedAt != nil {
+		revoked, err := s.repository.Redis().CheckUserTokenRevoked(ctx, refreshTokenClaims.UserID, refreshTokenClaims.IssuedAt.Time)
+		if err != nil {
+			return nil, serror.TranslateRepoError(err)
 		}
 
-		if err := s.repository.Redis().DeleteBlockCount(bgCtx, ip); err != nil {
-			s.logger.Error().Err(err).Msgf("Failed to delete block count for %s", ip)
+		if revoked {
+			return nil, exception.ErrUserTokensRevoked
 		}
-	}()
+	}
 
-	accessToken, accessExpiresAt, err := s.token.GenerateAccessToken(user.ID)
+	user, err := s.repository.MySQL().User().FindByID(ctx, refreshTokenClaims.UserID)
 	if err != nil {
-		return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "failed to generate access token")
+		return nil, serror.TranslateRepoError(err)
 	}
 
-	refreshToken, refreshExpiresAt, err := s.token.GenerateRefreshToken(user.ID)
-	if err != nil {
-		return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "failed to generate refresh token")
+	if user.Status != entity.UserStatusActive {
+		return nil, exception.ErrUserInactive
 	}
 
-	user.Token = &entity.Token{
-		AccessToken:           accessToken,
-		AccessTokenExpiresAt:  accessExpiresAt,
-		RefreshToken:          refreshToken,
-		RefreshTokenExpiresAt: refreshExpiresAt,
-		TokenType:             constant.TokenType,
+	return issueToken(s.token, user.ID)
+}
+
+// RevokeUserTokens invalidates every access and refresh token issued to the user so far.
+func (s *authService) RevokeUserTokens(ctx context.Context, userID uint) error {
+	lifetime := time.Duration(max(s.config.Token.AccessTokenDuration, s.config.Token.RefreshTokenDuration)) * time.Minute
+	if lifetime <= 0 {
+		return nil
 	}
 
-	return user, nil
+	if err := s.repository.Redis().RevokeUserTokens(ctx, userID, time.Now(), lifetime); err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	return nil
 }
 
-type RefreshRequest struct {
-	RefreshToken string
+type MeRequest struct {
+	AuthParams *AuthParams
 }
 
-func (s *authService) Refresh(ctx context.Context, req *RefreshRequest) (*entity.Token, error) {
-	refreshTokenClaims, err := s.token.VerifyRefreshToken(req.RefreshToken)
-	if err != nil {
-		return nil, exception.Wrap(err, exception.TypeUnauthorized, exception.CodeUnauthorized, "invalid refresh token")
+// Me returns the signed-in user. Under impersonation the user carries the impersonating admin, so
+// clients can show that the session is not the user's own.
+func (s *authService) Me(ctx context.Context, req *MeRequest) (*entity.User, error) {
+	if req.AuthParams == nil || req.AuthParams.AccessTokenClaims == nil {
+		return nil, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
 	}
 
-	user, err := s.repository.MySQL().User().FindByID(ctx, refreshTokenClaims.UserID)
+	claims := req.AuthParams.AccessTokenClaims
+
+	user, err := s.repository.MySQL().User().FindByID(ctx, claims.UserID)
 	if err != nil {
 		return nil, serror.TranslateRepoError(err)
 	}
 
-	accessToken, accessExpiresAt, err := s.token.GenerateAccessToken(user.ID)
+	user.Password = ""
+
+	if !claims.Impersonated() {
+		return user, nil
+	}
+
+	user.Impersonation = &entity.Impersonation{ImpersonatorID: claims.Act.UserID}
+	if claims.ExpiresAt != nil {
+		user.Impersonation.ExpiresAt = claims.ExpiresAt.Time
+	}
+
+	impersonator, err := s.repository.MySQL().User().FindByID(ctx, claims.Act.UserID)
+	if err != nil && !errors.Is(err, exception.ErrNotFound) {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if impersonator != nil {
+		impersonator.Password = ""
+		user.Impersonation.Impersonator = impersonator
+	}
+
+	return user, nil
+}
+
+// issueToken creates the access and refresh token pair returned by every login path.
+func issueToken(tokenManager token.Token, userID uint) (*entity.Token, error) {
+	accessToken, accessExpiresAt, err := tokenManager.GenerateAccessToken(userID)
 	if err != nil {
 		return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "failed to generate access token")
 	}
 
-	refreshToken, refreshExpiresAt, err := s.token.GenerateRefreshToken(user.ID)
+	refreshToken, refreshExpiresAt, err := tokenManager.GenerateRefreshToken(userID)
 	if err != nil {
 		return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "failed to generate refresh token")
 	}
@@ -239,11 +323,17 @@ func (s *authService) Logout(ctx context.Context, req *LogoutRequest) error {
 	return nil
 }
 
+// AuthorizationCheck reports whether the user holds the permission. Requests made with an API key
+// are further limited to the key's scopes.
 func (s *authService) AuthorizationCheck(ctx context.Context, userID uint, permissionCode string) (bool, error) {
 	if userID == 0 {
 		return false, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "User id not provided")
 	}
 
+	if apiKey := APIKeyFromContext(ctx); apiKey != nil && !apiKey.Allows(permissionCode) {
+		return false, nil
+	}
+
 	hasPermission, err := s.repository.MySQL().User().HasPermission(ctx, userID, permissionCode)
 	if err != nil {
 		return false, serror.TranslateRepoError(err)
@@ -272,10 +362,13 @@ func (s *authService) ForgetPassword(ctx context.Context, req *ForgetPasswordReq
 	}
 
 	user := users[0]
-	resetToken := uuid.NewString()
-	ttl := time.Minute * 15
+	if user.Status == entity.UserStatusPending {
+		s.logger.Warn().Msgf("Password reset attempt for pending user %d", user.ID)
+		return nil
+	}
 
-	err = s.repository.Redis().StoreResetToken(ctx, resetToken, user.ID, ttl)
+	resetToken := uuid.NewString()
+	err = s.repository.Redis().StoreResetToken(ctx, resetToken, user.ID, passwordResetTokenTTL)
 	if err != nil {
 		return serror.TranslateRepoError(err)
 	}
@@ -285,14 +378,7 @@ func (s *authService) ForgetPassword(ctx context.Context, req *ForgetPasswordReq
 		resetToken,
 	)
 
-	go func() {
-		bgCtx := context.Background()
-
-		err := s.notificationSvc.SendPasswordResetEmail(bgCtx, user.Email, resetLink)
-		if err != nil {
-			s.logger.Error().Err(err).Msgf("Failed to send password reset email to user: %d", user.ID)
-		}
-	}()
+	s.enqueue(ctx, TaskSendPasswordResetEmail, &emailTask{UserID: user.ID, Recipient: NewEmailRecipient(user), Link: resetLink})
 
 	return nil
 }
@@ -329,62 +415,73 @@ func (s *authService) ResetPassword(ctx context.Context, req *ResetPasswordReque
 		return serror.TranslateRepoError(err)
 	}
 
-	if len(req.NewPassword) < constant.MinPasswordLength {
-		return exception.Newf(exception.TypeBadRequest, exception.CodeValidationFailed, "Password must be at least %d characters long", constant.MinPasswordLength)
-	}
-
 	user, err := s.repository.MySQL().User().FindByID(ctx, userID)
 	if err != nil {
 		return serror.TranslateRepoError(err)
 	}
 
-	hashedPassword, err := shared.HashPassword(req.NewPassword)
-	if err != nil {
-		return exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "failed to hash new password")
+	if err := s.changePassword(ctx, user, "new_password", req.NewPassword); err != nil {
+		return err
 	}
 
-	updatePayload := &mysqlrepository.UpdateUserPayload{
-		ID:       userID,
-		Password: &hashedPassword,
-	}
-	if _, err := s.repository.MySQL().User().Update(ctx, updatePayload); err != nil {
-		return serror.TranslateRepoError(err)
-	}
+	ip, _ := ctx.Value(constant.CtxKeyRequestIP).(string)
 
-	go func() {
-		bgCtx := context.Background()
+	s.enqueue(ctx, TaskDeleteResetToken, &resetTokenTask{UserID: userID, Token: req.Token})
+	s.enqueue(ctx, TaskClearLoginAttempts, &loginAttemptTask{Username: user.Username, IP: ip})
+	s.enqueue(ctx, TaskSendPasswordResetSuccessEmail, &emailTask{UserID: user.ID, Recipient: NewEmailRecipient(user)})
 
-		err := s.repository.Redis().DeleteResetToken(bgCtx, req.Token)
-		if err != nil {
-			s.logger.Error().Err(err).Msgf("CRITICAL: Failed to delete reset token for user %d. Token: %s", userID, req.Token)
-		}
-	}()
+	return nil
+}
 
-	go func() {
-		bgCtx := context.Background()
-		ip, _ := ctx.Value(constant.CtxKeyRequestIP).(string)
+type ChangePasswordRequest struct {
+	Username        string
+	CurrentPassword string
+	NewPassword     string
+}
 
-		if err := s.repository.Redis().DeleteUserAttempts(bgCtx, user.Username); err != nil {
-			s.logger.Error().Err(err).Msgf("Failed to delete user attempts for %s", user.Username)
-		}
+// ChangePassword takes the current password instead of a token so that users whose password has
+// expired, and who therefore cannot log in, can still replace it.
+func (s *authService) ChangePassword(ctx context.Context, req *ChangePasswordRequest) error {
+	user, err := s.verifyCredentials(ctx, req.Username, req.CurrentPassword)
+	if err != nil {
+		return err
+	}
 
-		if err := s.repository.Redis().DeleteIPAttempts(bgCtx, ip); err != nil {
-			s.logger.Error().Err(err).Msgf("Failed to delete IP attempts for %s", ip)
-		}
+	return s.changePassword(ctx, user, "new_password", req.NewPassword)
+}
 
-		if err := s.repository.Redis().DeleteBlockCount(bgCtx, ip); err != nil {
-			s.logger.Error().Err(err).Msgf("Failed to delete block count for %s", ip)
-		}
-	}()
+// changePassword applies the password policy and stores the new password together with its history entry.
+func (s *authService) changePassword(ctx context.Context, user *entity.User, field, newPassword string) error {
+	if err := s.passwords.Validate(field, newPassword, user); err != nil {
+		return err
+	}
 
-	go func() {
-		bgCtx := context.Background()
+	if err := s.passwords.CheckHistory(ctx, s.repository.MySQL(), field, newPassword, user); err != nil {
+		return serror.TranslateRepoError(err)
+	}
 
-		err := s.notificationSvc.SendPasswordResetSuccessEmail(bgCtx, user.Email)
-		if err != nil {
-			s.logger.Error().Err(err).Msgf("Failed to send password reset success email to user: %d", user.ID)
+	hashedPassword, err := shared.HashPassword(newPassword)
+	if err != nil {
+		return exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "failed to hash new password")
+	}
+
+	now := time.Now()
+	updatePayload := &mysqlrepository.UpdateUserPayload{
+		ID:                user.ID,
+		Password:          &hashedPassword,
+		PasswordChangedAt: &now,
+	}
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		if _, err := txRepo.User().Update(ctx, updatePayload); err != nil {
+			return err
 		}
-	}()
+
+		return s.passwords.Remember(ctx, txRepo, user.ID, hashedPassword)
+	}
+	if err := s.repository.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return serror.TranslateRepoError(err)
+	}
 
 	return nil
 }
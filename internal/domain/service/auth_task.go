@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/pkg/taskqueue"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	TaskRecordUserFailure  = "auth.record_user_failure"
+	TaskRecordIPFailure    = "auth.record_ip_failure"
+	TaskClearLoginAttempts = "auth.clear_login_attempts"
+	TaskDeleteResetToken   = "auth.delete_reset_token"
+)
+
+type loginAttemptTask struct {
+	Username string `json:"username,omitempty"`
+	IP       string `json:"ip,omitempty"`
+}
+
+type resetTokenTask struct {
+	UserID uint   `json:"user_id"`
+	Token  string `json:"token"`
+}
+
+func (s *authService) registerTasks(queue taskqueue.Queue) error {
+	handlers := map[string]taskqueue.Handler{
+		TaskRecordUserFailure:  s.recordUserFailure,
+		TaskRecordIPFailure:    s.recordIPFailure,
+		TaskClearLoginAttempts: s.clearLoginAttempts,
+		TaskDeleteResetToken:   s.deleteResetToken,
+	}
+
+	for taskType, handler := range handlers {
+		if err := queue.Register(taskType, handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enqueue hands a side effect to the task queue. The request has already been answered by the
+// time it runs, so a failure to enqueue is logged rather than returned to the caller.
+func (s *authService) enqueue(ctx context.Context, taskType string, payload any) {
+	if err := s.tasks.Enqueue(context.WithoutCancel(ctx), taskType, payload); err != nil {
+		s.logger.Error().Err(err).Msgf("Failed to enqueue %s task", taskType)
+	}
+}
+
+// recordUserFailure and recordIPFailure run as separate tasks because the counters are not
+// idempotent: a retry of one must not count the failure against the other a second time.
+func (s *authService) recordUserFailure(ctx context.Context, task *taskqueue.Task) error {
+	var payload loginAttemptTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	locked, err := s.repository.Redis().RecordUserFailure(ctx, payload.Username, s.policy.User)
+	if err != nil {
+		return errors.Wrapf(err, "failed to record user failure for %s", payload.Username)
+	}
+
+	if locked {
+		s.notifyAccountLocked(ctx, payload.Username)
+	}
+
+	return nil
+}
+
+func (s *authService) recordIPFailure(ctx context.Context, task *taskqueue.Task) error {
+	var payload loginAttemptTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	if payload.IP == "" || s.policy.Allowlisted(payload.IP) {
+		return nil
+	}
+
+	if _, _, err := s.repository.Redis().RecordIPFailure(ctx, payload.IP, s.policy.IP); err != nil {
+		return errors.Wrapf(err, "failed to record IP failure for %s", payload.IP)
+	}
+
+	return nil
+}
+
+func (s *authService) clearLoginAttempts(ctx context.Context, task *taskqueue.Task) error {
+	var payload loginAttemptTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	if err := s.repository.Redis().DeleteUserAttempts(ctx, payload.Username); err != nil {
+		return errors.Wrapf(err, "failed to delete user attempts for %s", payload.Username)
+	}
+
+	if payload.IP == "" {
+		return nil
+	}
+
+	if err := s.repository.Redis().DeleteIPAttempts(ctx, payload.IP); err != nil {
+		return errors.Wrapf(err, "failed to delete IP attempts for %s", payload.IP)
+	}
+
+	if err := s.repository.Redis().DeleteBlockCount(ctx, payload.IP); err != nil {
+		return errors.Wrapf(err, "failed to delete block count for %s", payload.IP)
+	}
+
+	return nil
+}
+
+func (s *authService) deleteResetToken(ctx context.Context, task *taskqueue.Task) error {
+	var payload resetTokenTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	if err := s.repository.Redis().DeleteResetToken(ctx, payload.Token); err != nil {
+		return errors.Wrapf(err, "failed to delete reset token for user %d", payload.UserID)
+	}
+
+	return nil
+}
+
+// notifyAccountLocked tells the owner of username that their account was locked. Attempts are counted
+// for any username, so nothing is sent when it does not belong to a user. Usernames are only unique
+// within a company and the lockout does not record which user was targeted, so nothing is sent when
+// several users share the username either.
+func (s *authService) notifyAccountLocked(ctx context.Context, username string) {
+	users, _, err := s.repository.MySQL().User().Find(ctx, &mysqlrepository.FilterUserPayload{Usernames: []string{username}})
+	if err != nil {
+		s.logger.Error().Err(err).Msgf("Failed to find locked user %s", username)
+		return
+	}
+
+	if len(users) != 1 {
+		if len(users) > 1 {
+			s.logger.Warn().Msgf("Skipped lockout notification for %s, the username belongs to %d users", username, len(users))
+		}
+
+		return
+	}
+
+	s.enqueue(ctx, TaskDispatchNotification, &notificationTask{
+		UserID: users[0].ID,
+		Type:   entity.NotificationAccountLocked,
+		Data: map[string]any{
+			"LockedMinutes": int(s.policy.User.Lockout / time.Minute),
+		},
+	})
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"goapptemp/constant"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+)
+
+// BulkResult is the outcome of a bulk operation for one ID; Err is nil when it succeeded.
+type BulkResult struct {
+	ID  uint
+	Err error
+}
+
+// bulkResults tracks the IDs of a bulk request in request order. IDs are marked failed while the
+// operation checks them; whatever is still pending at the end is what gets applied.
+type bulkResults struct {
+	results []*BulkResult
+	byID    map[uint]*BulkResult
+}
+
+func newBulkResults(ids []uint) (*bulkResults, error) {
+	if len(ids) == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeValidationFailed, "At least one ID is required")
+	}
+
+	if len(ids) > constant.BulkMaxIDs {
+		return nil, exception.Newf(exception.TypeBadRequest, exception.CodeValidationFailed, "Maximum %d IDs can be processed at once", constant.BulkMaxIDs)
+	}
+
+	r := &bulkResults{
+		results: make([]*BulkResult, 0, len(ids)),
+		byID:    make(map[uint]*BulkResult, len(ids)),
+	}
+
+	for _, id := range ids {
+		if id == 0 {
+			return nil, exception.New(exception.TypeBadRequest, exception.CodeValidationFailed, "IDs cannot be zero")
+		}
+
+		if _, ok := r.byID[id]; ok {
+			continue
+		}
+
+		result := &BulkResult{ID: id}
+		r.results = append(r.results, result)
+		r.byID[id] = result
+	}
+
+	return r, nil
+}
+
+// fail records err for id unless the ID already failed, keeping the first reason.
+func (r *bulkResults) fail(id uint, err error) {
+	if result, ok := r.byID[id]; ok && result.Err == nil {
+		result.Err = err
+	}
+}
+
+// failMissing marks the pending IDs absent from found as not found.
+func (r *bulkResults) failMissing(found map[uint]bool, resource string) {
+	for _, id := range r.pending() {
+		if !found[id] {
+			r.fail(id, exception.Newf(exception.TypeNotFound, exception.CodeNotFound, "%s not found", resource))
+		}
+	}
+}
+
+// failDependent marks the IDs that still have dependent records, as reported by
+// StoreProcedureRepository.FindDependencies.
+func (r *bulkResults) failDependent(dependencies map[uint][]*entity.Dependency, resource string) {
+	for _, id := range r.pending() {
+		if len(dependencies[id]) > 0 {
+			r.fail(id, dependencyError(resource, dependencies[id]))
+		}
+	}
+}
+
+func (r *bulkResults) pending() []uint {
+	ids := make([]uint, 0, len(r.results))
+
+	for _, result := range r.results {
+		if result.Err == nil {
+			ids = append(ids, result.ID)
+		}
+	}
+
+	return ids
+}
+
+func (r *bulkResults) list() []*BulkResult {
+	return r.results
+}
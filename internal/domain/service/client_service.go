@@ -11,6 +11,7 @@ import (
 	"goapptemp/internal/shared"
 	"goapptemp/internal/shared/exception"
 	"goapptemp/pkg/logger"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -22,26 +23,32 @@ type ClientService interface {
 	Create(ctx context.Context, req *CreateClientRequest) (*entity.Client, error)
 	Update(ctx context.Context, req *UpdateClientRequest) (*entity.Client, error)
 	Delete(ctx context.Context, req *DeleteClientRequest) error
+	BulkDelete(ctx context.Context, req *BulkDeleteClientsRequest) ([]*BulkResult, error)
+	FindDependencies(ctx context.Context, req *FindClientDependenciesRequest) ([]*entity.Dependency, error)
 	Find(ctx context.Context, req *FindClientsRequest) ([]*entity.Client, int, error)
 	FindOne(ctx context.Context, req *FindOneClientRequest) (*entity.Client, error)
 	IsDeletable(ctx context.Context, req *IsDeletableClientRequest) (bool, error)
 }
 
 type clientService struct {
-	config *config.Config
-	repo   repository.Repository
-	log    logger.Logger
-	auth   AuthService
-	pubsub PubsubService
+	config   *config.Config
+	repo     repository.Repository
+	log      logger.Logger
+	auth     AuthService
+	pubsub   PubsubService
+	events   EventService
+	policies *AccessPolicy
 }
 
-func NewClientService(config *config.Config, repo repository.Repository, log logger.Logger, auth AuthService, pubsub PubsubService) *clientService {
+func NewClientService(config *config.Config, repo repository.Repository, log logger.Logger, auth AuthService, pubsub PubsubService, events EventService, policies *AccessPolicy) *clientService {
 	return &clientService{
-		config: config,
-		repo:   repo,
-		log:    log,
-		auth:   auth,
-		pubsub: pubsub,
+		config:   config,
+		repo:     repo,
+		log:      log,
+		auth:     auth,
+		pubsub:   pubsub,
+		events:   events,
+		policies: policies,
 	}
 }
 
@@ -144,6 +151,8 @@ func (s *clientService) Create(ctx context.Context, req *CreateClientRequest) (*
 
 	createdClient.ClientSupportFeatures = nil
 
+	s.events.Publish(ctx, newClientEvent(entity.EventClientCreated, createdClient, req.AuthParams.AccessTokenClaims.UserID))
+
 	return createdClient, nil
 }
 
@@ -166,6 +175,11 @@ func (s *clientService) Find(ctx context.Context, req *FindClientsRequest) ([]*e
 		return nil, 0, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
 	}
 
+	req.Filter.Policy, err = s.policies.Filter(ctx, s.repo.MySQL(), req.AuthParams, PolicyResourceClient, PolicyActionRead)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	clients, totalCount, err := s.repo.MySQL().Client().Find(ctx, req.Filter)
 	if err != nil {
 		return nil, 0, serror.TranslateRepoError(err)
@@ -202,6 +216,10 @@ func (s *clientService) FindOne(ctx context.Context, req *FindOneClientRequest)
 		return nil, serror.TranslateRepoError(err)
 	}
 
+	if err := s.policies.Authorize(ctx, s.repo.MySQL(), req.AuthParams, PolicyResourceClient, PolicyActionRead, clientPolicyAttributes(client)); err != nil {
+		return nil, err
+	}
+
 	return client, nil
 }
 
@@ -232,6 +250,10 @@ func (s *clientService) Update(ctx context.Context, req *UpdateClientRequest) (*
 		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Client ID required for update")
 	}
 
+	if err := s.authorizeClient(ctx, req.AuthParams, PolicyActionUpdate, req.Update.ID); err != nil {
+		return nil, err
+	}
+
 	var updatedClient *entity.Client
 
 	var iconBase64, format string
@@ -300,12 +322,16 @@ func (s *clientService) Update(ctx context.Context, req *UpdateClientRequest) (*
 
 	updatedClient.ClientSupportFeatures = nil
 
+	s.events.Publish(ctx, newClientEvent(entity.EventClientUpdated, updatedClient, req.AuthParams.AccessTokenClaims.UserID))
+
 	return updatedClient, nil
 }
 
 type DeleteClientRequest struct {
 	AuthParams *AuthParams
 	ClientID   uint
+	// Cascade soft-deletes dependent rows that support it instead of refusing the deletion.
+	Cascade bool
 }
 
 func (s *clientService) Delete(ctx context.Context, req *DeleteClientRequest) error {
@@ -326,17 +352,23 @@ func (s *clientService) Delete(ctx context.Context, req *DeleteClientRequest) er
 		return exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Client ID cannot be zero")
 	}
 
+	if err := s.authorizeClient(ctx, req.AuthParams, PolicyActionDelete, req.ClientID); err != nil {
+		return err
+	}
+
+	var deletedClient *entity.Client
+
 	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
 		clientTable := txRepo.Client().GetTableName()
 		ignoreTables := txRepo.ClientSupportFeature().GetTableName()
 
-		dependencyMap, err := txRepo.StoreProcedure().CheckIfRecordsAreDeletable(ctx, clientTable, []uint{req.ClientID}, ignoreTables)
-		if err != nil {
+		if err := checkDependencies(ctx, txRepo, "Client", clientTable, req.ClientID, req.Cascade, ignoreTables); err != nil {
 			return err
 		}
 
-		if count, found := dependencyMap[req.ClientID]; found && count > 0 {
-			return exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Client is not deletable due to existing dependencies")
+		deletedClient, err = txRepo.Client().FindByID(ctx, req.ClientID, false)
+		if err != nil {
+			return err
 		}
 
 		if err := txRepo.Client().Delete(ctx, req.ClientID); err != nil {
@@ -353,9 +385,108 @@ func (s *clientService) Delete(ctx context.Context, req *DeleteClientRequest) er
 		return serror.TranslateRepoError(err)
 	}
 
+	s.events.Publish(ctx, newClientEvent(entity.EventClientDeleted, deletedClient, req.AuthParams.AccessTokenClaims.UserID))
+
 	return nil
 }
 
+type BulkDeleteClientsRequest struct {
+	AuthParams *AuthParams
+	ClientIDs  []uint
+}
+
+// BulkDelete deletes the clients in one transaction, checking the dependencies of all of them in a
+// single query. Clients that are missing, denied by the access policies or still referenced are
+// reported and kept.
+func (s *clientService) BulkDelete(ctx context.Context, req *BulkDeleteClientsRequest) ([]*BulkResult, error) {
+	if req.AuthParams.AccessTokenClaims == nil {
+		return nil, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, req.AuthParams.AccessTokenClaims.UserID, "CLIENT.DELETE")
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	results, err := newBulkResults(req.ClientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []*entity.Client
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		clients, _, err := txRepo.Client().Find(ctx, &mysqlrepository.FilterClientPayload{IDs: results.pending()})
+		if err != nil {
+			return err
+		}
+
+		filter, err := s.policies.Filter(ctx, txRepo, req.AuthParams, PolicyResourceClient, PolicyActionDelete)
+		if err != nil {
+			return err
+		}
+
+		found := make(map[uint]bool, len(clients))
+
+		for _, client := range clients {
+			found[client.ID] = true
+
+			if !filter.Matches(clientPolicyAttributes(client)) {
+				results.fail(client.ID, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access"))
+			}
+		}
+
+		results.failMissing(found, "Client")
+
+		if len(results.pending()) == 0 {
+			return nil
+		}
+
+		clientTable := txRepo.Client().GetTableName()
+		ignoreTables := txRepo.ClientSupportFeature().GetTableName()
+
+		dependencies, err := txRepo.StoreProcedure().FindDependencies(ctx, clientTable, results.pending(), ignoreTables)
+		if err != nil {
+			return err
+		}
+
+		results.failDependent(dependencies, "Client")
+
+		deleted = make([]*entity.Client, 0, len(clients))
+
+		for _, client := range clients {
+			if !slices.Contains(results.pending(), client.ID) {
+				continue
+			}
+
+			if err := txRepo.Client().Delete(ctx, client.ID); err != nil {
+				return err
+			}
+
+			if err := txRepo.ClientSupportFeature().DeleteByClientID(ctx, client.ID); err != nil {
+				return err
+			}
+
+			deleted = append(deleted, client)
+		}
+
+		return nil
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	for _, client := range deleted {
+		s.events.Publish(ctx, newClientEvent(entity.EventClientDeleted, client, req.AuthParams.AccessTokenClaims.UserID))
+	}
+
+	return results.list(), nil
+}
+
 type IsDeletableClientRequest struct {
 	AuthParams *AuthParams
 	ClientID   uint
@@ -393,3 +524,58 @@ func (s *clientService) IsDeletable(ctx context.Context, req *IsDeletableClientR
 
 	return true, nil
 }
+
+type FindClientDependenciesRequest struct {
+	AuthParams *AuthParams
+	ClientID   uint
+}
+
+// FindDependencies explains IsDeletable: it lists the tables still referencing the client, leaving
+// out its support features, which are deleted along with it.
+func (s *clientService) FindDependencies(ctx context.Context, req *FindClientDependenciesRequest) ([]*entity.Dependency, error) {
+	if req.AuthParams.AccessTokenClaims == nil {
+		return nil, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, req.AuthParams.AccessTokenClaims.UserID, "CLIENT.DELETE")
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	if req.ClientID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Client ID cannot be zero")
+	}
+
+	if err := s.authorizeClient(ctx, req.AuthParams, PolicyActionRead, req.ClientID); err != nil {
+		return nil, err
+	}
+
+	clientTable := s.repo.MySQL().Client().GetTableName()
+	ignoreTables := s.repo.MySQL().ClientSupportFeature().GetTableName()
+
+	dependencies, err := s.repo.MySQL().StoreProcedure().FindDependencies(ctx, clientTable, []uint{req.ClientID}, ignoreTables)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return dependencies[req.ClientID], nil
+}
+
+// authorizeClient evaluates the access policies against the stored client, loading it only when a
+// policy targets the action.
+func (s *clientService) authorizeClient(ctx context.Context, authParams *AuthParams, action string, clientID uint) error {
+	if !s.policies.Governs(PolicyResourceClient, action) {
+		return nil
+	}
+
+	client, err := s.repo.MySQL().Client().FindByID(ctx, clientID, true)
+	if err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	return s.policies.Authorize(ctx, s.repo.MySQL(), authParams, PolicyResourceClient, action, clientPolicyAttributes(client))
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"goapptemp/config"
+	"goapptemp/constant"
+	"goapptemp/internal/adapter/pubsub"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+
+	"github.com/cockroachdb/errors"
+)
+
+var _ ConsumerService = (*consumerService)(nil)
+
+type ConsumerService interface {
+	Register(subscriber pubsub.Subscriber)
+}
+
+type consumerService struct {
+	config  *config.Config
+	logger  logger.Logger
+	webhook WebhookService
+}
+
+func NewConsumerService(config *config.Config, logger logger.Logger, webhook WebhookService) *consumerService {
+	return &consumerService{
+		config:  config,
+		logger:  logger,
+		webhook: webhook,
+	}
+}
+
+func (s *consumerService) Register(subscriber pubsub.Subscriber) {
+	subscriber.Register(constant.PubsubCommandUpdateIcon, s.handleUpdateIcon)
+}
+
+type UpdateIconPayload struct {
+	ID   uint   `json:"id"`
+	Type string `json:"type"`
+	Link string `json:"link"`
+}
+
+func (s *consumerService) handleUpdateIcon(ctx context.Context, msg *pubsub.Message) error {
+	var command CommandMessage
+	if err := json.Unmarshal(msg.Data, &command); err != nil {
+		return errors.Mark(errors.Wrap(err, "failed to decode command message"), pubsub.ErrUnrecoverable)
+	}
+
+	var payload UpdateIconPayload
+	if err := json.Unmarshal([]byte(command.Payload), &payload); err != nil {
+		return errors.Mark(errors.Wrap(err, "failed to decode update icon payload"), pubsub.ErrUnrecoverable)
+	}
+
+	if payload.ID == 0 || payload.Type == "" || payload.Link == "" {
+		return errors.Mark(errors.New("update icon payload is incomplete"), pubsub.ErrUnrecoverable)
+	}
+
+	err := s.webhook.UpdateIcon(ctx, &UpdateIconRequest{
+		ID:   payload.ID,
+		Type: payload.Type,
+		Link: payload.Link,
+	})
+
+	return markClientErrorUnrecoverable(err)
+}
+
+// markClientErrorUnrecoverable stops redelivery of messages that fail for reasons a retry cannot fix.
+func markClientErrorUnrecoverable(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	ex, ok := exception.GetException(err)
+	if !ok {
+		return err
+	}
+
+	switch ex.Type {
+	case exception.TypeBadRequest, exception.TypeValidationError, exception.TypeNotFound, exception.TypeConflict:
+		return errors.Mark(err, pubsub.ErrUnrecoverable)
+	default:
+		return err
+	}
+}
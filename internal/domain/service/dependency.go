@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"slices"
+	"strings"
+)
+
+// dependencyError refuses the deletion of a record that is still referenced, listing what uses it
+// under "dependencies" (for example "used by 3 clients") so clients can explain the refusal.
+func dependencyError(resource string, dependencies []*entity.Dependency) error {
+	return exception.NewWithErrors(exception.TypeBadRequest, exception.CodeBadRequest,
+		fmt.Sprintf("%s is not deletable due to existing dependencies", resource),
+		exception.FieldErrors{"dependencies": describeDependencies(dependencies)})
+}
+
+// checkDependencies refuses deleting the record while other rows reference it. In cascade mode only
+// references from tables without soft delete block the deletion; the others are soft-deleted, along
+// with whatever references them in turn.
+func checkDependencies(ctx context.Context, repo mysqlrepository.MySQLRepository, resource, table string, id uint, cascade bool, ignoreTables ...string) error {
+	dependencies, err := repo.StoreProcedure().FindDependencies(ctx, table, []uint{id}, ignoreTables...)
+	if err != nil {
+		return err
+	}
+
+	blocking := dependencies[id]
+	if cascade {
+		blocking = slices.DeleteFunc(slices.Clone(blocking), func(dependency *entity.Dependency) bool {
+			return dependency.SoftDeletable
+		})
+	}
+
+	if len(blocking) > 0 {
+		return dependencyError(resource, blocking)
+	}
+
+	if cascade && len(dependencies[id]) > 0 {
+		if _, err := repo.StoreProcedure().CascadeSoftDelete(ctx, table, []uint{id}, ignoreTables...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// describeDependencies merges the counts of tables referencing the record through several columns
+// and keeps the order the repository reported them in.
+func describeDependencies(dependencies []*entity.Dependency) []string {
+	tables := make([]string, 0, len(dependencies))
+	counts := make(map[string]int, len(dependencies))
+
+	for _, dependency := range dependencies {
+		if _, ok := counts[dependency.Table]; !ok {
+			tables = append(tables, dependency.Table)
+		}
+
+		counts[dependency.Table] += dependency.Count
+	}
+
+	descriptions := make([]string, 0, len(tables))
+
+	for _, table := range tables {
+		name := table
+		if counts[table] == 1 {
+			name = singularTable(table)
+		}
+
+		descriptions = append(descriptions, fmt.Sprintf("used by %d %s", counts[table], name))
+	}
+
+	return descriptions
+}
+
+func singularTable(table string) string {
+	if strings.HasSuffix(table, "ies") {
+		return strings.TrimSuffix(table, "ies") + "y"
+	}
+
+	return strings.TrimSuffix(table, "s")
+}
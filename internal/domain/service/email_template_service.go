@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"goapptemp/config"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"goapptemp/pkg/mailtemplate"
+	"maps"
+
+	"github.com/cockroachdb/errors"
+)
+
+var _ EmailTemplateService = (*emailTemplateService)(nil)
+
+type EmailTemplateService interface {
+	Find(ctx context.Context, req *FindEmailTemplatesRequest) ([]*mailtemplate.TemplateInfo, error)
+	Preview(ctx context.Context, req *PreviewEmailTemplateRequest) (*mailtemplate.Rendered, error)
+}
+
+type emailTemplateService struct {
+	config    *config.Config
+	logger    logger.Logger
+	auth      AuthService
+	templates mailtemplate.Registry
+}
+
+func NewEmailTemplateService(config *config.Config, logger logger.Logger, auth AuthService, templates mailtemplate.Registry) *emailTemplateService {
+	return &emailTemplateService{
+		config:    config,
+		logger:    logger,
+		auth:      auth,
+		templates: templates,
+	}
+}
+
+type FindEmailTemplatesRequest struct {
+	AuthParams *AuthParams
+}
+
+func (s *emailTemplateService) Find(ctx context.Context, req *FindEmailTemplatesRequest) ([]*mailtemplate.TemplateInfo, error) {
+	if err := s.authorize(ctx, req.AuthParams); err != nil {
+		return nil, err
+	}
+
+	return s.templates.Templates(), nil
+}
+
+type PreviewEmailTemplateRequest struct {
+	AuthParams *AuthParams
+	Name       string
+	Locale     string
+	// Data overrides individual keys of the template's sample data.
+	Data map[string]any
+}
+
+func (s *emailTemplateService) Preview(ctx context.Context, req *PreviewEmailTemplateRequest) (*mailtemplate.Rendered, error) {
+	if err := s.authorize(ctx, req.AuthParams); err != nil {
+		return nil, err
+	}
+
+	sample, err := s.templates.Sample(req.Name)
+	if err != nil {
+		return nil, translateTemplateError(err)
+	}
+
+	data := make(map[string]any, len(sample)+len(req.Data))
+	maps.Copy(data, sample)
+	maps.Copy(data, req.Data)
+
+	rendered, err := s.templates.Render(req.Name, req.Locale, data)
+	if err != nil {
+		return nil, translateTemplateError(err)
+	}
+
+	return rendered, nil
+}
+
+func (s *emailTemplateService) authorize(ctx context.Context, authParams *AuthParams) error {
+	if authParams == nil || authParams.AccessTokenClaims == nil {
+		return exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, authParams.AccessTokenClaims.UserID, "EMAIL_TEMPLATE.READ")
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	return nil
+}
+
+func translateTemplateError(err error) error {
+	if errors.Is(err, mailtemplate.ErrTemplateNotFound) {
+		return exception.Wrap(err, exception.TypeNotFound, exception.CodeNotFound, "Email template not found")
+	}
+
+	// Anything else is a template referencing data the sample does not provide.
+	return exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Failed to render email template: "+err.Error())
+}
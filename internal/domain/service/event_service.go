@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"goapptemp/config"
+	"goapptemp/internal/adapter/pubsub"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/pkg/logger"
+	"goapptemp/pkg/taskqueue"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var _ EventService = (*eventService)(nil)
+
+type EventService interface {
+	Publish(ctx context.Context, event *entity.DomainEvent)
+}
+
+type eventService struct {
+	config     *config.Config
+	logger     logger.Logger
+	publishers map[string]pubsub.Publisher
+	webhooks   WebhookSubscriptionService
+	tasks      taskqueue.Queue
+}
+
+// NewEventService takes one publisher per topic ID; events whose topic has no publisher are only
+// handed to tenant webhooks.
+func NewEventService(
+	config *config.Config,
+	logger logger.Logger,
+	publishers map[string]pubsub.Publisher,
+	webhooks WebhookSubscriptionService,
+	tasks taskqueue.Queue,
+) *eventService {
+	return &eventService{
+		config:     config,
+		logger:     logger,
+		publishers: publishers,
+		webhooks:   webhooks,
+		tasks:      tasks,
+	}
+}
+
+type EventEnvelope struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Version        int       `json:"version"`
+	OccurredAt     time.Time `json:"occurred_at"`
+	TenantID       uint      `json:"tenant_id"`
+	ActorID        uint      `json:"actor_id"`
+	ImpersonatorID uint      `json:"impersonator_id,omitempty"`
+	Payload        any       `json:"payload"`
+}
+
+// Publish is called after the originating transaction has committed, so failures are logged instead of
+// returned. Webhook dispatch and the topic publish run as tasks so the request does not wait on them.
+func (s *eventService) Publish(ctx context.Context, event *entity.DomainEvent) {
+	if event == nil {
+		return
+	}
+
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+
+	if event.Version == 0 {
+		event.Version = entity.DomainEventVersion
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	if event.ImpersonatorID == 0 {
+		event.ImpersonatorID = ImpersonatorFromContext(ctx)
+	}
+
+	data, err := json.Marshal(EventEnvelope{
+		ID:             event.ID,
+		Type:           string(event.Type),
+		Version:        event.Version,
+		OccurredAt:     event.OccurredAt,
+		TenantID:       event.TenantID,
+		ActorID:        event.ActorID,
+		ImpersonatorID: event.ImpersonatorID,
+		Payload:        event.Payload,
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Msgf("Failed to encode domain event %s", event.Type)
+		return
+	}
+
+	payload := &eventTask{
+		ID:       event.ID,
+		Type:     event.Type,
+		Version:  event.Version,
+		TenantID: event.TenantID,
+		Envelope: data,
+	}
+
+	if event.TenantID != 0 {
+		s.enqueue(ctx, TaskDispatchEventWebhooks, payload)
+	}
+
+	if publisher, ok := s.publishers[EventTopic(s.config, event.Type)]; ok && publisher != nil {
+		s.enqueue(ctx, TaskPublishEvent, payload)
+	}
+}
+
+func (s *eventService) enqueue(ctx context.Context, taskType string, payload *eventTask) {
+	if err := s.tasks.Enqueue(context.WithoutCancel(ctx), taskType, payload); err != nil {
+		s.logger.Error().Err(err).Msgf("Failed to enqueue %s task for domain event %s (%s)", taskType, payload.Type, payload.ID)
+	}
+}
+
+// EventTopic resolves the topic for an event type by the longest matching route prefix,
+// falling back to the default event topic.
+func EventTopic(config *config.Config, eventType entity.DomainEventType) string {
+	var (
+		topicID   = config.Pubsub.EventTopicID
+		matchSize = -1
+	)
+
+	for prefix, routeTopicID := range config.Pubsub.EventTopicRoutes {
+		if strings.HasPrefix(string(eventType), prefix) && len(prefix) > matchSize {
+			topicID = routeTopicID
+			matchSize = len(prefix)
+		}
+	}
+
+	return topicID
+}
+
+func newClientEvent(eventType entity.DomainEventType, client *entity.Client, actorID uint) *entity.DomainEvent {
+	return &entity.DomainEvent{
+		Type:     eventType,
+		TenantID: client.CompanyID,
+		ActorID:  actorID,
+		Payload: entity.ClientEventPayload{
+			ID:         client.ID,
+			CompanyID:  client.CompanyID,
+			Code:       client.Code,
+			Name:       client.Name,
+			DistrictID: client.DistrictID,
+		},
+	}
+}
+
+func newUserEvent(eventType entity.DomainEventType, user *entity.User, actorID uint) *entity.DomainEvent {
+	return &entity.DomainEvent{
+		Type:     eventType,
+		TenantID: user.CompanyID,
+		ActorID:  actorID,
+		Payload: entity.UserEventPayload{
+			ID:        user.ID,
+			CompanyID: user.CompanyID,
+			Username:  user.Username,
+			Email:     user.Email,
+			Fullname:  user.Fullname,
+			RoleIDs:   user.RoleIDs,
+		},
+	}
+}
+
+func newRoleEvent(eventType entity.DomainEventType, role *entity.Role, actorID uint) *entity.DomainEvent {
+	var tenantID uint
+	if role.CompanyID != nil {
+		tenantID = *role.CompanyID
+	}
+
+	return &entity.DomainEvent{
+		Type:     eventType,
+		TenantID: tenantID,
+		ActorID:  actorID,
+		Payload: entity.RoleEventPayload{
+			ID:            role.ID,
+			Code:          role.Code,
+			Name:          role.Name,
+			SuperAdmin:    role.SuperAdmin,
+			ParentID:      role.ParentID,
+			CompanyID:     role.CompanyID,
+			PermissionIDs: role.PermissionIDs,
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/pkg/taskqueue"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	TaskDispatchEventWebhooks = "event.dispatch_webhooks"
+	TaskPublishEvent          = "event.publish"
+)
+
+// eventTask carries an encoded envelope; the other fields are what routing and webhook dispatch need
+// without decoding it again.
+type eventTask struct {
+	ID       string                 `json:"id"`
+	Type     entity.DomainEventType `json:"type"`
+	Version  int                    `json:"version"`
+	TenantID uint                   `json:"tenant_id,omitempty"`
+	Envelope json.RawMessage        `json:"envelope"`
+}
+
+func (s *eventService) registerTasks(queue taskqueue.Queue) error {
+	handlers := map[string]taskqueue.Handler{
+		TaskDispatchEventWebhooks: s.dispatchWebhooks,
+		TaskPublishEvent:          s.publishEvent,
+	}
+
+	for taskType, handler := range handlers {
+		if err := queue.Register(taskType, handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *eventService) dispatchWebhooks(ctx context.Context, task *taskqueue.Task) error {
+	var payload eventTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	event := &entity.DomainEvent{
+		ID:       payload.ID,
+		Type:     payload.Type,
+		Version:  payload.Version,
+		TenantID: payload.TenantID,
+	}
+
+	return s.webhooks.Dispatch(ctx, event, payload.Envelope)
+}
+
+// publishEvent sends the envelope to the event's topic. Subscribers may see an event twice when a
+// retry follows a publish whose acknowledgement was lost, and deduplicate on the event_id attribute.
+func (s *eventService) publishEvent(ctx context.Context, task *taskqueue.Task) error {
+	var payload eventTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	publisher, ok := s.publishers[EventTopic(s.config, payload.Type)]
+	if !ok || publisher == nil {
+		return nil
+	}
+
+	// Events carry no command attribute, so command subscribers sharing the topic can tell them apart.
+	attributes := map[string]string{
+		"event_id":      payload.ID,
+		"event_type":    string(payload.Type),
+		"event_version": strconv.Itoa(payload.Version),
+	}
+
+	if _, err := publisher.Publish(ctx, payload.Envelope, attributes); err != nil {
+		return errors.Wrapf(err, "failed to publish domain event %s (%s)", payload.Type, payload.ID)
+	}
+
+	return nil
+}
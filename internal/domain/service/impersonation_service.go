@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"goapptemp/config"
+	"goapptemp/constant"
+	"goapptemp/internal/adapter/repository"
+	"goapptemp/internal/domain/entity"
+	serror "goapptemp/internal/domain/service/error"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/internal/shared/token"
+	"goapptemp/pkg/logger"
+	"time"
+)
+
+const defaultImpersonationTokenDuration = 15 // in minutes
+
+var _ ImpersonationService = (*impersonationService)(nil)
+
+type ImpersonationService interface {
+	Impersonate(ctx context.Context, req *ImpersonateRequest) (*entity.User, error)
+}
+
+// impersonationService lets super admins see the app as another user. Sessions are short-lived
+// access tokens without a refresh token, and every one of them is published as an audit event.
+type impersonationService struct {
+	config *config.Config
+	token  token.Token
+	repo   repository.Repository
+	logger logger.Logger
+	events EventService
+}
+
+func NewImpersonationService(
+	config *config.Config,
+	token token.Token,
+	repo repository.Repository,
+	logger logger.Logger,
+	events EventService,
+) *impersonationService {
+	return &impersonationService{
+		config: config,
+		token:  token,
+		repo:   repo,
+		logger: logger,
+		events: events,
+	}
+}
+
+// WithImpersonator marks ctx as a request made by impersonatorID on behalf of the token's user, so
+// domain events raised while handling it name the admin behind the change.
+func WithImpersonator(ctx context.Context, impersonatorID uint) context.Context {
+	return context.WithValue(ctx, constant.CtxKeyImpersonator, impersonatorID)
+}
+
+func ImpersonatorFromContext(ctx context.Context) uint {
+	impersonatorID, _ := ctx.Value(constant.CtxKeyImpersonator).(uint)
+
+	return impersonatorID
+}
+
+type ImpersonateRequest struct {
+	AuthParams *AuthParams
+	UserID     uint
+	Reason     string
+}
+
+// Impersonate issues an access token for the target user carrying the admin in its act claim.
+// Only super admins signed in with their own bearer token may impersonate, and super admins
+// cannot be impersonated themselves.
+func (s *impersonationService) Impersonate(ctx context.Context, req *ImpersonateRequest) (*entity.User, error) {
+	if req.AuthParams == nil || req.AuthParams.AccessTokenClaims == nil {
+		return nil, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	if req.AuthParams.AccessTokenClaims.Impersonated() {
+		return nil, exception.ErrImpersonationDenied
+	}
+
+	if req.AuthParams.APIKey != nil {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "API keys cannot impersonate users")
+	}
+
+	admin, err := s.repo.MySQL().User().FindByID(ctx, req.AuthParams.AccessTokenClaims.UserID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if !admin.IsSuperAdmin() {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	if req.UserID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "User ID cannot be zero")
+	}
+
+	if req.UserID == admin.ID {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "User cannot impersonate themselves")
+	}
+
+	user, err := s.repo.MySQL().User().FindByID(ctx, req.UserID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if user.IsSuperAdmin() {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Super admins cannot be impersonated")
+	}
+
+	if user.Status != entity.UserStatusActive {
+		return nil, exception.ErrUserInactive
+	}
+
+	duration := time.Duration(positiveOr(s.config.Token.ImpersonationTokenDuration, defaultImpersonationTokenDuration)) * time.Minute
+
+	accessToken, expiresAt, err := s.token.GenerateImpersonationToken(user.ID, admin.ID, duration)
+	if err != nil {
+		return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to generate impersonation token")
+	}
+
+	s.logger.Info().
+		Field("impersonator_id", admin.ID).
+		Field("user_id", user.ID).
+		Field("reason", req.Reason).
+		Msgf("User %d started impersonating user %d", admin.ID, user.ID)
+
+	s.events.Publish(ctx, &entity.DomainEvent{
+		Type:     entity.EventUserImpersonated,
+		TenantID: user.CompanyID,
+		ActorID:  admin.ID,
+		Payload: entity.ImpersonationEventPayload{
+			UserID:         user.ID,
+			ImpersonatorID: admin.ID,
+			Reason:         req.Reason,
+			ExpiresAt:      expiresAt,
+		},
+	})
+
+	admin.Password = ""
+	user.Password = ""
+	user.Token = &entity.Token{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: expiresAt,
+		TokenType:            constant.TokenType,
+	}
+	user.Impersonation = &entity.Impersonation{
+		ImpersonatorID: admin.ID,
+		Impersonator:   admin,
+		ExpiresAt:      expiresAt,
+	}
+
+	return user, nil
+}
@@ -0,0 +1,649 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	serror "goapptemp/internal/domain/service/error"
+	"goapptemp/internal/shared/exception"
+	"mime/multipart"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+	excelize "github.com/xuri/excelize/v2"
+)
+
+const (
+	userImportSheetName     = "Users"
+	userImportRoleSheetName = "Roles"
+	userImportMaxRows       = 300
+)
+
+var userImportColumns = []string{"Fullname", "Username", "Email", "Role"}
+
+type ValidatableFullname struct {
+	Value   string `json:"value"             validate:"required,min=3,max=100"`
+	Message string `json:"message,omitempty"`
+}
+
+type ValidatableUsername struct {
+	Value   string `json:"value"             validate:"required,min=3,max=100,username_chars_allowed"`
+	Message string `json:"message,omitempty"`
+}
+
+type ValidatableEmail struct {
+	Value   string `json:"value"             validate:"required,email,min=3,max=100"`
+	Message string `json:"message,omitempty"`
+}
+
+type ValidatableRoleCode struct {
+	Value   string `json:"value"             validate:"required"`
+	Message string `json:"message,omitempty"`
+}
+
+type UserImportPreview struct {
+	Row      int                 `json:"row"`
+	Fullname ValidatableFullname `json:"fullname" validate:"required"`
+	Username ValidatableUsername `json:"username" validate:"required"`
+	Email    ValidatableEmail    `json:"email"    validate:"required"`
+	Role     ValidatableRoleCode `json:"role"     validate:"required"`
+	roleID   uint
+}
+
+func (p *UserImportPreview) valid() bool {
+	return p.Fullname.Message == "" && p.Username.Message == "" && p.Email.Message == "" && p.Role.Message == ""
+}
+
+type TemplateImportUserRequest struct {
+	AuthParams *AuthParams
+}
+
+// TemplateImport builds the user import workbook. The role column offers the roles the importer's
+// company can assign, read from a hidden sheet so the list is not limited by Excel's inline list size.
+func (s *invitationService) TemplateImport(ctx context.Context, req *TemplateImportUserRequest) (*FileServiceData, error) {
+	if err := s.authorize(ctx, req.AuthParams, "USER.CREATE"); err != nil {
+		return nil, err
+	}
+
+	_, roles, err := s.importScope(ctx, req.AuthParams)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		headerRow         = 1
+		dataStartRow      = 2
+		totalRowsToFormat = headerRow + userImportMaxRows
+	)
+
+	headers := []struct {
+		Name         string
+		ColumnLetter string
+		CommentText  string
+		Width        float64
+		MinLength    int
+	}{
+		{Name: "Fullname", ColumnLetter: "A", CommentText: "Required. 3 to 100 characters.", Width: 40, MinLength: 3},
+		{Name: "Username", ColumnLetter: "B", CommentText: "Required. Letters, numbers, dot (.), underscore (_) and dash (-). 3 to 100 characters. Unique in the company.", Width: 30, MinLength: 3},
+		{Name: "Email", ColumnLetter: "C", CommentText: "Required. A valid email address, unique in the company. The invitation is sent here.", Width: 40, MinLength: 3},
+		{Name: "Role", ColumnLetter: "D", CommentText: "Required. Select a role code from the list.", Width: 30},
+	}
+
+	f := excelize.NewFile()
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			s.logger.Error().Err(err).Msg("Failed to close excel file")
+		}
+	}()
+
+	mainSheetIndex, err := f.NewSheet(userImportSheetName)
+	if err != nil {
+		return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, "Failed to create main sheet")
+	}
+
+	f.SetActiveSheet(mainSheetIndex)
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"4F81BD"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+		Border: []excelize.Border{
+			{Type: "left", Color: "D3D3D3", Style: 1},
+			{Type: "right", Color: "D3D3D3", Style: 1},
+			{Type: "top", Color: "D3D3D3", Style: 1},
+			{Type: "bottom", Color: "D3D3D3", Style: 1},
+		},
+		Protection: &excelize.Protection{Locked: true},
+	})
+	if err != nil {
+		return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, "Failed to create header style")
+	}
+
+	for i := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, headerRow)
+		if err = f.SetCellValue(userImportSheetName, cell, headers[i].Name); err != nil {
+			return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, fmt.Sprintf("Failed to set cell value for %s: %v", cell, err))
+		}
+
+		if err = f.SetCellStyle(userImportSheetName, cell, cell, headerStyle); err != nil {
+			return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, fmt.Sprintf("Failed to set cell style for %s: %v", cell, err))
+		}
+
+		if err = f.SetColWidth(userImportSheetName, headers[i].ColumnLetter, headers[i].ColumnLetter, headers[i].Width); err != nil {
+			return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, fmt.Sprintf("Failed to set column width for %s: %v", headers[i].ColumnLetter, err))
+		}
+
+		comment := excelize.Comment{
+			Cell:   cell,
+			Author: "Template Guide:",
+			Paragraph: []excelize.RichTextRun{
+				{Text: "Guidance: ", Font: &excelize.Font{Bold: true, Color: "000000"}},
+				{Text: headers[i].CommentText, Font: &excelize.Font{Color: "000000"}},
+			},
+			Height: 70,
+			Width:  300,
+		}
+		if err = f.AddComment(userImportSheetName, comment); err != nil {
+			return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, fmt.Sprintf("Failed to add comment for %s: %v", cell, err))
+		}
+
+		if headers[i].MinLength == 0 {
+			continue
+		}
+
+		dv := excelize.NewDataValidation(true)
+		dv.Sqref = fmt.Sprintf("%s%d:%s%d", headers[i].ColumnLetter, dataStartRow, headers[i].ColumnLetter, totalRowsToFormat)
+
+		if err = dv.SetRange(float64(headers[i].MinLength), float64(100), excelize.DataValidationTypeTextLength, excelize.DataValidationOperatorBetween); err != nil {
+			return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, fmt.Sprintf("Failed to set range for %s data validation: %v", headers[i].Name, err))
+		}
+
+		dv.SetError(excelize.DataValidationErrorStyleStop, "Invalid "+headers[i].Name, headers[i].CommentText)
+		dv.SetInput(headers[i].Name+" Input", headers[i].CommentText)
+
+		if err = f.AddDataValidation(userImportSheetName, dv); err != nil {
+			return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, fmt.Sprintf("Failed to add %s data validation", headers[i].Name))
+		}
+	}
+
+	if err = f.SetRowHeight(userImportSheetName, headerRow, 30); err != nil {
+		return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, fmt.Sprintf("Failed to set row height for row %d: %v", headerRow, err))
+	}
+
+	if len(roles) > 0 {
+		if _, err = f.NewSheet(userImportRoleSheetName); err != nil {
+			return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, "Failed to create role sheet")
+		}
+
+		for i, role := range roles {
+			if err = f.SetSheetRow(userImportRoleSheetName, fmt.Sprintf("A%d", i+1), &[]any{role.Code, role.Name}); err != nil {
+				return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, fmt.Sprintf("Failed to write role %s: %v", role.Code, err))
+			}
+		}
+
+		if err = f.SetSheetVisible(userImportRoleSheetName, false); err != nil {
+			return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, "Failed to hide role sheet")
+		}
+
+		dvRole := excelize.NewDataValidation(true)
+		dvRole.Sqref = fmt.Sprintf("D%d:D%d", dataStartRow, totalRowsToFormat)
+		dvRole.SetSqrefDropList(fmt.Sprintf("%s!$A$1:$A$%d", userImportRoleSheetName, len(roles)))
+		dvRole.SetError(excelize.DataValidationErrorStyleStop, "Invalid Role", "Please select a role from the list.")
+		dvRole.SetInput("Role", "Select the role the user is invited with.")
+
+		if err = f.AddDataValidation(userImportSheetName, dvRole); err != nil {
+			return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, "Failed to add Role data validation")
+		}
+	}
+
+	unlockedStyle, err := f.NewStyle(&excelize.Style{
+		Protection: &excelize.Protection{Locked: false},
+		Alignment:  &excelize.Alignment{Vertical: "center"},
+	})
+	if err != nil {
+		return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, "Failed to create unlocked style")
+	}
+
+	lastCell, _ := excelize.CoordinatesToCellName(len(headers), totalRowsToFormat)
+	if err = f.SetCellStyle(userImportSheetName, fmt.Sprintf("A%d", dataStartRow), lastCell, unlockedStyle); err != nil {
+		return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, fmt.Sprintf("Failed to set data cell style: %v", err))
+	}
+
+	if err := f.ProtectSheet(userImportSheetName, &excelize.SheetProtectionOptions{SelectLockedCells: true, SelectUnlockedCells: true}); err != nil {
+		return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, "Failed to protect main sheet")
+	}
+
+	if err := createErrorGuideSheet(f); err != nil {
+		return nil, err
+	}
+
+	if sheetIdx, _ := f.GetSheetIndex("Sheet1"); sheetIdx != -1 {
+		if err := f.DeleteSheet("Sheet1"); err != nil {
+			return nil, exception.New(exception.TypeInternalError, exception.CodeInternalError, "Failed to delete default sheet")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write excel to buffer: %w", err)
+	}
+
+	contentBytes := buf.Bytes()
+
+	return &FileServiceData{
+		Filename: "user_import_template.xlsx",
+		MIMEType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Content:  bytes.NewReader(contentBytes),
+		Size:     int64(len(contentBytes)),
+	}, nil
+}
+
+type ImportPreviewUserRequest struct {
+	AuthParams *AuthParams
+	File       *multipart.FileHeader
+}
+
+// ImportPreview validates the file without creating anything. Like the help service import it
+// returns only the rows with errors when there are any, otherwise every row.
+func (s *invitationService) ImportPreview(ctx context.Context, req *ImportPreviewUserRequest) ([]*UserImportPreview, error) {
+	if err := s.authorize(ctx, req.AuthParams, "USER.CREATE"); err != nil {
+		return nil, err
+	}
+
+	previews, _, err := s.readImport(ctx, req.AuthParams, req.File)
+	if err != nil {
+		return nil, err
+	}
+
+	errPreviews := make([]*UserImportPreview, 0, len(previews))
+
+	for _, preview := range previews {
+		if !preview.valid() {
+			errPreviews = append(errPreviews, preview)
+		}
+	}
+
+	if len(errPreviews) > 0 {
+		return errPreviews, nil
+	}
+
+	return previews, nil
+}
+
+type ImportUsersRequest struct {
+	AuthParams *AuthParams
+	File       *multipart.FileHeader
+}
+
+// ImportedUser is a user created by Import with the outcome of sending its invitation.
+type ImportedUser struct {
+	User            *entity.User
+	InvitationSent  bool
+	InvitationError string
+}
+
+// Import re-validates the file and, when every row is valid, creates all users as pending with
+// their role in a single transaction, so a failing row leaves nothing behind. Invitations are
+// sent once the transaction has committed and reported per user; a user whose invitation could
+// not be queued stays pending and can be sent a new link with Resend.
+func (s *invitationService) Import(ctx context.Context, req *ImportUsersRequest) ([]*ImportedUser, error) {
+	if err := s.authorize(ctx, req.AuthParams, "USER.CREATE"); err != nil {
+		return nil, err
+	}
+
+	previews, companyID, err := s.readImport(ctx, req.AuthParams, req.File)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(previews) == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeValidationFailed, "At least one user is required")
+	}
+
+	fieldErrors := make(exception.FieldErrors)
+
+	for _, preview := range previews {
+		for field, message := range map[string]string{
+			"fullname": preview.Fullname.Message,
+			"username": preview.Username.Message,
+			"email":    preview.Email.Message,
+			"role":     preview.Role.Message,
+		} {
+			if message != "" {
+				key := fmt.Sprintf("rows[%d].%s", preview.Row, field)
+				fieldErrors[key] = append(fieldErrors[key], message)
+			}
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, exception.NewWithErrors(exception.TypeValidationError, exception.CodeValidationFailed, "Import file contains invalid rows", fieldErrors)
+	}
+
+	users := make([]*entity.User, 0, len(previews))
+
+	for _, preview := range previews {
+		password, err := randomToken()
+		if err != nil {
+			return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to generate user password")
+		}
+
+		user := &entity.User{
+			CompanyID: companyID,
+			Fullname:  preview.Fullname.Value,
+			Username:  preview.Username.Value,
+			Email:     preview.Email.Value,
+			Status:    entity.UserStatusPending,
+			RoleIDs:   []uint{preview.roleID},
+		}
+
+		if err := user.SetPassword(password); err != nil {
+			return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to hash user password during import")
+		}
+
+		users = append(users, user)
+	}
+
+	created := make([]*entity.User, 0, len(users))
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		created = created[:0]
+
+		for _, user := range users {
+			createdUser, err := txRepo.User().Create(ctx, user)
+			if err != nil {
+				return err
+			}
+
+			if _, err := txRepo.User().AttachRoles(ctx, createdUser.ID, user.RoleIDs); err != nil {
+				return err
+			}
+
+			created = append(created, createdUser)
+		}
+
+		return nil
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	ids := make([]uint, 0, len(created))
+	for _, user := range created {
+		ids = append(ids, user.ID)
+	}
+
+	imported, _, err := s.repo.MySQL().User().Find(ctx, &mysqlrepository.FilterUserPayload{IDs: ids})
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	actorID := req.AuthParams.AccessTokenClaims.UserID
+	res := make([]*ImportedUser, 0, len(imported))
+
+	for _, user := range imported {
+		result := &ImportedUser{User: user, InvitationSent: true}
+
+		if err := s.send(ctx, user); err != nil {
+			s.logger.Warn().Err(err).Msgf("Failed to send invitation to imported user %d", user.ID)
+
+			result.InvitationSent = false
+			result.InvitationError = "Invitation could not be sent, resend it to try again"
+		}
+
+		s.events.Publish(ctx, newUserEvent(entity.EventUserInvited, user, actorID))
+
+		user.Password = ""
+		res = append(res, result)
+	}
+
+	return res, nil
+}
+
+// importScope returns the company imported users join, the importer's own, and the roles they
+// can be given: the company's roles and the global ones, never super admin roles. A company role
+// shadows a global role with the same code.
+func (s *invitationService) importScope(ctx context.Context, authParams *AuthParams) (uint, []*entity.Role, error) {
+	importer, err := s.repo.MySQL().User().FindByID(ctx, authParams.AccessTokenClaims.UserID)
+	if err != nil {
+		return 0, nil, serror.TranslateRepoError(err)
+	}
+
+	global, superAdmin := true, false
+
+	found, _, err := s.repo.MySQL().Role().Find(ctx, &mysqlrepository.FilterRolePayload{
+		CompanyIDs: []uint{importer.CompanyID},
+		Global:     &global,
+		SuperAdmin: &superAdmin,
+	})
+	if err != nil {
+		return 0, nil, serror.TranslateRepoError(err)
+	}
+
+	byCode := make(map[string]*entity.Role, len(found))
+	roles := make([]*entity.Role, 0, len(found))
+
+	for _, role := range found {
+		code := strings.ToUpper(role.Code)
+		if existing, ok := byCode[code]; ok && !existing.Global() {
+			continue
+		}
+
+		byCode[code] = role
+	}
+
+	for _, role := range found {
+		if byCode[strings.ToUpper(role.Code)] == role {
+			roles = append(roles, role)
+		}
+	}
+
+	return importer.CompanyID, roles, nil
+}
+
+// readImport parses and validates the uploaded file against the rules of a single invite, the
+// other rows of the file and the users already in the importer's company.
+func (s *invitationService) readImport(ctx context.Context, authParams *AuthParams, file *multipart.FileHeader) ([]*UserImportPreview, uint, error) {
+	if file == nil {
+		return nil, 0, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Missing data. Please fill in the required field.")
+	}
+
+	companyID, roles, err := s.importScope(ctx, authParams)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, 0, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to open uploaded file")
+	}
+
+	defer func() {
+		if err := src.Close(); err != nil {
+			s.logger.Error().Err(err).Msg("Failed to close uploaded file")
+		}
+	}()
+
+	f, err := excelize.OpenReader(src)
+	if err != nil {
+		return nil, 0, exception.Wrap(err, exception.TypeBadRequest, exception.CodeBadRequest, "Invalid data format. Please check your entry.")
+	}
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			s.logger.Error().Err(err).Msg("Failed to close excel file")
+		}
+	}()
+
+	sheetName := userImportSheetName
+	if idx, _ := f.GetSheetIndex(sheetName); idx == -1 {
+		sheetList := f.GetSheetList()
+		if len(sheetList) == 0 {
+			return nil, 0, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Invalid data format. Please check your entry.")
+		}
+
+		sheetName = sheetList[0]
+	}
+
+	rows, err := f.GetRows(sheetName)
+	if err != nil {
+		return nil, 0, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to read rows from excel sheet: "+sheetName)
+	}
+
+	if len(rows) < 2 {
+		return make([]*UserImportPreview, 0), companyID, nil
+	}
+
+	colMap := make(map[string]int)
+	for i, colName := range rows[0] {
+		colMap[strings.TrimSpace(colName)] = i
+	}
+
+	var missingCols []string
+
+	for _, col := range userImportColumns {
+		if _, exists := colMap[col]; !exists {
+			missingCols = append(missingCols, col)
+		}
+	}
+
+	if len(missingCols) > 0 {
+		return nil, 0, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Missing column(s) in header: "+strings.Join(missingCols, ", ")+". Please fill in the required field.")
+	}
+
+	cell := func(row []string, col string) string {
+		if idx := colMap[col]; len(row) > idx {
+			return strings.TrimSpace(row[idx])
+		}
+
+		return ""
+	}
+
+	previews := make([]*UserImportPreview, 0, len(rows)-1)
+	usernames := make([]string, 0, len(rows)-1)
+	emails := make([]string, 0, len(rows)-1)
+
+	for i, row := range rows[1:] {
+		preview := &UserImportPreview{Row: i + 2}
+		preview.Fullname.Value = cell(row, "Fullname")
+		preview.Username.Value = cell(row, "Username")
+		preview.Email.Value = cell(row, "Email")
+		preview.Role.Value = cell(row, "Role")
+
+		if preview.Fullname.Value == "" && preview.Username.Value == "" && preview.Email.Value == "" && preview.Role.Value == "" {
+			continue
+		}
+
+		previews = append(previews, preview)
+
+		if preview.Username.Value != "" {
+			usernames = append(usernames, preview.Username.Value)
+		}
+
+		if preview.Email.Value != "" {
+			emails = append(emails, preview.Email.Value)
+		}
+	}
+
+	if len(previews) > userImportMaxRows {
+		return nil, 0, exception.Newf(exception.TypeBadRequest, exception.CodeValidationFailed, "Maximum %d users can be imported at once", userImportMaxRows)
+	}
+
+	existingUsernames, existingEmails, err := s.repo.MySQL().User().FindExistingUsernamesAndEmails(ctx, companyID, usernames, emails)
+	if err != nil {
+		return nil, 0, serror.TranslateRepoError(err)
+	}
+
+	roleIDs := make(map[string]uint, len(roles))
+	for _, role := range roles {
+		roleIDs[strings.ToUpper(role.Code)] = role.ID
+	}
+
+	seenUsernames := make(map[string]int)
+	seenEmails := make(map[string]int)
+
+	for _, preview := range previews {
+		s.validateImportRow(preview)
+
+		if preview.Username.Message == "" {
+			lowerUsername := strings.ToLower(preview.Username.Value)
+			if _, seen := seenUsernames[lowerUsername]; seen {
+				preview.Username.Message = "Duplicate entry found in file. Each entry in the file must be unique."
+			} else {
+				seenUsernames[lowerUsername] = preview.Row
+
+				if _, exists := existingUsernames[lowerUsername]; exists {
+					preview.Username.Message = "Data already exists. Please enter new information."
+				}
+			}
+		}
+
+		if preview.Email.Message == "" {
+			lowerEmail := strings.ToLower(preview.Email.Value)
+			if _, seen := seenEmails[lowerEmail]; seen {
+				preview.Email.Message = "Duplicate entry found in file. Each entry in the file must be unique."
+			} else {
+				seenEmails[lowerEmail] = preview.Row
+
+				if _, exists := existingEmails[lowerEmail]; exists {
+					preview.Email.Message = "Data already exists. Please enter new information."
+				}
+			}
+		}
+
+		if preview.Role.Message == "" {
+			roleID, ok := roleIDs[strings.ToUpper(preview.Role.Value)]
+			if !ok {
+				preview.Role.Message = "Value not found. Please select a role from the list."
+			}
+
+			preview.roleID = roleID
+		}
+	}
+
+	return previews, companyID, nil
+}
+
+func (s *invitationService) validateImportRow(preview *UserImportPreview) {
+	err := s.validate.Struct(preview)
+	if err == nil {
+		return
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return
+	}
+
+	for _, fe := range validationErrors {
+		message := "Invalid data format. Please check your entry."
+
+		switch {
+		case fe.Tag() == "required" && fe.StructNamespace() == "UserImportPreview.Role.Value":
+			message = "Missing data. Please select in the required field."
+		case fe.Tag() == "required":
+			message = "Missing data. Please fill in the required field."
+		}
+
+		var target *string
+
+		switch fe.StructNamespace() {
+		case "UserImportPreview.Fullname.Value":
+			target = &preview.Fullname.Message
+		case "UserImportPreview.Username.Value":
+			target = &preview.Username.Message
+		case "UserImportPreview.Email.Value":
+			target = &preview.Email.Message
+		case "UserImportPreview.Role.Value":
+			target = &preview.Role.Message
+		}
+
+		if target != nil && *target == "" {
+			*target = message
+		}
+	}
+}
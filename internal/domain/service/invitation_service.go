@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"goapptemp/config"
+	"goapptemp/internal/adapter/repository"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	serror "goapptemp/internal/domain/service/error"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"goapptemp/pkg/taskqueue"
+	"net/url"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	validator "github.com/go-playground/validator/v10"
+)
+
+const defaultInvitationTokenTTL = 72 * time.Hour
+
+var _ InvitationService = (*invitationService)(nil)
+
+type InvitationService interface {
+	Invite(ctx context.Context, req *InviteUserRequest) (*entity.User, error)
+	Resend(ctx context.Context, req *ResendInvitationRequest) error
+	Revoke(ctx context.Context, req *RevokeInvitationRequest) error
+	Verify(ctx context.Context, req *VerifyInvitationRequest) error
+	Accept(ctx context.Context, req *AcceptInvitationRequest) error
+	TemplateImport(ctx context.Context, req *TemplateImportUserRequest) (*FileServiceData, error)
+	ImportPreview(ctx context.Context, req *ImportPreviewUserRequest) ([]*UserImportPreview, error)
+	Import(ctx context.Context, req *ImportUsersRequest) ([]*ImportedUser, error)
+}
+
+// invitationService creates users in the pending state, one at a time or imported from Excel, and
+// lets them activate their account by choosing their own password through a single-use link, so
+// admins never handle passwords.
+type invitationService struct {
+	config    *config.Config
+	repo      repository.Repository
+	logger    logger.Logger
+	auth      AuthService
+	events    EventService
+	tasks     taskqueue.Queue
+	passwords *PasswordPolicy
+	validate  *validator.Validate
+	policies  *AccessPolicy
+}
+
+func NewInvitationService(
+	config *config.Config,
+	repo repository.Repository,
+	logger logger.Logger,
+	auth AuthService,
+	events EventService,
+	tasks taskqueue.Queue,
+	passwords *PasswordPolicy,
+	validate *validator.Validate,
+	policies *AccessPolicy,
+) *invitationService {
+	return &invitationService{
+		config:    config,
+		repo:      repo,
+		logger:    logger,
+		auth:      auth,
+		events:    events,
+		tasks:     tasks,
+		passwords: passwords,
+		validate:  validate,
+		policies:  policies,
+	}
+}
+
+type InviteUserRequest struct {
+	AuthParams *AuthParams
+	User       *entity.User
+}
+
+// Invite creates the user as pending with an unusable random password and emails the invite link.
+func (s *invitationService) Invite(ctx context.Context, req *InviteUserRequest) (*entity.User, error) {
+	if err := s.authorize(ctx, req.AuthParams, "USER.CREATE"); err != nil {
+		return nil, err
+	}
+
+	if req.User == nil {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "User data cannot be nil")
+	}
+
+	password, err := randomToken()
+	if err != nil {
+		return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to generate user password")
+	}
+
+	if err := req.User.SetPassword(password); err != nil {
+		return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to hash user password during invite")
+	}
+
+	req.User.Status = entity.UserStatusPending
+	req.User.PasswordChangedAt = nil
+
+	var user *entity.User
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		var err error
+
+		user, err = txRepo.User().Create(ctx, req.User)
+		if err != nil {
+			return err
+		}
+
+		if len(req.User.RoleIDs) != 0 {
+			if err := checkRoleCompany(ctx, txRepo, user.CompanyID, req.User.RoleIDs); err != nil {
+				return err
+			}
+
+			if _, err = txRepo.User().AttachRoles(ctx, user.ID, req.User.RoleIDs); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	user, err = s.repo.MySQL().User().FindByID(ctx, user.ID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if err := s.send(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, newUserEvent(entity.EventUserInvited, user, req.AuthParams.AccessTokenClaims.UserID))
+
+	user.Password = ""
+
+	return user, nil
+}
+
+type ResendInvitationRequest struct {
+	AuthParams *AuthParams
+	UserID     uint
+}
+
+// Resend issues a fresh link; the previous one stops working.
+func (s *invitationService) Resend(ctx context.Context, req *ResendInvitationRequest) error {
+	if err := s.authorize(ctx, req.AuthParams, "USER.CREATE"); err != nil {
+		return err
+	}
+
+	user, err := s.findPending(ctx, req.AuthParams, PolicyActionUpdate, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	return s.send(ctx, user)
+}
+
+type RevokeInvitationRequest struct {
+	AuthParams *AuthParams
+	UserID     uint
+}
+
+// Revoke invalidates the link and deletes the pending user, freeing its username and email.
+func (s *invitationService) Revoke(ctx context.Context, req *RevokeInvitationRequest) error {
+	if err := s.authorize(ctx, req.AuthParams, "USER.DELETE"); err != nil {
+		return err
+	}
+
+	user, err := s.findPending(ctx, req.AuthParams, PolicyActionDelete, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Redis().DeleteInviteToken(ctx, user.ID); err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	if err := s.repo.MySQL().User().Delete(ctx, user.ID); err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	s.events.Publish(ctx, newUserEvent(entity.EventUserDeleted, user, req.AuthParams.AccessTokenClaims.UserID))
+
+	return nil
+}
+
+type VerifyInvitationRequest struct {
+	Token string
+}
+
+func (s *invitationService) Verify(ctx context.Context, req *VerifyInvitationRequest) error {
+	_, err := s.userFromToken(ctx, req.Token)
+
+	return err
+}
+
+type AcceptInvitationRequest struct {
+	Token    string
+	Password string
+}
+
+// Accept sets the invited user's password and activates the account. The link stays valid when the
+// password is rejected by the policy, so the user can try again. Activation only applies to a user
+// that is still pending, so a link used twice at once activates the account once.
+func (s *invitationService) Accept(ctx context.Context, req *AcceptInvitationRequest) error {
+	user, err := s.userFromToken(ctx, req.Token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.passwords.Validate("password", req.Password, user); err != nil {
+		return err
+	}
+
+	hashedPassword, err := shared.HashPassword(req.Password)
+	if err != nil {
+		return exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to hash user password during activation")
+	}
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		if err := txRepo.User().Activate(ctx, user.ID, hashedPassword, time.Now()); err != nil {
+			return err
+		}
+
+		return s.passwords.Remember(ctx, txRepo, user.ID, hashedPassword)
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		if errors.Is(err, exception.ErrNotFound) {
+			return errInvalidInvitation()
+		}
+
+		return serror.TranslateRepoError(err)
+	}
+
+	if err := s.repo.Redis().DeleteInviteToken(ctx, user.ID); err != nil {
+		s.logger.Warn().Err(err).Msgf("Failed to delete invite token of activated user %d", user.ID)
+	}
+
+	s.events.Publish(ctx, newUserEvent(entity.EventUserActivated, user, user.ID))
+
+	return nil
+}
+
+// send stores a new invite token, replacing any previous one, and queues the invitation email.
+func (s *invitationService) send(ctx context.Context, user *entity.User) error {
+	token, err := randomToken()
+	if err != nil {
+		return exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to generate invite token")
+	}
+
+	ttl := secondsOr(s.config.Invitation.TokenTTL, defaultInvitationTokenTTL)
+	if err := s.repo.Redis().StoreInviteToken(ctx, token, user.ID, ttl); err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	link := fmt.Sprintf("%s/accept-invite?token=%s", s.config.App.FrontendURL, url.QueryEscape(token))
+
+	err = s.tasks.Enqueue(context.WithoutCancel(ctx), TaskSendInvitationEmail, &emailTask{UserID: user.ID, Recipient: NewEmailRecipient(user), Link: link})
+	if err != nil {
+		return exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to queue invitation email")
+	}
+
+	return nil
+}
+
+func errInvalidInvitation() error {
+	return exception.New(exception.TypeBadRequest, "INVALID_TOKEN", "Invalid or expired invitation")
+}
+
+func (s *invitationService) userFromToken(ctx context.Context, token string) (*entity.User, error) {
+	errInvalid := errInvalidInvitation()
+
+	userID, err := s.repo.Redis().GetUserIDFromInviteToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, exception.ErrNotFound) {
+			return nil, errInvalid
+		}
+
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	user, err := s.repo.MySQL().User().FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, exception.ErrNotFound) {
+			return nil, errInvalid
+		}
+
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if user.Status != entity.UserStatusPending {
+		return nil, errInvalid
+	}
+
+	return user, nil
+}
+
+// findPending loads the user for an invitation change, checking the access policies before revealing
+// whether the user still has an invitation pending.
+func (s *invitationService) findPending(ctx context.Context, authParams *AuthParams, action string, userID uint) (*entity.User, error) {
+	if userID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "User ID cannot be zero")
+	}
+
+	user, err := s.repo.MySQL().User().FindByID(ctx, userID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if err := s.policies.Authorize(ctx, s.repo.MySQL(), authParams, PolicyResourceUser, action, userPolicyAttributes(user)); err != nil {
+		return nil, err
+	}
+
+	if user.Status != entity.UserStatusPending {
+		return nil, exception.New(exception.TypeConflict, exception.CodeConflict, "User has no pending invitation")
+	}
+
+	return user, nil
+}
+
+func (s *invitationService) authorize(ctx context.Context, authParams *AuthParams, permissionCode string) error {
+	if authParams == nil || authParams.AccessTokenClaims == nil {
+		return exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, authParams.AccessTokenClaims.UserID, permissionCode)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"goapptemp/config"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"goapptemp/pkg/scheduler"
+
+	"github.com/cockroachdb/errors"
+)
+
+var _ JobService = (*jobService)(nil)
+
+type JobService interface {
+	Find(ctx context.Context, req *FindJobsRequest) ([]*scheduler.JobInfo, error)
+	FindRuns(ctx context.Context, req *FindJobRunsRequest) ([]*scheduler.Run, error)
+	Trigger(ctx context.Context, req *TriggerJobRequest) (*scheduler.Run, error)
+}
+
+type jobService struct {
+	config    *config.Config
+	logger    logger.Logger
+	auth      AuthService
+	scheduler scheduler.Scheduler
+}
+
+func NewJobService(config *config.Config, logger logger.Logger, auth AuthService, scheduler scheduler.Scheduler) *jobService {
+	return &jobService{
+		config:    config,
+		logger:    logger,
+		auth:      auth,
+		scheduler: scheduler,
+	}
+}
+
+type FindJobsRequest struct {
+	AuthParams *AuthParams
+}
+
+func (s *jobService) Find(ctx context.Context, req *FindJobsRequest) ([]*scheduler.JobInfo, error) {
+	if err := s.authorize(ctx, req.AuthParams, "JOB.READ"); err != nil {
+		return nil, err
+	}
+
+	return s.scheduler.Jobs(ctx), nil
+}
+
+type FindJobRunsRequest struct {
+	AuthParams *AuthParams
+	Name       string
+	Limit      int
+}
+
+func (s *jobService) FindRuns(ctx context.Context, req *FindJobRunsRequest) ([]*scheduler.Run, error) {
+	if err := s.authorize(ctx, req.AuthParams, "JOB.READ"); err != nil {
+		return nil, err
+	}
+
+	runs, err := s.scheduler.Runs(ctx, req.Name, req.Limit)
+	if err != nil {
+		return nil, translateSchedulerError(err)
+	}
+
+	return runs, nil
+}
+
+type TriggerJobRequest struct {
+	AuthParams *AuthParams
+	Name       string
+}
+
+func (s *jobService) Trigger(ctx context.Context, req *TriggerJobRequest) (*scheduler.Run, error) {
+	if err := s.authorize(ctx, req.AuthParams, "JOB.EXECUTE"); err != nil {
+		return nil, err
+	}
+
+	run, err := s.scheduler.Trigger(ctx, req.Name)
+	if err != nil {
+		return nil, translateSchedulerError(err)
+	}
+
+	s.logger.Info().Msgf("Job %s triggered manually by user %d", req.Name, req.AuthParams.AccessTokenClaims.UserID)
+
+	return run, nil
+}
+
+func (s *jobService) authorize(ctx context.Context, authParams *AuthParams, permissionCode string) error {
+	if authParams == nil || authParams.AccessTokenClaims == nil {
+		return exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, authParams.AccessTokenClaims.UserID, permissionCode)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	return nil
+}
+
+func translateSchedulerError(err error) error {
+	switch {
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		return exception.Wrap(err, exception.TypeNotFound, exception.CodeNotFound, "Job not found")
+	case errors.Is(err, scheduler.ErrJobRunning):
+		return exception.Wrap(err, exception.TypeConflict, exception.CodeConflict, "Job is already running")
+	default:
+		return exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to run job")
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"goapptemp/config"
+	"goapptemp/internal/adapter/repository"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+
+	serror "goapptemp/internal/domain/service/error"
+)
+
+// maxLoginBlocks caps how many locks a single listing scans for.
+const maxLoginBlocks = 1000
+
+var _ LockoutService = (*lockoutService)(nil)
+
+// LockoutService lets administrators inspect and lift the brute-force locks the login flow places
+// on usernames and IP addresses.
+type LockoutService interface {
+	FindLockedUsers(ctx context.Context, req *FindLockedUsersRequest) ([]*entity.LoginBlock, error)
+	FindBlockedIPs(ctx context.Context, req *FindBlockedIPsRequest) ([]*entity.LoginBlock, error)
+	UnlockUser(ctx context.Context, req *UnlockUserRequest) error
+	UnblockIP(ctx context.Context, req *UnblockIPRequest) error
+}
+
+type lockoutService struct {
+	config *config.Config
+	repo   repository.Repository
+	logger logger.Logger
+	auth   AuthService
+}
+
+func NewLockoutService(config *config.Config, repo repository.Repository, logger logger.Logger, auth AuthService) *lockoutService {
+	return &lockoutService{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		auth:   auth,
+	}
+}
+
+type FindLockedUsersRequest struct {
+	AuthParams *AuthParams
+}
+
+func (s *lockoutService) FindLockedUsers(ctx context.Context, req *FindLockedUsersRequest) ([]*entity.LoginBlock, error) {
+	if err := s.authorize(ctx, req.AuthParams, "LOCKOUT.READ"); err != nil {
+		return nil, err
+	}
+
+	blocks, err := s.repo.Redis().FindLockedUsers(ctx, maxLoginBlocks)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if len(blocks) == 0 {
+		return blocks, nil
+	}
+
+	usernames := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		usernames = append(usernames, block.Identifier)
+	}
+
+	users, _, err := s.repo.MySQL().User().Find(ctx, &mysqlrepository.FilterUserPayload{Usernames: usernames})
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	byUsername := make(map[string]*entity.User, len(users))
+	for _, user := range users {
+		user.Password = ""
+		byUsername[user.Username] = user
+	}
+
+	for _, block := range blocks {
+		block.User = byUsername[block.Identifier]
+	}
+
+	return blocks, nil
+}
+
+type FindBlockedIPsRequest struct {
+	AuthParams *AuthParams
+}
+
+func (s *lockoutService) FindBlockedIPs(ctx context.Context, req *FindBlockedIPsRequest) ([]*entity.LoginBlock, error) {
+	if err := s.authorize(ctx, req.AuthParams, "LOCKOUT.READ"); err != nil {
+		return nil, err
+	}
+
+	blocks, err := s.repo.Redis().FindBlockedIPs(ctx, maxLoginBlocks)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return blocks, nil
+}
+
+type UnlockUserRequest struct {
+	AuthParams *AuthParams
+	Username   string
+}
+
+func (s *lockoutService) UnlockUser(ctx context.Context, req *UnlockUserRequest) error {
+	if err := s.authorize(ctx, req.AuthParams, "LOCKOUT.UPDATE"); err != nil {
+		return err
+	}
+
+	if req.Username == "" {
+		return exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Username required")
+	}
+
+	unlocked, err := s.repo.Redis().UnlockUser(ctx, req.Username)
+	if err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	if !unlocked {
+		return exception.New(exception.TypeNotFound, exception.CodeNotFound, "User is not locked")
+	}
+
+	s.logger.Info().Msgf("User %s unlocked by user %d", req.Username, req.AuthParams.AccessTokenClaims.UserID)
+
+	return nil
+}
+
+type UnblockIPRequest struct {
+	AuthParams *AuthParams
+	IP         string
+}
+
+func (s *lockoutService) UnblockIP(ctx context.Context, req *UnblockIPRequest) error {
+	if err := s.authorize(ctx, req.AuthParams, "LOCKOUT.UPDATE"); err != nil {
+		return err
+	}
+
+	if req.IP == "" {
+		return exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "IP address required")
+	}
+
+	unblocked, err := s.repo.Redis().UnblockIP(ctx, req.IP)
+	if err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	if !unblocked {
+		return exception.New(exception.TypeNotFound, exception.CodeNotFound, "IP address is not blocked")
+	}
+
+	s.logger.Info().Msgf("IP %s unblocked by user %d", req.IP, req.AuthParams.AccessTokenClaims.UserID)
+
+	return nil
+}
+
+func (s *lockoutService) authorize(ctx context.Context, authParams *AuthParams, permissionCode string) error {
+	if authParams == nil || authParams.AccessTokenClaims == nil {
+		return exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, authParams.AccessTokenClaims.UserID, permissionCode)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"goapptemp/config"
+	"goapptemp/constant"
+	redisrepository "goapptemp/internal/adapter/repository/redis"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// LoginPolicy holds the brute-force limits for the current environment. IPs in the allowlist, such
+// as office or VPN ranges, are never counted or blocked; their usernames can still be locked.
+type LoginPolicy struct {
+	User      redisrepository.UserFailurePolicy
+	IP        redisrepository.IPFailurePolicy
+	allowlist []netip.Prefix
+}
+
+// NewLoginPolicy fills unset limits with the defaults from the constant package and fails on an
+// allowlist entry that is neither an IP nor a CIDR, rather than silently ignoring it.
+func NewLoginPolicy(cfg *config.BruteForceConfig) (*LoginPolicy, error) {
+	if cfg == nil {
+		cfg = &config.BruteForceConfig{}
+	}
+
+	policy := &LoginPolicy{
+		User: redisrepository.UserFailurePolicy{
+			MaxAttempts: positiveOr(cfg.UserMaxAttempts, constant.UserFailedAttemptsLimit),
+			Window:      secondsOr(cfg.UserWindow, constant.UserFailedWindow),
+			Lockout:     secondsOr(cfg.UserLockout, constant.UserLockoutDuration),
+		},
+		IP: redisrepository.IPFailurePolicy{
+			MaxAttempts: positiveOr(cfg.IPMaxAttempts, constant.IpRateLimitAttempts),
+			Window:      secondsOr(cfg.IPWindow, constant.IpRateLimitWindow),
+			BackoffBase: secondsOr(cfg.IPBackoffBase, time.Duration(constant.IpBackoffBaseSeconds)*time.Second),
+			BackoffMax:  secondsOr(cfg.IPBackoffMax, time.Duration(constant.IpBackoffMaxSeconds)*time.Second),
+			LevelReset:  secondsOr(cfg.IPLevelReset, constant.IpBlockLevelReset),
+		},
+	}
+
+	if policy.IP.BackoffMax < policy.IP.BackoffBase {
+		return nil, errors.Newf("brute force IP backoff max %s is shorter than its base %s", policy.IP.BackoffMax, policy.IP.BackoffBase)
+	}
+
+	for _, entry := range cfg.IPAllowlist {
+		prefix, err := parseIPPrefix(entry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid brute force allowlist entry %q", entry)
+		}
+
+		policy.allowlist = append(policy.allowlist, prefix)
+	}
+
+	return policy, nil
+}
+
+// Allowlisted reports whether ip falls inside one of the allowlisted ranges.
+func (p *LoginPolicy) Allowlisted(ip string) bool {
+	if p == nil || len(p.allowlist) == 0 || ip == "" {
+		return false
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range p.allowlist {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseIPPrefix accepts CIDR notation or a bare address, which is treated as a single-host range.
+func parseIPPrefix(entry string) (netip.Prefix, error) {
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	addr = addr.Unmap()
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
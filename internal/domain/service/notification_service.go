@@ -2,71 +2,449 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"goapptemp/config"
+	"goapptemp/emailtemplate"
+	"goapptemp/internal/adapter/repository"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	serror "goapptemp/internal/domain/service/error"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/emailsender"
 	"goapptemp/pkg/logger"
+	"goapptemp/pkg/mailtemplate"
+	"goapptemp/pkg/taskqueue"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	EmailDriverSMTP  = "smtp"
+	EmailDriverGmail = "gmail"
+	EmailDriverFile  = "file"
+)
+
+const (
+	EmailTemplatePasswordReset        = "password_reset"
+	EmailTemplatePasswordResetSuccess = "password_reset_success"
+	EmailTemplateUserInvitation       = "user_invitation"
 )
 
 type NotificationService interface {
-	SendPasswordResetEmail(ctx context.Context, userEmail, resetLink string) error
-	SendPasswordResetSuccessEmail(ctx context.Context, userEmail string) error
+	SendPasswordResetEmail(ctx context.Context, recipient *EmailRecipient, resetLink string) error
+	SendPasswordResetSuccessEmail(ctx context.Context, recipient *EmailRecipient) error
+	SendInvitationEmail(ctx context.Context, recipient *EmailRecipient, inviteLink string) error
+	Notify(ctx context.Context, req *NotifyRequest) error
+	Find(ctx context.Context, req *FindNotificationsRequest) ([]*entity.Notification, int, error)
+	CountUnread(ctx context.Context, req *CountUnreadNotificationsRequest) (int, error)
+	MarkRead(ctx context.Context, req *MarkNotificationsReadRequest) (int, error)
+	MarkAllRead(ctx context.Context, req *MarkAllNotificationsReadRequest) (int, error)
+	FindPreferences(ctx context.Context, req *FindNotificationPreferencesRequest) ([]*entity.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, req *UpdateNotificationPreferencesRequest) ([]*entity.NotificationPreference, error)
+}
+
+// EmailRecipient carries what templates need to address and localise an email.
+type EmailRecipient struct {
+	Email  string `json:"email"`
+	Name   string `json:"name,omitempty"`
+	Locale string `json:"locale,omitempty"`
+}
+
+func NewEmailRecipient(user *entity.User) *EmailRecipient {
+	return &EmailRecipient{
+		Email:  user.Email,
+		Name:   user.Fullname,
+		Locale: user.Locale,
+	}
 }
 
 type EmailSender interface {
-	SendEmail(ctx context.Context, to, subject, body string) error
+	Send(ctx context.Context, msg *emailsender.Message) error
+}
+
+// NewEmailSender picks the email driver from config. Without an explicit driver it keeps using Gmail
+// when credentials are configured and otherwise falls back to the file driver, so the app can boot
+// in development without any mail provider.
+func NewEmailSender(ctx context.Context, config *config.Config, logger logger.Logger) (EmailSender, error) {
+	cfg := config.Email
+
+	sender := emailsender.Sender{
+		Address: cfg.From,
+		Name:    cfg.FromName,
+		ReplyTo: cfg.ReplyTo,
+	}
+	if sender.Address == "" {
+		sender.Address = config.Gmail.Sender
+	}
+
+	if sender.Name == "" {
+		sender.Name = config.App.Name
+	}
+
+	driver := cfg.Driver
+	if driver == "" {
+		driver = EmailDriverFile
+		if config.Gmail.CredFile != "" {
+			driver = EmailDriverGmail
+		}
+	}
+
+	switch driver {
+	case EmailDriverSMTP:
+		return emailsender.NewSMTPSender(emailsender.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			Security: emailsender.SMTPSecurity(cfg.SMTPSecurity),
+			Timeout:  time.Duration(cfg.SMTPTimeout) * time.Second,
+		}, sender)
+	case EmailDriverGmail:
+		return emailsender.NewGmailSender(ctx, config.Gmail.CredFile, config.Gmail.TokenFile, sender)
+	case EmailDriverFile:
+		if sender.Address == "" {
+			sender.Address = "no-reply@localhost"
+		}
+
+		return emailsender.NewFileSender(cfg.FileDir, sender, logger)
+	default:
+		return nil, errors.Newf("unsupported email driver %q", driver)
+	}
+}
+
+// NewEmailTemplateRegistry loads the embedded email templates, failing startup if any of them is broken.
+func NewEmailTemplateRegistry(config *config.Config) (mailtemplate.Registry, error) {
+	return mailtemplate.NewRegistry(emailtemplate.FS,
+		mailtemplate.WithAppName(config.App.Name),
+		mailtemplate.WithDefaultLocale(config.Email.DefaultLocale),
+	)
 }
 
 var _ NotificationService = (*notificationService)(nil)
 
+// notificationService sends the transactional emails and runs the notification center: a
+// notification is fanned out to the channels the user has enabled, each delivered by its own task
+// so a failing channel is retried without repeating the others.
 type notificationService struct {
-	emailSender EmailSender
+	config      *config.Config
+	repo        repository.Repository
 	logger      logger.Logger
+	emailSender EmailSender
+	templates   mailtemplate.Registry
+	events      EventService
+	tasks       taskqueue.Queue
 }
 
-func NewNotificationService(emailSender EmailSender, log logger.Logger) NotificationService {
+func NewNotificationService(
+	config *config.Config,
+	repo repository.Repository,
+	log logger.Logger,
+	emailSender EmailSender,
+	templates mailtemplate.Registry,
+	events EventService,
+	tasks taskqueue.Queue,
+) *notificationService {
 	return &notificationService{
-		emailSender: emailSender,
+		config:      config,
+		repo:        repo,
 		logger:      log,
+		emailSender: emailSender,
+		templates:   templates,
+		events:      events,
+		tasks:       tasks,
 	}
 }
 
-func (s *notificationService) SendPasswordResetEmail(ctx context.Context, userEmail, resetLink string) error {
-	subject := "Reset Your Password"
+func (s *notificationService) SendPasswordResetEmail(ctx context.Context, recipient *EmailRecipient, resetLink string) error {
+	return s.send(ctx, recipient, EmailTemplatePasswordReset, map[string]any{
+		"Name":             recipient.Name,
+		"ResetLink":        resetLink,
+		"ExpiresInMinutes": int(passwordResetTokenTTL / time.Minute),
+	})
+}
+
+func (s *notificationService) SendPasswordResetSuccessEmail(ctx context.Context, recipient *EmailRecipient) error {
+	return s.send(ctx, recipient, EmailTemplatePasswordResetSuccess, map[string]any{
+		"Name": recipient.Name,
+	})
+}
+
+func (s *notificationService) SendInvitationEmail(ctx context.Context, recipient *EmailRecipient, inviteLink string) error {
+	return s.send(ctx, recipient, EmailTemplateUserInvitation, map[string]any{
+		"Name":           recipient.Name,
+		"InviteLink":     inviteLink,
+		"ExpiresInHours": int(secondsOr(s.config.Invitation.TokenTTL, defaultInvitationTokenTTL) / time.Hour),
+	})
+}
+
+func (s *notificationService) send(ctx context.Context, recipient *EmailRecipient, template string, data map[string]any) error {
+	rendered, err := s.templates.Render(template, recipient.Locale, data)
+	if err != nil {
+		s.logger.Error().Err(err).Msgf("Failed to render %s email", template)
+		return err
+	}
 
-	body := fmt.Sprintf(`
-		<p>Hello,</p>
-		<p>You requested a password reset. Click the link below to set a new password:</p>
-		<p><a href="%s">Reset Password</a></p>
-		<p>This link is valid for 15 minutes.</p>
-		<p>If you did not request this, please ignore this email.</p>
-	`, resetLink)
+	return s.sendRendered(ctx, recipient, rendered)
+}
 
-	err := s.emailSender.SendEmail(ctx, userEmail, subject, body)
+func (s *notificationService) sendRendered(ctx context.Context, recipient *EmailRecipient, rendered *mailtemplate.Rendered) error {
+	err := s.emailSender.Send(ctx, &emailsender.Message{
+		To:      []string{recipient.Email},
+		Subject: rendered.Subject,
+		HTML:    rendered.HTML,
+		Text:    rendered.Text,
+	})
 	if err != nil {
-		s.logger.Error().Err(err).Msgf("Failed to send password reset email to %s", userEmail)
+		s.logger.Error().Err(err).Msgf("Failed to send %s email to %s", rendered.Name, recipient.Email)
 		return err
 	}
 
-	s.logger.Info().Msgf("Password reset email sent to %s", userEmail)
+	s.logger.Info().Msgf("Email %s (%s) sent to %s", rendered.Name, rendered.Locale, recipient.Email)
 
 	return nil
 }
 
-func (s *notificationService) SendPasswordResetSuccessEmail(ctx context.Context, userEmail string) error {
-	subject := "Your Password Has Been Changed"
+type NotifyRequest struct {
+	UserID uint
+	Type   entity.NotificationType
+	// Data is handed to the notification's template and stored with the in-app notification.
+	Data map[string]any
+}
 
-	body := `
-		<p>Hello,</p>
-		<p>This is a confirmation that the password for your account has just been changed.</p>
-		<p>If you did not make this change, please contact our support team immediately.</p>
-	`
+// Notify queues a notification for delivery; the user's preferences are resolved when it is dispatched.
+func (s *notificationService) Notify(ctx context.Context, req *NotifyRequest) error {
+	if req == nil || req.UserID == 0 {
+		return exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Notification recipient required")
+	}
 
-	err := s.emailSender.SendEmail(ctx, userEmail, subject, body)
-	if err != nil {
-		s.logger.Error().Err(err).Msgf("Failed to send password reset success email to %s", userEmail)
-		return err
+	if !req.Type.Valid() {
+		return exception.Newf(exception.TypeBadRequest, exception.CodeBadRequest, "Unknown notification type %s", req.Type)
 	}
 
-	s.logger.Info().Msgf("Password reset success email sent to %s", userEmail)
+	err := s.tasks.Enqueue(context.WithoutCancel(ctx), TaskDispatchNotification, &notificationTask{
+		UserID: req.UserID,
+		Type:   req.Type,
+		Data:   req.Data,
+	})
+	if err != nil {
+		return exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to queue notification")
+	}
 
 	return nil
 }
+
+type FindNotificationsRequest struct {
+	AuthParams *AuthParams
+	UnreadOnly bool
+	Types      []string
+	Page       int
+	PerPage    int
+}
+
+func (s *notificationService) Find(ctx context.Context, req *FindNotificationsRequest) ([]*entity.Notification, int, error) {
+	userID, err := notificationOwner(req.AuthParams)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	notifications, totalCount, err := s.repo.MySQL().Notification().Find(ctx, &mysqlrepository.FilterNotificationPayload{
+		UserID:     userID,
+		UnreadOnly: req.UnreadOnly,
+		Types:      req.Types,
+		Page:       req.Page,
+		PerPage:    req.PerPage,
+	})
+	if err != nil {
+		return nil, 0, serror.TranslateRepoError(err)
+	}
+
+	return notifications, totalCount, nil
+}
+
+type CountUnreadNotificationsRequest struct {
+	AuthParams *AuthParams
+}
+
+func (s *notificationService) CountUnread(ctx context.Context, req *CountUnreadNotificationsRequest) (int, error) {
+	userID, err := notificationOwner(req.AuthParams)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.repo.MySQL().Notification().CountUnread(ctx, userID)
+	if err != nil {
+		return 0, serror.TranslateRepoError(err)
+	}
+
+	return count, nil
+}
+
+type MarkNotificationsReadRequest struct {
+	AuthParams *AuthParams
+	IDs        []uint64
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, req *MarkNotificationsReadRequest) (int, error) {
+	userID, err := notificationOwner(req.AuthParams)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(req.IDs) == 0 {
+		return 0, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Notification IDs required")
+	}
+
+	updated, err := s.repo.MySQL().Notification().MarkRead(ctx, userID, req.IDs)
+	if err != nil {
+		return 0, serror.TranslateRepoError(err)
+	}
+
+	return updated, nil
+}
+
+type MarkAllNotificationsReadRequest struct {
+	AuthParams *AuthParams
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, req *MarkAllNotificationsReadRequest) (int, error) {
+	userID, err := notificationOwner(req.AuthParams)
+	if err != nil {
+		return 0, err
+	}
+
+	updated, err := s.repo.MySQL().Notification().MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, serror.TranslateRepoError(err)
+	}
+
+	return updated, nil
+}
+
+type FindNotificationPreferencesRequest struct {
+	AuthParams *AuthParams
+}
+
+// FindPreferences returns every type and channel combination, filling the ones the user never
+// changed with the type's default. Required channels are always reported as enabled.
+func (s *notificationService) FindPreferences(ctx context.Context, req *FindNotificationPreferencesRequest) ([]*entity.NotificationPreference, error) {
+	userID, err := notificationOwner(req.AuthParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.preferences(ctx, userID)
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	AuthParams  *AuthParams
+	Preferences []*entity.NotificationPreference
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, req *UpdateNotificationPreferencesRequest) ([]*entity.NotificationPreference, error) {
+	userID, err := notificationOwner(req.AuthParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Preferences) == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Notification preferences required")
+	}
+
+	for _, pref := range req.Preferences {
+		if pref == nil {
+			return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Notification preference cannot be nil")
+		}
+
+		if !pref.Type.Valid() {
+			return nil, exception.Newf(exception.TypeBadRequest, exception.CodeBadRequest, "Unknown notification type %s", pref.Type)
+		}
+
+		if !pref.Channel.Valid() {
+			return nil, exception.Newf(exception.TypeBadRequest, exception.CodeBadRequest, "Unknown notification channel %s", pref.Channel)
+		}
+
+		if !pref.Enabled && pref.Type.Required(pref.Channel) {
+			return nil, exception.Newf(exception.TypeBadRequest, exception.CodeBadRequest, "%s notifications cannot be turned off for %s", pref.Channel, pref.Type)
+		}
+
+		pref.UserID = userID
+	}
+
+	if err := s.repo.MySQL().NotificationPreference().Upsert(ctx, req.Preferences); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return s.preferences(ctx, userID)
+}
+
+func (s *notificationService) preferences(ctx context.Context, userID uint) ([]*entity.NotificationPreference, error) {
+	stored, err := s.repo.MySQL().NotificationPreference().FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	saved := make(map[string]*entity.NotificationPreference, len(stored))
+	for _, pref := range stored {
+		saved[string(pref.Type)+"/"+string(pref.Channel)] = pref
+	}
+
+	types := slices.Sorted(maps.Keys(entity.NotificationDefaults))
+	res := make([]*entity.NotificationPreference, 0, len(types)*len(entity.NotificationChannels))
+
+	for _, notificationType := range types {
+		for _, channel := range entity.NotificationChannels {
+			if pref, ok := saved[string(notificationType)+"/"+string(channel)]; ok {
+				pref.Enabled = pref.Enabled || notificationType.Required(channel)
+				res = append(res, pref)
+
+				continue
+			}
+
+			res = append(res, &entity.NotificationPreference{
+				UserID:  userID,
+				Type:    notificationType,
+				Channel: channel,
+				Enabled: notificationType.EnabledByDefault(channel) || notificationType.Required(channel),
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// channels lists the channels notificationType should be delivered on for userID.
+func (s *notificationService) channels(ctx context.Context, userID uint, notificationType entity.NotificationType) ([]entity.NotificationChannel, error) {
+	prefs, err := s.preferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []entity.NotificationChannel
+
+	for _, pref := range prefs {
+		if pref.Type == notificationType && pref.Enabled {
+			res = append(res, pref.Channel)
+		}
+	}
+
+	return res, nil
+}
+
+// notificationTemplate maps a notification type to its email template, e.g. "user.roles_changed"
+// to "user_roles_changed".
+func notificationTemplate(notificationType entity.NotificationType) string {
+	return strings.ReplaceAll(string(notificationType), ".", "_")
+}
+
+// notificationOwner returns the user whose inbox is accessed. Every user may manage their own
+// notifications, so no permission is checked beyond authentication.
+func notificationOwner(authParams *AuthParams) (uint, error) {
+	if authParams == nil || authParams.AccessTokenClaims == nil {
+		return 0, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	return authParams.AccessTokenClaims.UserID, nil
+}
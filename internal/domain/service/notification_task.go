@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/taskqueue"
+	"maps"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	TaskSendPasswordResetEmail        = "email.password_reset"
+	TaskSendPasswordResetSuccessEmail = "email.password_reset_success"
+	TaskSendInvitationEmail           = "email.user_invitation"
+	TaskDispatchNotification          = "notification.dispatch"
+	TaskDeliverNotification           = "notification.deliver"
+)
+
+// emailTask addresses a transactional email; Link is set for the emails that carry one.
+type emailTask struct {
+	UserID    uint            `json:"user_id"`
+	Recipient *EmailRecipient `json:"recipient"`
+	Link      string          `json:"link,omitempty"`
+}
+
+// notificationTask is dispatched without a channel and fanned out into one deliver task per channel.
+type notificationTask struct {
+	UserID  uint                       `json:"user_id"`
+	Type    entity.NotificationType    `json:"type"`
+	Channel entity.NotificationChannel `json:"channel,omitempty"`
+	Data    map[string]any             `json:"data,omitempty"`
+}
+
+func (s *notificationService) registerTasks(queue taskqueue.Queue) error {
+	handlers := map[string]taskqueue.Handler{
+		TaskSendPasswordResetEmail:        s.sendPasswordResetEmail,
+		TaskSendPasswordResetSuccessEmail: s.sendPasswordResetSuccessEmail,
+		TaskSendInvitationEmail:           s.sendInvitationEmail,
+		TaskDispatchNotification:          s.dispatchNotification,
+		TaskDeliverNotification:           s.deliverNotification,
+	}
+
+	for taskType, handler := range handlers {
+		if err := queue.Register(taskType, handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *notificationService) sendPasswordResetEmail(ctx context.Context, task *taskqueue.Task) error {
+	var payload emailTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	if payload.Recipient == nil {
+		return errors.Mark(errors.New("email task has no recipient"), taskqueue.ErrPermanent)
+	}
+
+	if err := s.SendPasswordResetEmail(ctx, payload.Recipient, payload.Link); err != nil {
+		return errors.Wrapf(err, "failed to send password reset email to user %d", payload.UserID)
+	}
+
+	return nil
+}
+
+func (s *notificationService) sendPasswordResetSuccessEmail(ctx context.Context, task *taskqueue.Task) error {
+	var payload emailTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	if payload.Recipient == nil {
+		return errors.Mark(errors.New("email task has no recipient"), taskqueue.ErrPermanent)
+	}
+
+	if err := s.SendPasswordResetSuccessEmail(ctx, payload.Recipient); err != nil {
+		return errors.Wrapf(err, "failed to send password reset success email to user %d", payload.UserID)
+	}
+
+	return nil
+}
+
+func (s *notificationService) sendInvitationEmail(ctx context.Context, task *taskqueue.Task) error {
+	var payload emailTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	if payload.Recipient == nil {
+		return errors.Mark(errors.New("email task has no recipient"), taskqueue.ErrPermanent)
+	}
+
+	if err := s.SendInvitationEmail(ctx, payload.Recipient, payload.Link); err != nil {
+		return errors.Wrapf(err, "failed to send invitation email to user %d", payload.UserID)
+	}
+
+	return nil
+}
+
+func (s *notificationService) dispatchNotification(ctx context.Context, task *taskqueue.Task) error {
+	var payload notificationTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	if !payload.Type.Valid() {
+		return errors.Mark(errors.Newf("unknown notification type %s", payload.Type), taskqueue.ErrPermanent)
+	}
+
+	channels, err := s.channels(ctx, payload.UserID, payload.Type)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve notification channels for user %d", payload.UserID)
+	}
+
+	// All channels are queued in one write: a retry after a partial failure would otherwise deliver
+	// the channels that did get queued twice.
+	deliveries := make([]any, 0, len(channels))
+
+	for _, channel := range channels {
+		delivery := payload
+		delivery.Channel = channel
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err := s.tasks.EnqueueBatch(ctx, TaskDeliverNotification, deliveries...); err != nil {
+		return errors.Wrapf(err, "failed to queue notifications for user %d", payload.UserID)
+	}
+
+	return nil
+}
+
+func (s *notificationService) deliverNotification(ctx context.Context, task *taskqueue.Task) error {
+	var payload notificationTask
+	if err := task.Decode(&payload); err != nil {
+		return err
+	}
+
+	user, err := s.repo.MySQL().User().FindByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, exception.ErrNotFound) {
+			return errors.Mark(errors.Wrapf(err, "notification recipient %d not found", payload.UserID), taskqueue.ErrPermanent)
+		}
+
+		return errors.Wrapf(err, "failed to find notification recipient %d", payload.UserID)
+	}
+
+	// Name is always provided so templates can greet the user without every caller passing it.
+	data := map[string]any{"Name": user.Fullname}
+	maps.Copy(data, payload.Data)
+
+	rendered, err := s.templates.Render(notificationTemplate(payload.Type), user.Locale, data)
+	if err != nil {
+		return errors.Mark(errors.Wrapf(err, "failed to render %s notification", payload.Type), taskqueue.ErrPermanent)
+	}
+
+	body := rendered.Summary
+	if body == "" {
+		body = rendered.Text
+	}
+
+	switch payload.Channel {
+	case entity.NotificationChannelInApp:
+		_, err = s.repo.MySQL().Notification().Create(ctx, &entity.Notification{
+			UserID: user.ID,
+			Type:   payload.Type,
+			Title:  rendered.Subject,
+			Body:   body,
+			Data:   payload.Data,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to store notification for user %d", user.ID)
+		}
+	case entity.NotificationChannelEmail:
+		if err := s.sendRendered(ctx, NewEmailRecipient(user), rendered); err != nil {
+			return errors.Wrapf(err, "failed to email notification to user %d", user.ID)
+		}
+	case entity.NotificationChannelWebhook:
+		s.events.Publish(ctx, &entity.DomainEvent{
+			Type:     entity.EventNotificationCreated,
+			TenantID: user.CompanyID,
+			Payload: entity.NotificationEventPayload{
+				UserID: user.ID,
+				Type:   string(payload.Type),
+				Title:  rendered.Subject,
+				Body:   body,
+				Data:   payload.Data,
+			},
+		})
+	default:
+		return errors.Mark(errors.Newf("unknown notification channel %q", payload.Channel), taskqueue.ErrPermanent)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"goapptemp/config"
+	"goapptemp/constant"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/breachedpassword"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// minIdentifierMatch keeps very short usernames from rejecting most passwords that happen to contain them.
+const minIdentifierMatch = 3
+
+// PasswordPolicy is the single set of password rules applied wherever a password is chosen: user
+// creation, admin updates, resets and self-service changes.
+type PasswordPolicy struct {
+	MinLength   int
+	MinClasses  int
+	MaxAge      time.Duration
+	HistorySize int
+	breached    *breachedpassword.List
+}
+
+// NewPasswordPolicy fills unset limits with the defaults from the constant package. A configured
+// breached list file that cannot be read fails startup rather than silently weakening the check.
+func NewPasswordPolicy(cfg *config.PasswordPolicyConfig) (*PasswordPolicy, error) {
+	if cfg == nil {
+		cfg = &config.PasswordPolicyConfig{}
+	}
+
+	breached, err := breachedpassword.New(cfg.BreachedListFile)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &PasswordPolicy{
+		MinLength:   positiveOr(cfg.MinLength, constant.MinPasswordLength),
+		MinClasses:  min(positiveOr(cfg.MinClasses, constant.MinPasswordClasses), 4),
+		HistorySize: positiveOr(cfg.HistorySize, constant.PasswordHistorySize),
+		breached:    breached,
+	}
+
+	if cfg.MaxAgeDays > 0 {
+		policy.MaxAge = time.Duration(cfg.MaxAgeDays) * 24 * time.Hour
+	}
+
+	return policy, nil
+}
+
+// Validate checks password against the static rules and reports every violation under field.
+// user supplies the username and email the password must not contain and may be nil.
+func (p *PasswordPolicy) Validate(field, password string, user *entity.User) error {
+	var violations []string
+
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+
+	if passwordClasses(password) < p.MinClasses {
+		violations = append(violations, fmt.Sprintf("must contain at least %d of: lowercase letters, uppercase letters, digits and symbols", p.MinClasses))
+	}
+
+	if user != nil {
+		lowered := strings.ToLower(password)
+
+		if username := strings.ToLower(user.Username); len(username) >= minIdentifierMatch && strings.Contains(lowered, username) {
+			violations = append(violations, "must not contain the username")
+		}
+
+		local, _, _ := strings.Cut(strings.ToLower(user.Email), "@")
+		if len(local) >= minIdentifierMatch && strings.Contains(lowered, local) {
+			violations = append(violations, "must not contain the email address")
+		}
+	}
+
+	if p.breached.Contains(password) {
+		violations = append(violations, "is too common or has appeared in a data breach")
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return exception.NewWithErrors(exception.TypeValidationError, exception.CodeValidationFailed, "Password does not meet the password policy",
+		exception.FieldErrors{field: violations})
+}
+
+// CheckHistory rejects the user's current password and the last HistorySize passwords.
+func (p *PasswordPolicy) CheckHistory(ctx context.Context, repo mysqlrepository.MySQLRepository, field, password string, user *entity.User) error {
+	errReused := exception.NewWithErrors(exception.TypeValidationError, exception.CodePasswordReused, "Password was used recently",
+		exception.FieldErrors{field: {fmt.Sprintf("must not match the current or last %d passwords", p.HistorySize)}})
+
+	if user.Password != "" && shared.CheckPassword(password, user.Password) == nil {
+		return errReused
+	}
+
+	history, err := repo.PasswordHistory().FindRecent(ctx, user.ID, p.HistorySize)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range history {
+		if shared.CheckPassword(password, entry.PasswordHash) == nil {
+			return errReused
+		}
+	}
+
+	return nil
+}
+
+// Remember records a newly set password hash and drops entries beyond the history size.
+func (p *PasswordPolicy) Remember(ctx context.Context, repo mysqlrepository.MySQLRepository, userID uint, hash string) error {
+	if _, err := repo.PasswordHistory().Create(ctx, &entity.PasswordHistory{UserID: userID, PasswordHash: hash}); err != nil {
+		return err
+	}
+
+	return repo.PasswordHistory().Prune(ctx, userID, p.HistorySize)
+}
+
+// Expired reports whether the user's password is older than MaxAge. Users without a recorded
+// change time are never expired.
+func (p *PasswordPolicy) Expired(user *entity.User, now time.Time) bool {
+	if p.MaxAge <= 0 || user == nil || user.PasswordChangedAt == nil {
+		return false
+	}
+
+	return now.Sub(*user.PasswordChangedAt) > p.MaxAge
+}
+
+// passwordClasses counts the character classes present: lowercase, uppercase, digits and symbols.
+func passwordClasses(password string) int {
+	var lower, upper, digit, symbol int
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	return lower + upper + digit + symbol
+}
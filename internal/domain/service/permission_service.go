@@ -0,0 +1,208 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"goapptemp/config"
+	"goapptemp/constant"
+	"goapptemp/internal/adapter/repository"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	serror "goapptemp/internal/domain/service/error"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// permissionAcronyms keeps acronyms upper case in generated permission names.
+var permissionAcronyms = map[string]bool{"API": true}
+
+var _ PermissionService = (*permissionService)(nil)
+
+type PermissionService interface {
+	Find(ctx context.Context, req *FindPermissionsRequest) ([]*entity.Permission, int, error)
+	FindOne(ctx context.Context, req *FindOnePermissionRequest) (*entity.Permission, error)
+	FindGroups(ctx context.Context, req *FindPermissionGroupsRequest) ([]*entity.PermissionGroup, error)
+	Sync(ctx context.Context) error
+}
+
+// permissionService exposes the permission catalog read-only. The catalog itself is defined by
+// constant.PermissionCodes and written to the database by Sync on startup.
+type permissionService struct {
+	config *config.Config
+	repo   repository.Repository
+	logger logger.Logger
+	auth   AuthService
+}
+
+func NewPermissionService(config *config.Config, repo repository.Repository, logger logger.Logger, auth AuthService) *permissionService {
+	return &permissionService{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		auth:   auth,
+	}
+}
+
+type FindPermissionsRequest struct {
+	AuthParams *AuthParams
+	Filter     *mysqlrepository.FilterPermissionPayload
+}
+
+func (s *permissionService) Find(ctx context.Context, req *FindPermissionsRequest) ([]*entity.Permission, int, error) {
+	if err := s.authorize(ctx, req.AuthParams); err != nil {
+		return nil, 0, err
+	}
+
+	permissions, totalCount, err := s.repo.MySQL().Permission().Find(ctx, req.Filter)
+	if err != nil {
+		return nil, 0, serror.TranslateRepoError(err)
+	}
+
+	return permissions, totalCount, nil
+}
+
+type FindOnePermissionRequest struct {
+	AuthParams   *AuthParams
+	PermissionID uint
+}
+
+func (s *permissionService) FindOne(ctx context.Context, req *FindOnePermissionRequest) (*entity.Permission, error) {
+	if err := s.authorize(ctx, req.AuthParams); err != nil {
+		return nil, err
+	}
+
+	if req.PermissionID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Permission ID required for find one")
+	}
+
+	permission, err := s.repo.MySQL().Permission().FindByID(ctx, req.PermissionID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return permission, nil
+}
+
+type FindPermissionGroupsRequest struct {
+	AuthParams *AuthParams
+	Modules    []string
+}
+
+// FindGroups returns the whole catalog grouped by module, with modules and codes sorted.
+func (s *permissionService) FindGroups(ctx context.Context, req *FindPermissionGroupsRequest) ([]*entity.PermissionGroup, error) {
+	if err := s.authorize(ctx, req.AuthParams); err != nil {
+		return nil, err
+	}
+
+	permissions, _, err := s.repo.MySQL().Permission().Find(ctx, &mysqlrepository.FilterPermissionPayload{Modules: req.Modules})
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	slices.SortFunc(permissions, func(a, b *entity.Permission) int {
+		return cmp.Or(cmp.Compare(a.Module(), b.Module()), cmp.Compare(a.Code, b.Code))
+	})
+
+	groups := make([]*entity.PermissionGroup, 0)
+
+	for _, permission := range permissions {
+		if len(groups) == 0 || groups[len(groups)-1].Module != permission.Module() {
+			groups = append(groups, &entity.PermissionGroup{Module: permission.Module()})
+		}
+
+		group := groups[len(groups)-1]
+		group.Permissions = append(group.Permissions, permission)
+	}
+
+	return groups, nil
+}
+
+// Sync upserts every code in constant.PermissionCodes, so the catalog's names and descriptions win
+// over edits in the table and permissions that were soft-deleted come back. Codes that are no longer
+// in the catalog are only reported because roles may still reference them.
+func (s *permissionService) Sync(ctx context.Context) error {
+	existing, _, err := s.repo.MySQL().Permission().Find(ctx, &mysqlrepository.FilterPermissionPayload{})
+	if err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	for _, permission := range existing {
+		if _, ok := constant.PermissionCodes[permission.Code]; !ok {
+			s.logger.Warn().Msgf("Permission %s is not in the permission catalog", permission.Code)
+		}
+	}
+
+	codes := slices.Sorted(maps.Keys(constant.PermissionCodes))
+	permissions := make([]*entity.Permission, 0, len(codes))
+
+	for _, code := range codes {
+		description := permissionDescription(code)
+
+		permissions = append(permissions, &entity.Permission{
+			Code:        code,
+			Name:        permissionName(code),
+			Description: &description,
+		})
+	}
+
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		return txRepo.Permission().Upsert(ctx, permissions)
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	s.logger.Info().Msgf("Synced %d permissions from the permission catalog", len(permissions))
+
+	return nil
+}
+
+func (s *permissionService) authorize(ctx context.Context, authParams *AuthParams) error {
+	if authParams == nil || authParams.AccessTokenClaims == nil {
+		return exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, authParams.AccessTokenClaims.UserID, "PERMISSION.READ")
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	return nil
+}
+
+// permissionName turns a code such as API_KEY.READ into "API Key Read".
+func permissionName(code string) string {
+	words := strings.FieldsFunc(code, func(r rune) bool { return r == '.' || r == '_' })
+	for i, word := range words {
+		if !permissionAcronyms[word] {
+			words[i] = word[:1] + strings.ToLower(word[1:])
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// permissionDescription turns a code such as USER.SUSPEND into "Permission to suspend user".
+func permissionDescription(code string) string {
+	module, action, _ := strings.Cut(code, ".")
+
+	words := strings.Split(module, "_")
+	for i, word := range words {
+		if !permissionAcronyms[word] {
+			words[i] = strings.ToLower(word)
+		}
+	}
+
+	return "Permission to " + strings.ToLower(action) + " " + strings.Join(words, " ")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"goapptemp/config"
+	"goapptemp/constant"
 	"goapptemp/internal/adapter/pubsub"
 	"goapptemp/internal/shared/exception"
 	"goapptemp/pkg/logger"
@@ -58,7 +59,7 @@ func (s *pubsubService) SendToPublisher(ctx context.Context, image string, id ui
 
 	payloadJSON, _ := json.Marshal(payload)
 	msg := CommandMessage{
-		Command: "pub image",
+		Command: constant.PubsubCommandPubImage,
 		Payload: string(payloadJSON),
 		Detail:  userLog,
 	}
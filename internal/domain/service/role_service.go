@@ -2,15 +2,20 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"goapptemp/config"
+	"goapptemp/constant"
 	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
 	"goapptemp/internal/domain/entity"
 	"goapptemp/internal/shared/exception"
 	"goapptemp/pkg/logger"
+	"slices"
 
 	repo "goapptemp/internal/adapter/repository"
 
 	serror "goapptemp/internal/domain/service/error"
+
+	"github.com/cockroachdb/errors"
 )
 
 var _ RoleService = (*roleService)(nil)
@@ -19,8 +24,11 @@ type RoleService interface {
 	Create(ctx context.Context, req *CreateRoleRequest) (*entity.Role, error)
 	Update(ctx context.Context, req *UpdateRoleRequest) (*entity.Role, error)
 	Delete(ctx context.Context, req *DeleteRoleRequest) error
+	BulkDelete(ctx context.Context, req *BulkDeleteRolesRequest) ([]*BulkResult, error)
+	FindDependencies(ctx context.Context, req *FindRoleDependenciesRequest) ([]*entity.Dependency, error)
 	Find(ctx context.Context, req *FindRolesRequest) ([]*entity.Role, int, error)
 	FindOne(ctx context.Context, req *FindOneRoleRequest) (*entity.Role, error)
+	FindEffectivePermissions(ctx context.Context, req *FindRolePermissionsRequest) ([]*entity.EffectivePermission, error)
 }
 
 type roleService struct {
@@ -28,14 +36,16 @@ type roleService struct {
 	repo   repo.Repository
 	logger logger.Logger
 	auth   AuthService
+	events EventService
 }
 
-func NewRoleService(config *config.Config, repo repo.Repository, logger logger.Logger, auth AuthService) *roleService {
+func NewRoleService(config *config.Config, repo repo.Repository, logger logger.Logger, auth AuthService, events EventService) *roleService {
 	return &roleService{
 		config: config,
 		repo:   repo,
 		logger: logger,
 		auth:   auth,
+		events: events,
 	}
 }
 
@@ -62,6 +72,25 @@ func (s *roleService) Create(ctx context.Context, req *CreateRoleRequest) (*enti
 		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Role data cannot be nil")
 	}
 
+	scope, err := s.callerScope(ctx, req.AuthParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope != nil {
+		if req.Role.SuperAdmin {
+			return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Only super admins can manage super admin roles")
+		}
+
+		req.Role.CompanyID = scope
+	}
+
+	if req.Role.ParentID != nil {
+		if err := s.checkParent(ctx, req.Role, *req.Role.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
 	var role *entity.Role
 
 	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
@@ -90,6 +119,8 @@ func (s *roleService) Create(ctx context.Context, req *CreateRoleRequest) (*enti
 		return nil, serror.TranslateRepoError(err)
 	}
 
+	s.events.Publish(ctx, newRoleEvent(entity.EventRoleCreated, role, req.AuthParams.AccessTokenClaims.UserID))
+
 	return role, nil
 }
 
@@ -120,6 +151,30 @@ func (s *roleService) Update(ctx context.Context, req *UpdateRoleRequest) (*enti
 		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Role ID required for update")
 	}
 
+	scope, err := s.callerScope(ctx, req.AuthParams)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.MySQL().Role().FindByID(ctx, req.Update.ID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if !manageable(scope, current) {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	if scope != nil && req.Update.SuperAdmin != nil && *req.Update.SuperAdmin {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Only super admins can manage super admin roles")
+	}
+
+	if req.Update.ParentID != nil && *req.Update.ParentID != 0 {
+		if err := s.checkParent(ctx, current, *req.Update.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
 	var role *entity.Role
 
 	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
@@ -161,6 +216,13 @@ func (s *roleService) Update(ctx context.Context, req *UpdateRoleRequest) (*enti
 		return nil, serror.TranslateRepoError(err)
 	}
 
+	actorID := req.AuthParams.AccessTokenClaims.UserID
+	s.events.Publish(ctx, newRoleEvent(entity.EventRoleUpdated, role, actorID))
+
+	if req.Update.PermissionIDs != nil {
+		s.events.Publish(ctx, newRoleEvent(entity.EventRolePermissionsChanged, role, actorID))
+	}
+
 	return role, nil
 }
 
@@ -187,14 +249,163 @@ func (s *roleService) Delete(ctx context.Context, req *DeleteRoleRequest) error
 		return exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Role ID cannot be zero")
 	}
 
-	err = s.repo.MySQL().Role().Delete(ctx, req.RoleID)
+	scope, err := s.callerScope(ctx, req.AuthParams)
+	if err != nil {
+		return err
+	}
+
+	role, err := s.repo.MySQL().Role().FindByID(ctx, req.RoleID)
 	if err != nil {
 		return serror.TranslateRepoError(err)
 	}
 
+	if !manageable(scope, role) {
+		return exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		if err := checkDependencies(ctx, txRepo, "Role", txRepo.Role().GetTableName(), role.ID, false, "role_permissions"); err != nil {
+			return err
+		}
+
+		return txRepo.Role().Delete(ctx, role.ID)
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	s.events.Publish(ctx, newRoleEvent(entity.EventRoleDeleted, role, req.AuthParams.AccessTokenClaims.UserID))
+
 	return nil
 }
 
+type BulkDeleteRolesRequest struct {
+	AuthParams *AuthParams
+	RoleIDs    []uint
+}
+
+// BulkDelete deletes the roles in one transaction. Like Delete it refuses roles still held by users
+// or extended by other roles; the dependency check counts both in a single query.
+func (s *roleService) BulkDelete(ctx context.Context, req *BulkDeleteRolesRequest) ([]*BulkResult, error) {
+	if req.AuthParams.AccessTokenClaims == nil {
+		return nil, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, req.AuthParams.AccessTokenClaims.UserID, "ROLE.DELETE")
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	results, err := newBulkResults(req.RoleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := s.callerScope(ctx, req.AuthParams)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []*entity.Role
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		roles, _, err := txRepo.Role().Find(ctx, &mysqlrepository.FilterRolePayload{IDs: results.pending()})
+		if err != nil {
+			return err
+		}
+
+		found := make(map[uint]bool, len(roles))
+		for _, role := range roles {
+			found[role.ID] = true
+		}
+
+		results.failMissing(found, "Role")
+
+		for _, role := range roles {
+			if !manageable(scope, role) {
+				results.fail(role.ID, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access"))
+			}
+		}
+
+		if len(results.pending()) == 0 {
+			return nil
+		}
+
+		dependencies, err := txRepo.StoreProcedure().FindDependencies(ctx, txRepo.Role().GetTableName(), results.pending(), "role_permissions")
+		if err != nil {
+			return err
+		}
+
+		results.failDependent(dependencies, "Role")
+
+		deleted = make([]*entity.Role, 0, len(roles))
+
+		for _, role := range roles {
+			if !slices.Contains(results.pending(), role.ID) {
+				continue
+			}
+
+			if err := txRepo.Role().Delete(ctx, role.ID); err != nil {
+				return err
+			}
+
+			deleted = append(deleted, role)
+		}
+
+		return nil
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	for _, role := range deleted {
+		s.events.Publish(ctx, newRoleEvent(entity.EventRoleDeleted, role, req.AuthParams.AccessTokenClaims.UserID))
+	}
+
+	return results.list(), nil
+}
+
+type FindRoleDependenciesRequest struct {
+	AuthParams *AuthParams
+	RoleID     uint
+}
+
+// FindDependencies lists what keeps Delete and BulkDelete from deleting the role: user assignments
+// and child roles. Its own permission grants are removed with it and not reported.
+func (s *roleService) FindDependencies(ctx context.Context, req *FindRoleDependenciesRequest) ([]*entity.Dependency, error) {
+	if req.AuthParams.AccessTokenClaims == nil {
+		return nil, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, req.AuthParams.AccessTokenClaims.UserID, "ROLE.DELETE")
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	if req.RoleID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Role ID cannot be zero")
+	}
+
+	if _, err := s.repo.MySQL().Role().FindByID(ctx, req.RoleID); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	dependencies, err := s.repo.MySQL().StoreProcedure().FindDependencies(ctx, s.repo.MySQL().Role().GetTableName(), []uint{req.RoleID}, "role_permissions")
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return dependencies[req.RoleID], nil
+}
+
 type FindRolesRequest struct {
 	AuthParams *AuthParams
 	Filter     *mysqlrepository.FilterRolePayload
@@ -252,3 +463,187 @@ func (s *roleService) FindOne(ctx context.Context, req *FindOneRoleRequest) (*en
 
 	return role, nil
 }
+
+type FindRolePermissionsRequest struct {
+	AuthParams *AuthParams
+	RoleID     uint
+}
+
+// FindEffectivePermissions lists every permission the role holds, marking the ones inherited from
+// ancestors. A permission granted at several levels is attributed to the nearest role.
+func (s *roleService) FindEffectivePermissions(ctx context.Context, req *FindRolePermissionsRequest) ([]*entity.EffectivePermission, error) {
+	if req.AuthParams.AccessTokenClaims == nil {
+		return nil, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, req.AuthParams.AccessTokenClaims.UserID, "ROLE.READ")
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	if req.RoleID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Role ID cannot be zero")
+	}
+
+	role, err := s.repo.MySQL().Role().FindByID(ctx, req.RoleID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	ancestors, err := s.ancestors(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool)
+	effective := make([]*entity.EffectivePermission, 0, len(role.Permissions))
+
+	for i, source := range append([]*entity.Role{role}, ancestors...) {
+		for _, permission := range source.Permissions {
+			if permission == nil || seen[permission.ID] {
+				continue
+			}
+
+			seen[permission.ID] = true
+			effective = append(effective, &entity.EffectivePermission{
+				Permission:   permission,
+				SourceRoleID: source.ID,
+				Inherited:    i > 0,
+			})
+		}
+	}
+
+	return effective, nil
+}
+
+// callerScope returns the company whose roles the caller may write, or nil for super admins, who
+// manage global roles and the roles of every company.
+func (s *roleService) callerScope(ctx context.Context, authParams *AuthParams) (*uint, error) {
+	caller, err := s.repo.MySQL().User().FindByID(ctx, authParams.AccessTokenClaims.UserID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if caller.IsSuperAdmin() {
+		return nil, nil
+	}
+
+	return &caller.CompanyID, nil
+}
+
+// manageable reports whether a caller with the given scope may change or delete role.
+func manageable(scope *uint, role *entity.Role) bool {
+	return scope == nil || (!role.Global() && *role.CompanyID == *scope)
+}
+
+// checkParent validates parentID as the parent of role, which may not exist yet. Global roles may
+// only extend global roles and company roles may extend global roles or roles of their own company.
+// The parent chain must stay acyclic and, including the role's own descendants, within
+// constant.MaxRoleDepth.
+func (s *roleService) checkParent(ctx context.Context, role *entity.Role, parentID uint) error {
+	invalid := func(message string) error {
+		return exception.NewWithErrors(exception.TypeValidationError, exception.CodeValidationFailed, message,
+			exception.FieldErrors{"parent_id": {message}})
+	}
+
+	if role.ID != 0 && parentID == role.ID {
+		return invalid("Role cannot be its own parent")
+	}
+
+	parent, err := s.repo.MySQL().Role().FindByID(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, exception.ErrNotFound) {
+			return invalid("Parent role does not exist")
+		}
+
+		return serror.TranslateRepoError(err)
+	}
+
+	if parent.SuperAdmin {
+		return invalid("Super admin roles cannot be extended")
+	}
+
+	if !parent.Global() && (role.Global() || *parent.CompanyID != *role.CompanyID) {
+		return invalid("Parent role must be a global role or belong to the same company")
+	}
+
+	ancestors, err := s.ancestors(ctx, parent)
+	if err != nil {
+		return err
+	}
+
+	for _, ancestor := range ancestors {
+		if role.ID != 0 && ancestor.ID == role.ID {
+			return invalid("Parent role would create a cycle in the role hierarchy")
+		}
+	}
+
+	height := 1
+	if role.ID != 0 {
+		if height, err = s.subtreeHeight(ctx, role.ID); err != nil {
+			return err
+		}
+	}
+
+	if depth := len(ancestors) + 1 + height; depth > constant.MaxRoleDepth {
+		return invalid(fmt.Sprintf("Role hierarchy cannot be deeper than %d levels", constant.MaxRoleDepth))
+	}
+
+	return nil
+}
+
+// ancestors returns the parent chain of role, nearest first. It stops at a repeated role or after
+// constant.MaxRoleDepth levels, so hierarchies broken outside this service cannot loop.
+func (s *roleService) ancestors(ctx context.Context, role *entity.Role) ([]*entity.Role, error) {
+	seen := map[uint]bool{role.ID: true}
+
+	var ancestors []*entity.Role
+
+	for current := role; current.ParentID != nil && len(ancestors) < constant.MaxRoleDepth; {
+		if seen[*current.ParentID] {
+			break
+		}
+
+		parent, err := s.repo.MySQL().Role().FindByID(ctx, *current.ParentID)
+		if err != nil {
+			return nil, serror.TranslateRepoError(err)
+		}
+
+		seen[parent.ID] = true
+		ancestors = append(ancestors, parent)
+		current = parent
+	}
+
+	return ancestors, nil
+}
+
+// subtreeHeight counts the levels from roleID down to its deepest descendant, itself included.
+func (s *roleService) subtreeHeight(ctx context.Context, roleID uint) (int, error) {
+	height := 0
+	seen := make(map[uint]bool)
+
+	for level := []uint{roleID}; len(level) > 0 && height <= constant.MaxRoleDepth; height++ {
+		children, _, err := s.repo.MySQL().Role().Find(ctx, &mysqlrepository.FilterRolePayload{ParentIDs: level})
+		if err != nil {
+			return 0, serror.TranslateRepoError(err)
+		}
+
+		for _, id := range level {
+			seen[id] = true
+		}
+
+		level = level[:0:0]
+
+		for _, child := range children {
+			if !seen[child.ID] {
+				level = append(level, child.ID)
+			}
+		}
+	}
+
+	return height, nil
+}
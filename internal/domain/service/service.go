@@ -7,8 +7,10 @@ import (
 	"goapptemp/internal/adapter/repository"
 	"goapptemp/internal/shared"
 	"goapptemp/internal/shared/token"
-	"goapptemp/pkg/gmailsender"
 	"goapptemp/pkg/logger"
+	"goapptemp/pkg/scheduler"
+	"goapptemp/pkg/taskqueue"
+	"goapptemp/pkg/webhooksender"
 )
 
 var _ Service = (*service)(nil)
@@ -16,31 +18,57 @@ var _ Service = (*service)(nil)
 type Service interface {
 	Token() token.Token
 	Auth() AuthService
+	SSO() SSOService
 	User() UserService
+	Invitation() InvitationService
+	Impersonation() ImpersonationService
 	Client() ClientService
 	Role() RoleService
+	Permission() PermissionService
 	SupportFeature() SupportFeatureService
 	Province() ProvinceService
 	City() CityService
 	District() DistrictService
 	Notification() NotificationService
+	EmailTemplate() EmailTemplateService
 	Webhook() WebhookService
-	StaleTaskDetector() StaleTaskDetector
+	Consumer() ConsumerService
+	WebhookSubscription() WebhookSubscriptionService
+	Job() JobService
+	Lockout() LockoutService
+	APIKey() APIKeyService
+	Scheduler() scheduler.Scheduler
+	TaskQueue() taskqueue.Queue
+	LoginPolicy() *LoginPolicy
+	WebhookDeliveryWorker() WebhookDeliveryWorker
 }
 
 type service struct {
-	tokenManager          token.Token
-	authService           AuthService
-	userService           UserService
-	clientService         ClientService
-	roleService           RoleService
-	supportFeatureService SupportFeatureService
-	webhookService        WebhookService
-	provinceService       ProvinceService
-	cityService           CityService
-	districtService       DistrictService
-	staleTaskDetector     StaleTaskDetector
-	notificationService   NotificationService
+	tokenManager               token.Token
+	authService                AuthService
+	ssoService                 SSOService
+	userService                UserService
+	invitationService          InvitationService
+	impersonationService       ImpersonationService
+	clientService              ClientService
+	roleService                RoleService
+	permissionService          PermissionService
+	supportFeatureService      SupportFeatureService
+	webhookService             WebhookService
+	consumerService            ConsumerService
+	webhookSubscriptionService WebhookSubscriptionService
+	provinceService            ProvinceService
+	cityService                CityService
+	districtService            DistrictService
+	jobService                 JobService
+	lockoutService             LockoutService
+	apiKeyService              APIKeyService
+	jobScheduler               scheduler.Scheduler
+	taskQueue                  taskqueue.Queue
+	loginPolicy                *LoginPolicy
+	webhookDeliveryWorker      WebhookDeliveryWorker
+	notificationService        NotificationService
+	emailTemplateService       EmailTemplateService
 }
 
 func NewService(
@@ -49,33 +77,93 @@ func NewService(
 	logger logger.Logger,
 	token token.Token,
 	publisher pubsub.Publisher,
+	eventPublishers map[string]pubsub.Publisher,
 ) (*service, error) {
 	validate, err := shared.NewValidator()
 	if err != nil {
 		return nil, err
 	}
 
-	gmailSender, err := gmailsender.NewGmailSender(context.Background(), config.Gmail.CredFile, config.Gmail.CredFile, config.Gmail.Sender)
+	emailSender, err := NewEmailSender(context.Background(), config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	emailTemplates, err := NewEmailTemplateRegistry(config)
 	if err != nil {
 		return nil, err
 	}
 
-	notifService := NewNotificationService(gmailSender, logger)
 	pubsubService := NewPubsubService(config, logger, publisher)
-	authService := NewAuthService(config, token, repo, logger, notifService)
+	loginPolicy, err := NewLoginPolicy(config.BruteForce)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordPolicy, err := NewPasswordPolicy(config.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	accessPolicy, err := NewAccessPolicy(config.Policy)
+	if err != nil {
+		return nil, err
+	}
+
+	taskQueue := NewTaskQueue(config, repo, logger)
+	authService := NewAuthService(config, token, repo, logger, taskQueue, loginPolicy, passwordPolicy)
+	if err := authService.registerTasks(taskQueue); err != nil {
+		return nil, err
+	}
+
+	webhookService := NewWebhookService(config, repo, logger)
+	webhookSubscriptionService := NewWebhookSubscriptionService(config, repo, logger, authService)
+	eventService := NewEventService(config, logger, eventPublishers, webhookSubscriptionService, taskQueue)
+	if err := eventService.registerTasks(taskQueue); err != nil {
+		return nil, err
+	}
+	ssoService, err := NewSSOService(config, token, repo, logger, eventService)
+	if err != nil {
+		return nil, err
+	}
+
+	notifService := NewNotificationService(config, repo, logger, emailSender, emailTemplates, eventService, taskQueue)
+	if err := notifService.registerTasks(taskQueue); err != nil {
+		return nil, err
+	}
+
+	jobScheduler := scheduler.NewScheduler(repo.Redis(), logger)
+	if err := jobScheduler.Register(NewStaleIconJob(config, repo, logger)); err != nil {
+		return nil, err
+	}
+
+	webhookSender := webhooksender.NewWebhookSender(secondsOr(config.Webhook.Timeout, defaultWebhookTimeout), config.App.Name)
 
 	return &service{
-		authService:           authService,
-		userService:           NewUserService(config, repo, logger, authService),
-		clientService:         NewClientService(config, repo, logger, authService, pubsubService),
-		roleService:           NewRoleService(config, repo, logger, authService),
-		supportFeatureService: NewSupportFeatureService(config, repo, logger, authService, validate),
-		provinceService:       NewProvinceService(config, repo, logger, authService),
-		cityService:           NewCityService(config, repo, logger, authService),
-		districtService:       NewDistrictService(config, repo, logger, authService),
-		staleTaskDetector:     NewStaleTaskDetector(config, repo, logger),
-		webhookService:        NewWebhookService(config, repo, logger),
-		notificationService:   notifService,
+		authService:                authService,
+		ssoService:                 ssoService,
+		userService:                NewUserService(config, repo, logger, authService, eventService, notifService, passwordPolicy, accessPolicy),
+		invitationService:          NewInvitationService(config, repo, logger, authService, eventService, taskQueue, passwordPolicy, validate, accessPolicy),
+		impersonationService:       NewImpersonationService(config, token, repo, logger, eventService),
+		clientService:              NewClientService(config, repo, logger, authService, pubsubService, eventService, accessPolicy),
+		roleService:                NewRoleService(config, repo, logger, authService, eventService),
+		permissionService:          NewPermissionService(config, repo, logger, authService),
+		supportFeatureService:      NewSupportFeatureService(config, repo, logger, authService, validate),
+		provinceService:            NewProvinceService(config, repo, logger, authService),
+		cityService:                NewCityService(config, repo, logger, authService),
+		districtService:            NewDistrictService(config, repo, logger, authService),
+		jobService:                 NewJobService(config, logger, authService, jobScheduler),
+		lockoutService:             NewLockoutService(config, repo, logger, authService),
+		apiKeyService:              NewAPIKeyService(config, repo, logger, authService),
+		jobScheduler:               jobScheduler,
+		taskQueue:                  taskQueue,
+		loginPolicy:                loginPolicy,
+		webhookService:             webhookService,
+		consumerService:            NewConsumerService(config, logger, webhookService),
+		webhookSubscriptionService: webhookSubscriptionService,
+		webhookDeliveryWorker:      NewWebhookDeliveryWorker(config, repo, logger, webhookSender),
+		notificationService:        notifService,
+		emailTemplateService:       NewEmailTemplateService(config, logger, authService, emailTemplates),
 	}, nil
 }
 
@@ -87,10 +175,22 @@ func (s *service) Auth() AuthService {
 	return s.authService
 }
 
+func (s *service) SSO() SSOService {
+	return s.ssoService
+}
+
 func (s *service) User() UserService {
 	return s.userService
 }
 
+func (s *service) Invitation() InvitationService {
+	return s.invitationService
+}
+
+func (s *service) Impersonation() ImpersonationService {
+	return s.impersonationService
+}
+
 func (s *service) Client() ClientService {
 	return s.clientService
 }
@@ -99,6 +199,10 @@ func (s *service) Role() RoleService {
 	return s.roleService
 }
 
+func (s *service) Permission() PermissionService {
+	return s.permissionService
+}
+
 func (s *service) SupportFeature() SupportFeatureService {
 	return s.supportFeatureService
 }
@@ -119,10 +223,46 @@ func (s *service) Notification() NotificationService {
 	return s.notificationService
 }
 
+func (s *service) EmailTemplate() EmailTemplateService {
+	return s.emailTemplateService
+}
+
 func (s *service) Webhook() WebhookService {
 	return s.webhookService
 }
 
-func (s *service) StaleTaskDetector() StaleTaskDetector {
-	return s.staleTaskDetector
+func (s *service) Consumer() ConsumerService {
+	return s.consumerService
+}
+
+func (s *service) Job() JobService {
+	return s.jobService
+}
+
+func (s *service) Lockout() LockoutService {
+	return s.lockoutService
+}
+
+func (s *service) APIKey() APIKeyService {
+	return s.apiKeyService
+}
+
+func (s *service) Scheduler() scheduler.Scheduler {
+	return s.jobScheduler
+}
+
+func (s *service) TaskQueue() taskqueue.Queue {
+	return s.taskQueue
+}
+
+func (s *service) LoginPolicy() *LoginPolicy {
+	return s.loginPolicy
+}
+
+func (s *service) WebhookSubscription() WebhookSubscriptionService {
+	return s.webhookSubscriptionService
+}
+
+func (s *service) WebhookDeliveryWorker() WebhookDeliveryWorker {
+	return s.webhookDeliveryWorker
 }
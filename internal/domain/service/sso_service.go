@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"goapptemp/config"
+	"goapptemp/internal/adapter/repository"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	serror "goapptemp/internal/domain/service/error"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/internal/shared/token"
+	"goapptemp/pkg/logger"
+	"goapptemp/pkg/metrics"
+	"goapptemp/pkg/oidc"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const defaultSSOStateTTL = 10 * time.Minute
+
+var _ SSOService = (*ssoService)(nil)
+
+type SSOService interface {
+	Providers() []*entity.SSOProvider
+	Authorize(ctx context.Context, req *SSOAuthorizeRequest) (string, error)
+	Callback(ctx context.Context, req *SSOCallbackRequest) (*entity.User, error)
+}
+
+type ssoProvider struct {
+	config *config.OIDCProviderConfig
+	client oidc.Provider
+}
+
+type ssoService struct {
+	config     *config.Config
+	token      token.Token
+	repository repository.Repository
+	logger     logger.Logger
+	events     EventService
+	providers  map[string]*ssoProvider
+	order      []string
+}
+
+func NewSSOService(
+	config *config.Config,
+	token token.Token,
+	repo repository.Repository,
+	logger logger.Logger,
+	events EventService,
+) (*ssoService, error) {
+	s := &ssoService{
+		config:     config,
+		token:      token,
+		repository: repo,
+		logger:     logger,
+		events:     events,
+		providers:  make(map[string]*ssoProvider),
+	}
+
+	for _, providerConfig := range config.OIDC.Providers {
+		if providerConfig.IssuerURL == "" || providerConfig.ClientID == "" || providerConfig.RedirectURL == "" || providerConfig.CompanyID == 0 {
+			return nil, errors.Newf("oidc provider %q needs an issuer URL, client ID, redirect URL and company ID", providerConfig.Name)
+		}
+
+		if _, exists := s.providers[providerConfig.Name]; exists {
+			return nil, errors.Newf("oidc provider %q is configured twice", providerConfig.Name)
+		}
+
+		s.providers[providerConfig.Name] = &ssoProvider{
+			config: providerConfig,
+			client: oidc.NewProvider(oidc.Config{
+				IssuerURL:    providerConfig.IssuerURL,
+				ClientID:     providerConfig.ClientID,
+				ClientSecret: providerConfig.ClientSecret,
+				RedirectURL:  providerConfig.RedirectURL,
+				Scopes:       providerConfig.Scopes,
+			}),
+		}
+		s.order = append(s.order, providerConfig.Name)
+	}
+
+	return s, nil
+}
+
+func (s *ssoService) Providers() []*entity.SSOProvider {
+	providers := make([]*entity.SSOProvider, 0, len(s.order))
+
+	for _, name := range s.order {
+		displayName := s.providers[name].config.DisplayName
+		if displayName == "" {
+			displayName = name
+		}
+
+		providers = append(providers, &entity.SSOProvider{Name: name, DisplayName: displayName})
+	}
+
+	return providers
+}
+
+type SSOAuthorizeRequest struct {
+	Provider string
+}
+
+// Authorize starts an authorization code flow with PKCE and returns the URL to send the browser to.
+func (s *ssoService) Authorize(ctx context.Context, req *SSOAuthorizeRequest) (string, error) {
+	provider, err := s.provider(req.Provider)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to generate SSO state")
+	}
+
+	nonce, err := randomToken()
+	if err != nil {
+		return "", exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to generate SSO nonce")
+	}
+
+	verifier := oidc.NewVerifier()
+
+	authURL, err := provider.client.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return "", exception.Wrap(err, exception.TypeServiceUnavailable, exception.CodeServiceUnavailable, "Identity provider is unavailable")
+	}
+
+	payload := &entity.SSOState{Provider: provider.config.Name, Nonce: nonce, Verifier: verifier}
+	if err := s.repository.Redis().StoreSSOState(ctx, state, payload, secondsOr(s.config.OIDC.StateTTL, defaultSSOStateTTL)); err != nil {
+		return "", serror.TranslateRepoError(err)
+	}
+
+	return authURL, nil
+}
+
+type SSOCallbackRequest struct {
+	Provider string
+	Code     string
+	State    string
+}
+
+// Callback completes the flow and logs the user in with the application's own token pair. Users
+// are matched by their linked identity first, then by verified email within the provider's company,
+// and are provisioned on the fly when the provider allows it.
+func (s *ssoService) Callback(ctx context.Context, req *SSOCallbackRequest) (user *entity.User, err error) {
+	defer func() { metrics.ObserveLogin(metrics.LoginSSO, err == nil) }()
+
+	provider, err := s.provider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.repository.Redis().ConsumeSSOState(ctx, req.State)
+	if err != nil {
+		if errors.Is(err, exception.ErrNotFound) {
+			return nil, exception.New(exception.TypeBadRequest, "INVALID_SSO_STATE", "Invalid or expired SSO state")
+		}
+
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if state.Provider != provider.config.Name {
+		return nil, exception.New(exception.TypeBadRequest, "INVALID_SSO_STATE", "Invalid or expired SSO state")
+	}
+
+	claims, err := provider.client.Exchange(ctx, req.Code, state.Verifier, state.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrDiscovery) {
+			return nil, exception.Wrap(err, exception.TypeServiceUnavailable, exception.CodeServiceUnavailable, "Identity provider is unavailable")
+		}
+
+		return nil, exception.Wrap(err, exception.TypeUnauthorized, exception.CodeUnauthorized, "SSO login failed")
+	}
+
+	email := strings.TrimSpace(claims.Email)
+	if claims.Subject == "" || email == "" || !bool(claims.EmailVerified) {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Identity provider did not assert a verified email")
+	}
+
+	user, err = s.resolveUser(ctx, provider.config, claims, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status != entity.UserStatusActive {
+		return nil, exception.ErrUserInactive
+	}
+
+	user.Token, err = issueToken(s.token, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *ssoService) resolveUser(ctx context.Context, provider *config.OIDCProviderConfig, claims *oidc.Claims, email string) (*entity.User, error) {
+	identity, err := s.repository.MySQL().UserIdentity().FindByProviderSubject(ctx, provider.Name, claims.Subject)
+	if err == nil {
+		if err := s.repository.MySQL().UserIdentity().TouchLogin(ctx, identity.ID, email, time.Now()); err != nil {
+			s.logger.Warn().Err(err).Msgf("Failed to record SSO login for identity %d", identity.ID)
+		}
+
+		user, err := s.repository.MySQL().User().FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, serror.TranslateRepoError(err)
+		}
+
+		return user, nil
+	}
+
+	if !errors.Is(err, exception.ErrNotFound) {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	users, _, err := s.repository.MySQL().User().Find(ctx, &mysqlrepository.FilterUserPayload{
+		CompanyIDs: []uint{provider.CompanyID},
+		Emails:     []string{email},
+	})
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	var userID uint
+
+	switch {
+	case len(users) > 0 && users[0] != nil:
+		userID = users[0].ID
+		if err := s.link(ctx, s.repository.MySQL(), provider, claims, email, userID); err != nil {
+			return nil, serror.TranslateRepoError(err)
+		}
+
+		s.logger.Info().Msgf("Linked %s identity to existing user %d", provider.Name, userID)
+	case provider.AutoProvision:
+		userID, err = s.provision(ctx, provider, claims, email)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info().Msgf("Provisioned user %d from %s login", userID, provider.Name)
+	default:
+		return nil, exception.New(exception.TypeForbidden, exception.CodeUserNotFound, "No account exists for this email")
+	}
+
+	user, err := s.repository.MySQL().User().FindByID(ctx, userID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return user, nil
+}
+
+// provision creates the user with the provider's default role. The random password is never
+// shared, so the account can only log in through SSO until someone resets it.
+func (s *ssoService) provision(ctx context.Context, provider *config.OIDCProviderConfig, claims *oidc.Claims, email string) (uint, error) {
+	fullname := strings.TrimSpace(claims.Name)
+	if fullname == "" {
+		fullname = email
+	}
+
+	password, err := randomToken()
+	if err != nil {
+		return 0, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to generate user password")
+	}
+
+	now := time.Now()
+	newUser := &entity.User{
+		CompanyID:         provider.CompanyID,
+		Fullname:          fullname,
+		Username:          email,
+		Email:             email,
+		PasswordChangedAt: &now,
+	}
+	if err := newUser.SetPassword(password); err != nil {
+		return 0, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to hash user password during provisioning")
+	}
+
+	var user *entity.User
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		var err error
+
+		user, err = txRepo.User().Create(ctx, newUser)
+		if err != nil {
+			return err
+		}
+
+		if provider.DefaultRoleID != 0 {
+			if _, err = txRepo.User().AttachRoles(ctx, user.ID, []uint{provider.DefaultRoleID}); err != nil {
+				return err
+			}
+		}
+
+		return s.link(ctx, txRepo, provider, claims, email, user.ID)
+	}
+	if err := s.repository.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return 0, serror.TranslateRepoError(err)
+	}
+
+	s.events.Publish(ctx, newUserEvent(entity.EventUserCreated, user, user.ID))
+
+	return user.ID, nil
+}
+
+func (s *ssoService) link(ctx context.Context, repo mysqlrepository.MySQLRepository, provider *config.OIDCProviderConfig, claims *oidc.Claims, email string, userID uint) error {
+	now := time.Now()
+
+	_, err := repo.UserIdentity().Create(ctx, &entity.UserIdentity{
+		UserID:      userID,
+		Provider:    provider.Name,
+		Subject:     claims.Subject,
+		Email:       email,
+		LastLoginAt: &now,
+	})
+
+	return err
+}
+
+func (s *ssoService) provider(name string) (*ssoProvider, error) {
+	provider, ok := s.providers[strings.ToLower(name)]
+	if !ok {
+		return nil, exception.New(exception.TypeNotFound, exception.CodeNotFound, "SSO provider not found")
+	}
+
+	return provider, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
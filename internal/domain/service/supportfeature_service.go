@@ -34,6 +34,7 @@ type SupportFeatureService interface {
 	Find(ctx context.Context, req *FindSupportFeaturesRequest) ([]*entity.SupportFeature, int, error)
 	FindOne(ctx context.Context, req *FindOneSupportFeatureRequest) (*entity.SupportFeature, error)
 	IsDeletable(ctx context.Context, req *IsDeletableSupportFeatureRequest) (bool, error)
+	FindDependencies(ctx context.Context, req *FindSupportFeatureDependenciesRequest) ([]*entity.Dependency, error)
 	ImportPreview(ctx context.Context, req *ImportPreviewSupportFeatureRequest) ([]*SupportFeaturePreview, error)
 	TemplateImport(ctx context.Context, req *TemplateImportSupportFeatureRequest) (*FileServiceData, error)
 }
@@ -354,6 +355,8 @@ func (s *supportFeatureService) Update(ctx context.Context, req *UpdateSupportFe
 type DeleteSupportFeatureRequest struct {
 	AuthParams       *AuthParams
 	SupportFeatureID uint
+	// Cascade soft-deletes dependent rows that support it instead of refusing the deletion.
+	Cascade bool
 }
 
 func (s *supportFeatureService) Delete(ctx context.Context, req *DeleteSupportFeatureRequest) error {
@@ -377,15 +380,10 @@ func (s *supportFeatureService) Delete(ctx context.Context, req *DeleteSupportFe
 	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
 		sfTable := txRepo.SupportFeature().GetTableName()
 
-		dependencyMap, err := txRepo.StoreProcedure().CheckIfRecordsAreDeletable(ctx, sfTable, []uint{req.SupportFeatureID}, "")
-		if err != nil {
+		if err := checkDependencies(ctx, txRepo, "Help service", sfTable, req.SupportFeatureID, req.Cascade); err != nil {
 			return err
 		}
 
-		if count, found := dependencyMap[req.SupportFeatureID]; found && count > 0 {
-			return exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Help service is not deletable due to existing dependencies")
-		}
-
 		if err := txRepo.SupportFeature().Delete(ctx, req.SupportFeatureID); err != nil {
 			return err
 		}
@@ -424,7 +422,7 @@ func (s *supportFeatureService) IsDeletable(ctx context.Context, req *IsDeletabl
 
 	sfTable := s.repo.MySQL().SupportFeature().GetTableName()
 
-	dependencyMap, err := s.repo.MySQL().StoreProcedure().CheckIfRecordsAreDeletable(ctx, sfTable, []uint{req.SupportFeatureID}, "")
+	dependencyMap, err := s.repo.MySQL().StoreProcedure().CheckIfRecordsAreDeletable(ctx, sfTable, []uint{req.SupportFeatureID})
 	if err != nil {
 		return false, serror.TranslateRepoError(err)
 	}
@@ -436,6 +434,44 @@ func (s *supportFeatureService) IsDeletable(ctx context.Context, req *IsDeletabl
 	return true, nil
 }
 
+type FindSupportFeatureDependenciesRequest struct {
+	AuthParams       *AuthParams
+	SupportFeatureID uint
+}
+
+// FindDependencies explains IsDeletable: it lists the tables still referencing the help service.
+func (s *supportFeatureService) FindDependencies(ctx context.Context, req *FindSupportFeatureDependenciesRequest) ([]*entity.Dependency, error) {
+	if req.AuthParams.AccessTokenClaims == nil {
+		return nil, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, req.AuthParams.AccessTokenClaims.UserID, "HELP_SERVICE.DELETE")
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	if req.SupportFeatureID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Help service ID cannot be zero")
+	}
+
+	if _, err := s.repo.MySQL().SupportFeature().FindByID(ctx, req.SupportFeatureID); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	sfTable := s.repo.MySQL().SupportFeature().GetTableName()
+
+	dependencies, err := s.repo.MySQL().StoreProcedure().FindDependencies(ctx, sfTable, []uint{req.SupportFeatureID})
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return dependencies[req.SupportFeatureID], nil
+}
+
 type TemplateImportSupportFeatureRequest struct {
 	AuthParams *AuthParams
 	File       *multipart.FileHeader
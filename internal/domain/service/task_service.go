@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"goapptemp/config"
 	"goapptemp/pkg/logger"
+	"goapptemp/pkg/scheduler"
+	"goapptemp/pkg/taskqueue"
 	"time"
 
 	repo "goapptemp/internal/adapter/repository"
@@ -11,54 +14,48 @@ import (
 	apm "go.elastic.co/apm/v2"
 )
 
-var _ StaleTaskDetector = (*staleTaskDetector)(nil)
-
-type StaleTaskDetector interface {
-	Start(ctx context.Context)
-}
-
-type staleTaskDetector struct {
-	config *config.Config
-	repo   repo.Repository
-	logger logger.Logger
-}
-
-func NewStaleTaskDetector(config *config.Config, repo repo.Repository, logger logger.Logger) *staleTaskDetector {
-	return &staleTaskDetector{
-		config: config,
-		repo:   repo,
-		logger: logger,
-	}
-}
-
-func (d *staleTaskDetector) Start(ctx context.Context) {
-	ticker := time.NewTicker(time.Duration(d.config.StaleTask.CheckInterval) * time.Second)
-	defer ticker.Stop()
+const (
+	defaultStaleTaskMaxStaleTime  = 30 * time.Second
+	defaultStaleTaskCheckInterval = time.Minute
+)
 
-	for {
-		select {
-		case <-ticker.C:
-			d.checkAndProcessStaleTasks(ctx)
-		case <-ctx.Done():
-			d.logger.Info().Msg("Stale task detector stopping due to context cancellation.")
-			return
-		}
+// NewStaleIconJob marks client icons that have been "loading" for longer than StaleTask.MaxStaleTime as failed.
+func NewStaleIconJob(config *config.Config, repo repo.Repository, logger logger.Logger) scheduler.Job {
+	maxStaleTime := secondsOr(config.StaleTask.MaxStaleTime, defaultStaleTaskMaxStaleTime)
+	checkInterval := secondsOr(config.StaleTask.CheckInterval, defaultStaleTaskCheckInterval)
+
+	return scheduler.Job{
+		Name:        "client.stale_icons",
+		Description: "Mark client icons stuck in loading as failed",
+		Schedule:    fmt.Sprintf("@every %s", checkInterval),
+		Timeout:     checkInterval,
+		Run: func(ctx context.Context) error {
+			span, ctx := apm.StartSpan(ctx, "StaleIconJob.Run", "task")
+			defer span.End()
+
+			if err := repo.MySQL().Client().UpdateStaleIcons(ctx, maxStaleTime); err != nil {
+				logger.Error().Err(err).Msg("Failed to update stale icons")
+				return err
+			}
+
+			return nil
+		},
 	}
 }
 
-func (d *staleTaskDetector) checkAndProcessStaleTasks(ctx context.Context) {
-	span, ctx := apm.StartSpan(ctx, "StaleTaskDetector.checkAndProcessStaleTasks", "task")
-	defer span.End()
-
-	err := d.repo.MySQL().Client().UpdateStaleIcons(ctx)
-	if err != nil {
-		if apmErr := apm.CaptureError(ctx, err); apmErr != nil {
-			apmErr.Handled = true
-			apmErr.Send()
-		}
-
-		d.logger.Error().Err(err).Msg("Failed to update stale icons")
-	}
-
-	d.logger.Info().Msg("Stale task check completed")
+// NewTaskQueue builds the Redis-backed queue for side effects that must survive restarts and transient failures.
+// Unset config values fall back to the queue's own defaults.
+func NewTaskQueue(config *config.Config, repo repo.Repository, logger logger.Logger) taskqueue.Queue {
+	cfg := config.TaskQueue
+
+	return taskqueue.NewQueue(repo.Redis(), logger,
+		taskqueue.WithName(cfg.Name),
+		taskqueue.WithWorkers(cfg.Workers),
+		taskqueue.WithMaxAttempts(cfg.MaxAttempts),
+		taskqueue.WithVisibilityTimeout(time.Duration(cfg.VisibilityTimeout)*time.Second),
+		taskqueue.WithPollInterval(time.Duration(cfg.PollInterval)*time.Millisecond),
+		taskqueue.WithBackoff(time.Duration(cfg.BackoffBase)*time.Second, time.Duration(cfg.BackoffMax)*time.Second),
+		taskqueue.WithDrainTimeout(time.Duration(cfg.DrainTimeout)*time.Second),
+		taskqueue.WithDeadLetterSize(cfg.DeadLetterSize),
+	)
 }
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	serror "goapptemp/internal/domain/service/error"
+	"goapptemp/internal/shared/exception"
+	"slices"
+	"time"
+)
+
+type BulkDeleteUsersRequest struct {
+	AuthParams *AuthParams
+	UserIDs    []uint
+}
+
+// BulkDelete deletes the users in one transaction. IDs that are missing, the caller's own, denied
+// by the access policies or still referenced are reported and skipped; the others are deleted
+// together.
+func (s *userService) BulkDelete(ctx context.Context, req *BulkDeleteUsersRequest) ([]*BulkResult, error) {
+	if err := s.authorize(ctx, req.AuthParams, "USER.DELETE"); err != nil {
+		return nil, err
+	}
+
+	results, err := newBulkResults(req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	actorID := req.AuthParams.AccessTokenClaims.UserID
+	results.fail(actorID, exception.New(exception.TypeForbidden, exception.CodeForbidden, "User cannot delete their own account"))
+
+	var deleted []*entity.User
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		users, err := s.bulkUsers(ctx, txRepo, req.AuthParams, results, PolicyActionDelete)
+		if err != nil {
+			return err
+		}
+
+		if len(results.pending()) == 0 {
+			return nil
+		}
+
+		dependencies, err := txRepo.StoreProcedure().FindDependencies(ctx, txRepo.User().GetTableName(), results.pending(), userOwnedTables...)
+		if err != nil {
+			return err
+		}
+
+		results.failDependent(dependencies, "User")
+
+		deleted = make([]*entity.User, 0, len(users))
+
+		for _, user := range users {
+			if !slices.Contains(results.pending(), user.ID) {
+				continue
+			}
+
+			if err := txRepo.User().Delete(ctx, user.ID); err != nil {
+				return err
+			}
+
+			deleted = append(deleted, user)
+		}
+
+		return nil
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	for _, user := range deleted {
+		s.events.Publish(ctx, newUserEvent(entity.EventUserDeleted, user, actorID))
+	}
+
+	return results.list(), nil
+}
+
+type BulkSetUserStatusRequest struct {
+	AuthParams *AuthParams
+	UserIDs    []uint
+	// Reason is recorded on suspended users and ignored when reactivating.
+	Reason string
+}
+
+// BulkSuspend suspends the active users among the IDs and revokes their tokens once the
+// transaction has committed.
+func (s *userService) BulkSuspend(ctx context.Context, req *BulkSetUserStatusRequest) ([]*BulkResult, error) {
+	if err := s.authorize(ctx, req.AuthParams, "USER.SUSPEND"); err != nil {
+		return nil, err
+	}
+
+	results, err := newBulkResults(req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	actorID := req.AuthParams.AccessTokenClaims.UserID
+	results.fail(actorID, exception.New(exception.TypeForbidden, exception.CodeForbidden, "User cannot suspend their own account"))
+
+	now := time.Now()
+
+	var suspended []*entity.User
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		users, err := s.bulkUsers(ctx, txRepo, req.AuthParams, results, PolicyActionUpdate)
+		if err != nil {
+			return err
+		}
+
+		suspended = make([]*entity.User, 0, len(users))
+
+		for _, user := range users {
+			if user.Status != entity.UserStatusActive {
+				results.fail(user.ID, exception.Newf(exception.TypeConflict, exception.CodeConflict, "Only active users can be suspended, user is %s", user.Status))
+				continue
+			}
+
+			err := txRepo.User().SetStatus(ctx, &mysqlrepository.SetUserStatusPayload{
+				ID:               user.ID,
+				Status:           entity.UserStatusSuspended,
+				SuspendedAt:      &now,
+				SuspensionReason: req.Reason,
+			})
+			if err != nil {
+				return err
+			}
+
+			user.Status = entity.UserStatusSuspended
+			user.SuspendedAt = &now
+			user.SuspensionReason = req.Reason
+			suspended = append(suspended, user)
+		}
+
+		return nil
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	for _, user := range suspended {
+		if err := s.auth.RevokeUserTokens(ctx, user.ID); err != nil {
+			s.logger.Error().Err(err).Msgf("Failed to revoke tokens of suspended user %d", user.ID)
+		}
+
+		s.events.Publish(ctx, newUserEvent(entity.EventUserDeactivated, user, actorID))
+	}
+
+	return results.list(), nil
+}
+
+// BulkReactivate lifts the suspension of the suspended users among the IDs.
+func (s *userService) BulkReactivate(ctx context.Context, req *BulkSetUserStatusRequest) ([]*BulkResult, error) {
+	if err := s.authorize(ctx, req.AuthParams, "USER.SUSPEND"); err != nil {
+		return nil, err
+	}
+
+	results, err := newBulkResults(req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var reactivated []*entity.User
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		users, err := s.bulkUsers(ctx, txRepo, req.AuthParams, results, PolicyActionUpdate)
+		if err != nil {
+			return err
+		}
+
+		reactivated = make([]*entity.User, 0, len(users))
+
+		for _, user := range users {
+			if user.Status != entity.UserStatusSuspended {
+				results.fail(user.ID, exception.New(exception.TypeConflict, exception.CodeConflict, "User is not suspended"))
+				continue
+			}
+
+			err := txRepo.User().SetStatus(ctx, &mysqlrepository.SetUserStatusPayload{
+				ID:     user.ID,
+				Status: entity.UserStatusActive,
+			})
+			if err != nil {
+				return err
+			}
+
+			user.Status = entity.UserStatusActive
+			user.SuspendedAt = nil
+			user.SuspensionReason = ""
+			reactivated = append(reactivated, user)
+		}
+
+		return nil
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	for _, user := range reactivated {
+		s.events.Publish(ctx, newUserEvent(entity.EventUserActivated, user, req.AuthParams.AccessTokenClaims.UserID))
+	}
+
+	return results.list(), nil
+}
+
+type BulkAssignUserRolesRequest struct {
+	AuthParams *AuthParams
+	UserIDs    []uint
+	RoleIDs    []uint
+}
+
+// BulkAssignRoles adds the roles to every user, keeping the roles they already hold. A user whose
+// company cannot use one of the roles is reported and left unchanged.
+func (s *userService) BulkAssignRoles(ctx context.Context, req *BulkAssignUserRolesRequest) ([]*BulkResult, error) {
+	if err := s.authorize(ctx, req.AuthParams, "USER.UPDATE"); err != nil {
+		return nil, err
+	}
+
+	results, err := newBulkResults(req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.RoleIDs) == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeValidationFailed, "At least one role is required")
+	}
+
+	roles, _, err := s.repo.MySQL().Role().Find(ctx, &mysqlrepository.FilterRolePayload{IDs: req.RoleIDs})
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	for _, roleID := range req.RoleIDs {
+		if !slices.ContainsFunc(roles, func(role *entity.Role) bool { return role.ID == roleID }) {
+			return nil, exception.NewWithErrors(exception.TypeValidationError, exception.CodeValidationFailed, "Role not found",
+				exception.FieldErrors{"role_ids": {"role not found"}})
+		}
+	}
+
+	var changed []uint
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		users, err := s.bulkUsers(ctx, txRepo, req.AuthParams, results, PolicyActionUpdate)
+		if err != nil {
+			return err
+		}
+
+		changed = make([]uint, 0, len(users))
+
+		for _, user := range users {
+			if !rolesUsableBy(roles, user) {
+				results.fail(user.ID, exception.New(exception.TypeValidationError, exception.CodeValidationFailed, "Role belongs to another company"))
+				continue
+			}
+
+			missing := make([]uint, 0, len(roles))
+
+			for _, role := range roles {
+				if !slices.Contains(user.RoleIDs, role.ID) {
+					missing = append(missing, role.ID)
+				}
+			}
+
+			if len(missing) == 0 {
+				continue
+			}
+
+			if _, err := txRepo.User().AttachRoles(ctx, user.ID, missing); err != nil {
+				return err
+			}
+
+			changed = append(changed, user.ID)
+		}
+
+		return nil
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if len(changed) > 0 {
+		users, _, err := s.repo.MySQL().User().Find(ctx, &mysqlrepository.FilterUserPayload{IDs: changed})
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to load users after bulk role assignment")
+			return results.list(), nil
+		}
+
+		for _, user := range users {
+			s.events.Publish(ctx, newUserEvent(entity.EventUserRolesChanged, user, req.AuthParams.AccessTokenClaims.UserID))
+			s.notifyRolesChanged(ctx, user)
+		}
+	}
+
+	return results.list(), nil
+}
+
+// bulkUsers loads the pending users, reporting the IDs that do not exist or that the access
+// policies do not allow the action on.
+func (s *userService) bulkUsers(ctx context.Context, repo mysqlrepository.MySQLRepository, authParams *AuthParams, results *bulkResults, action string) ([]*entity.User, error) {
+	ids := results.pending()
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	users, _, err := repo.User().Find(ctx, &mysqlrepository.FilterUserPayload{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := s.policies.Filter(ctx, repo, authParams, PolicyResourceUser, action)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]bool, len(users))
+	allowed := make([]*entity.User, 0, len(users))
+
+	for _, user := range users {
+		found[user.ID] = true
+
+		if !filter.Matches(userPolicyAttributes(user)) {
+			results.fail(user.ID, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access"))
+			continue
+		}
+
+		allowed = append(allowed, user)
+	}
+
+	results.failMissing(found, "User")
+
+	return allowed, nil
+}
+
+// rolesUsableBy mirrors checkRoleCompany for roles that are already loaded.
+func rolesUsableBy(roles []*entity.Role, user *entity.User) bool {
+	for _, role := range roles {
+		if !role.Global() && *role.CompanyID != user.CompanyID {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *userService) authorize(ctx context.Context, authParams *AuthParams, permissionCode string) error {
+	if authParams == nil || authParams.AccessTokenClaims == nil {
+		return exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, authParams.AccessTokenClaims.UserID, permissionCode)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"goapptemp/config"
 	"goapptemp/internal/adapter/repository"
 	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
@@ -9,33 +10,62 @@ import (
 	"goapptemp/internal/shared"
 	"goapptemp/internal/shared/exception"
 	"goapptemp/pkg/logger"
+	"time"
 
 	serror "goapptemp/internal/domain/service/error"
 )
 
 var _ UserService = (*userService)(nil)
 
+// userOwnedTables hold data that belongs to the user and goes with it, so their rows do not keep a
+// user from being deleted.
+var userOwnedTables = []string{"user_roles", "api_keys", "notifications", "notification_preferences", "password_history", "user_identities"}
+
 type UserService interface {
 	Create(ctx context.Context, req *CreateUserRequest) (*entity.User, error)
 	Update(ctx context.Context, req *UpdateUserRequest) (*entity.User, error)
 	Delete(ctx context.Context, req *DeleteUserRequest) error
 	Find(ctx context.Context, req *FindUserRequest) ([]*entity.User, int, error)
 	FindOne(ctx context.Context, req *FindOneUserRequest) (*entity.User, error)
+	Suspend(ctx context.Context, req *SuspendUserRequest) (*entity.User, error)
+	Reactivate(ctx context.Context, req *ReactivateUserRequest) (*entity.User, error)
+	BulkDelete(ctx context.Context, req *BulkDeleteUsersRequest) ([]*BulkResult, error)
+	FindDependencies(ctx context.Context, req *FindUserDependenciesRequest) ([]*entity.Dependency, error)
+	BulkSuspend(ctx context.Context, req *BulkSetUserStatusRequest) ([]*BulkResult, error)
+	BulkReactivate(ctx context.Context, req *BulkSetUserStatusRequest) ([]*BulkResult, error)
+	BulkAssignRoles(ctx context.Context, req *BulkAssignUserRolesRequest) ([]*BulkResult, error)
 }
 
 type userService struct {
-	config *config.Config
-	repo   repository.Repository
-	logger logger.Logger
-	auth   AuthService
+	config        *config.Config
+	repo          repository.Repository
+	logger        logger.Logger
+	auth          AuthService
+	events        EventService
+	notifications NotificationService
+	passwords     *PasswordPolicy
+	policies      *AccessPolicy
 }
 
-func NewUserService(config *config.Config, repo repository.Repository, logger logger.Logger, auth AuthService) *userService {
+func NewUserService(
+	config *config.Config,
+	repo repository.Repository,
+	logger logger.Logger,
+	auth AuthService,
+	events EventService,
+	notifications NotificationService,
+	passwords *PasswordPolicy,
+	policies *AccessPolicy,
+) *userService {
 	return &userService{
-		config: config,
-		repo:   repo,
-		logger: logger,
-		auth:   auth,
+		config:        config,
+		repo:          repo,
+		logger:        logger,
+		auth:          auth,
+		events:        events,
+		notifications: notifications,
+		passwords:     passwords,
+		policies:      policies,
 	}
 }
 
@@ -62,10 +92,17 @@ func (s *userService) Create(ctx context.Context, req *CreateUserRequest) (*enti
 		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "User data cannot be nil")
 	}
 
+	if err := s.passwords.Validate("password", req.User.Password, req.User); err != nil {
+		return nil, err
+	}
+
 	if err := req.User.SetPassword(req.User.Password); err != nil {
 		return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to hash user password during create")
 	}
 
+	now := time.Now()
+	req.User.PasswordChangedAt = &now
+
 	var user *entity.User
 
 	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
@@ -77,13 +114,17 @@ func (s *userService) Create(ctx context.Context, req *CreateUserRequest) (*enti
 		}
 
 		if len(req.User.RoleIDs) != 0 {
+			if err := checkRoleCompany(ctx, txRepo, user.CompanyID, req.User.RoleIDs); err != nil {
+				return err
+			}
+
 			_, err = txRepo.User().AttachRoles(ctx, user.ID, req.User.RoleIDs)
 			if err != nil {
 				return err
 			}
 		}
 
-		return nil
+		return s.passwords.Remember(ctx, txRepo, user.ID, req.User.Password)
 	}
 	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
 		return nil, serror.TranslateRepoError(err)
@@ -94,6 +135,8 @@ func (s *userService) Create(ctx context.Context, req *CreateUserRequest) (*enti
 		return nil, serror.TranslateRepoError(err)
 	}
 
+	s.events.Publish(ctx, newUserEvent(entity.EventUserCreated, user, req.AuthParams.AccessTokenClaims.UserID))
+
 	return user, nil
 }
 
@@ -124,15 +167,31 @@ func (s *userService) Update(ctx context.Context, req *UpdateUserRequest) (*enti
 		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "User ID required for update")
 	}
 
-	if req.Update.Password != nil && *req.Update.Password != "" {
+	if err := s.authorizeUser(ctx, req.AuthParams, PolicyActionUpdate, req.Update.ID); err != nil {
+		return nil, err
+	}
+
+	passwordChanged := req.Update.Password != nil && *req.Update.Password != ""
+	if passwordChanged && req.AuthParams.AccessTokenClaims.Impersonated() {
+		return nil, exception.ErrImpersonationDenied
+	}
+
+	if passwordChanged {
+		if err := s.checkNewPassword(ctx, req.Update); err != nil {
+			return nil, err
+		}
+
 		hashedPassword, err := shared.HashPassword(*req.Update.Password)
 		if err != nil {
 			return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to hash user password during update")
 		}
 
+		now := time.Now()
 		req.Update.Password = &hashedPassword
+		req.Update.PasswordChangedAt = &now
 	} else {
 		req.Update.Password = nil
+		req.Update.PasswordChangedAt = nil
 	}
 
 	var user *entity.User
@@ -159,12 +218,20 @@ func (s *userService) Update(ctx context.Context, req *UpdateUserRequest) (*enti
 				roleIDs = append(roleIDs, IDMap)
 			}
 
+			if err := checkRoleCompany(ctx, txRepo, user.CompanyID, roleIDs); err != nil {
+				return err
+			}
+
 			_, err = txRepo.User().SyncRoles(ctx, user.ID, roleIDs)
 			if err != nil {
 				return err
 			}
 		}
 
+		if passwordChanged {
+			return s.passwords.Remember(ctx, txRepo, user.ID, *req.Update.Password)
+		}
+
 		return nil
 	}
 	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
@@ -176,6 +243,14 @@ func (s *userService) Update(ctx context.Context, req *UpdateUserRequest) (*enti
 		return nil, serror.TranslateRepoError(err)
 	}
 
+	actorID := req.AuthParams.AccessTokenClaims.UserID
+	s.events.Publish(ctx, newUserEvent(entity.EventUserUpdated, user, actorID))
+
+	if req.Update.RoleIDs != nil {
+		s.events.Publish(ctx, newUserEvent(entity.EventUserRolesChanged, user, actorID))
+		s.notifyRolesChanged(ctx, user)
+	}
+
 	return user, nil
 }
 
@@ -206,13 +281,64 @@ func (s *userService) Delete(ctx context.Context, req *DeleteUserRequest) error
 		return exception.New(exception.TypeForbidden, exception.CodeForbidden, "User cannot delete their own account")
 	}
 
-	if err := s.repo.MySQL().User().Delete(ctx, req.UserID); err != nil {
+	user, err := s.repo.MySQL().User().FindByID(ctx, req.UserID)
+	if err != nil {
 		return serror.TranslateRepoError(err)
 	}
 
+	if err := s.policies.Authorize(ctx, s.repo.MySQL(), req.AuthParams, PolicyResourceUser, PolicyActionDelete, userPolicyAttributes(user)); err != nil {
+		return err
+	}
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		if err := checkDependencies(ctx, txRepo, "User", txRepo.User().GetTableName(), user.ID, false, userOwnedTables...); err != nil {
+			return err
+		}
+
+		return txRepo.User().Delete(ctx, user.ID)
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	s.events.Publish(ctx, newUserEvent(entity.EventUserDeleted, user, req.AuthParams.AccessTokenClaims.UserID))
+
 	return nil
 }
 
+type FindUserDependenciesRequest struct {
+	AuthParams *AuthParams
+	UserID     uint
+}
+
+// FindDependencies lists what keeps Delete and BulkDelete from deleting the user. Data the user owns,
+// such as role assignments, API keys and notifications, goes with it and is not reported.
+func (s *userService) FindDependencies(ctx context.Context, req *FindUserDependenciesRequest) ([]*entity.Dependency, error) {
+	if err := s.authorize(ctx, req.AuthParams, "USER.DELETE"); err != nil {
+		return nil, err
+	}
+
+	if req.UserID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "User ID cannot be zero")
+	}
+
+	user, err := s.repo.MySQL().User().FindByID(ctx, req.UserID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if err := s.policies.Authorize(ctx, s.repo.MySQL(), req.AuthParams, PolicyResourceUser, PolicyActionRead, userPolicyAttributes(user)); err != nil {
+		return nil, err
+	}
+
+	dependencies, err := s.repo.MySQL().StoreProcedure().FindDependencies(ctx, s.repo.MySQL().User().GetTableName(), []uint{user.ID}, userOwnedTables...)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return dependencies[user.ID], nil
+}
+
 type FindUserRequest struct {
 	AuthParams *AuthParams
 	UserFilter *mysqlrepository.FilterUserPayload
@@ -232,6 +358,11 @@ func (s *userService) Find(ctx context.Context, req *FindUserRequest) ([]*entity
 		return nil, 0, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
 	}
 
+	req.UserFilter.Policy, err = s.policies.Filter(ctx, s.repo.MySQL(), req.AuthParams, PolicyResourceUser, PolicyActionRead)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	users, totalCount, err := s.repo.MySQL().User().Find(ctx, req.UserFilter)
 	if err != nil {
 		return nil, 0, serror.TranslateRepoError(err)
@@ -272,7 +403,218 @@ func (s *userService) FindOne(ctx context.Context, req *FindOneUserRequest) (*en
 		return nil, serror.TranslateRepoError(err)
 	}
 
+	if err := s.policies.Authorize(ctx, s.repo.MySQL(), req.AuthParams, PolicyResourceUser, PolicyActionRead, userPolicyAttributes(user)); err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+
+	return user, nil
+}
+
+type SuspendUserRequest struct {
+	AuthParams *AuthParams
+	UserID     uint
+	Reason     string
+}
+
+// Suspend blocks the user from logging in and revokes every token they hold, effective immediately.
+func (s *userService) Suspend(ctx context.Context, req *SuspendUserRequest) (*entity.User, error) {
+	if req.AuthParams.AccessTokenClaims == nil {
+		return nil, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, req.AuthParams.AccessTokenClaims.UserID, "USER.SUSPEND")
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	if req.UserID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "User ID cannot be zero")
+	}
+
+	if req.AuthParams.AccessTokenClaims.UserID == req.UserID {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "User cannot suspend their own account")
+	}
+
+	user, err := s.repo.MySQL().User().FindByID(ctx, req.UserID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if err := s.policies.Authorize(ctx, s.repo.MySQL(), req.AuthParams, PolicyResourceUser, PolicyActionUpdate, userPolicyAttributes(user)); err != nil {
+		return nil, err
+	}
+
+	if user.Status != entity.UserStatusActive {
+		return nil, exception.Newf(exception.TypeConflict, exception.CodeConflict, "Only active users can be suspended, user is %s", user.Status)
+	}
+
+	now := time.Now()
+	err = s.repo.MySQL().User().SetStatus(ctx, &mysqlrepository.SetUserStatusPayload{
+		ID:               user.ID,
+		Status:           entity.UserStatusSuspended,
+		SuspendedAt:      &now,
+		SuspensionReason: req.Reason,
+	})
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	// The suspension is already saved, so a failed revocation does not fail the request; the user can
+	// no longer log in either way.
+	if err := s.auth.RevokeUserTokens(ctx, user.ID); err != nil {
+		s.logger.Error().Err(err).Msgf("Failed to revoke tokens of suspended user %d", user.ID)
+	}
+
+	user.Status = entity.UserStatusSuspended
+	user.SuspendedAt = &now
+	user.SuspensionReason = req.Reason
+	user.Password = ""
+
+	s.events.Publish(ctx, newUserEvent(entity.EventUserDeactivated, user, req.AuthParams.AccessTokenClaims.UserID))
+
+	return user, nil
+}
+
+type ReactivateUserRequest struct {
+	AuthParams *AuthParams
+	UserID     uint
+}
+
+// Reactivate lifts a suspension. Tokens revoked by the suspension stay revoked; the user logs in again.
+func (s *userService) Reactivate(ctx context.Context, req *ReactivateUserRequest) (*entity.User, error) {
+	if req.AuthParams.AccessTokenClaims == nil {
+		return nil, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, req.AuthParams.AccessTokenClaims.UserID, "USER.SUSPEND")
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	if req.UserID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "User ID cannot be zero")
+	}
+
+	user, err := s.repo.MySQL().User().FindByID(ctx, req.UserID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if err := s.policies.Authorize(ctx, s.repo.MySQL(), req.AuthParams, PolicyResourceUser, PolicyActionUpdate, userPolicyAttributes(user)); err != nil {
+		return nil, err
+	}
+
+	if user.Status != entity.UserStatusSuspended {
+		return nil, exception.New(exception.TypeConflict, exception.CodeConflict, "User is not suspended")
+	}
+
+	err = s.repo.MySQL().User().SetStatus(ctx, &mysqlrepository.SetUserStatusPayload{
+		ID:     user.ID,
+		Status: entity.UserStatusActive,
+	})
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	user.Status = entity.UserStatusActive
+	user.SuspendedAt = nil
+	user.SuspensionReason = ""
 	user.Password = ""
 
+	s.events.Publish(ctx, newUserEvent(entity.EventUserActivated, user, req.AuthParams.AccessTokenClaims.UserID))
+
 	return user, nil
 }
+
+// authorizeUser evaluates the access policies against the stored user, loading it only when a
+// policy targets the action.
+func (s *userService) authorizeUser(ctx context.Context, authParams *AuthParams, action string, userID uint) error {
+	if !s.policies.Governs(PolicyResourceUser, action) {
+		return nil
+	}
+
+	user, err := s.repo.MySQL().User().FindByID(ctx, userID)
+	if err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	return s.policies.Authorize(ctx, s.repo.MySQL(), authParams, PolicyResourceUser, action, userPolicyAttributes(user))
+}
+
+// checkRoleCompany rejects company roles that belong to a company other than the user's. Global
+// roles can be assigned to anyone.
+func checkRoleCompany(ctx context.Context, repo mysqlrepository.MySQLRepository, companyID uint, roleIDs []uint) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+
+	global := false
+
+	roles, _, err := repo.Role().Find(ctx, &mysqlrepository.FilterRolePayload{IDs: roleIDs, Global: &global})
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		if *role.CompanyID != companyID {
+			return exception.NewWithErrors(exception.TypeValidationError, exception.CodeValidationFailed, "Role belongs to another company",
+				exception.FieldErrors{"role_ids": {fmt.Sprintf("role %d belongs to another company", role.ID)}})
+		}
+	}
+
+	return nil
+}
+
+// checkNewPassword applies the password policy against the user as it will look after the update,
+// so a username or email changed in the same request is taken into account.
+func (s *userService) checkNewPassword(ctx context.Context, update *mysqlrepository.UpdateUserPayload) error {
+	user, err := s.repo.MySQL().User().FindByID(ctx, update.ID)
+	if err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	if update.Username != nil {
+		user.Username = *update.Username
+	}
+
+	if update.Email != nil {
+		user.Email = *update.Email
+	}
+
+	if err := s.passwords.Validate("password", *update.Password, user); err != nil {
+		return err
+	}
+
+	if err := s.passwords.CheckHistory(ctx, s.repo.MySQL(), "password", *update.Password, user); err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	return nil
+}
+
+// notifyRolesChanged runs after the update has committed, so a failure is only logged.
+func (s *userService) notifyRolesChanged(ctx context.Context, user *entity.User) {
+	roles := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roles = append(roles, role.Name)
+	}
+
+	err := s.notifications.Notify(ctx, &NotifyRequest{
+		UserID: user.ID,
+		Type:   entity.NotificationUserRolesChanged,
+		Data:   map[string]any{"Roles": roles},
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Msgf("Failed to notify user %d about role changes", user.ID)
+	}
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"goapptemp/config"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"goapptemp/pkg/webhooksender"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	repo "goapptemp/internal/adapter/repository"
+
+	"github.com/cockroachdb/errors"
+	apm "go.elastic.co/apm/v2"
+)
+
+const (
+	defaultWebhookPollInterval     = 5 * time.Second
+	defaultWebhookBatchSize        = 50
+	defaultWebhookConcurrency      = 4
+	defaultWebhookTimeout          = 10 * time.Second
+	defaultWebhookMaxAttempts      = 6
+	defaultWebhookBackoffBase      = 30 * time.Second
+	defaultWebhookBackoffMax       = 6 * time.Hour
+	defaultWebhookFailureThreshold = 20
+)
+
+var _ WebhookDeliveryWorker = (*webhookDeliveryWorker)(nil)
+
+type WebhookDeliveryWorker interface {
+	Start(ctx context.Context)
+}
+
+type webhookDeliveryWorker struct {
+	config           *config.Config
+	repo             repo.Repository
+	logger           logger.Logger
+	sender           webhooksender.WebhookSender
+	pollInterval     time.Duration
+	batchSize        int
+	concurrency      int
+	timeout          time.Duration
+	maxAttempts      uint
+	backoffBase      time.Duration
+	backoffMax       time.Duration
+	failureThreshold uint
+}
+
+func NewWebhookDeliveryWorker(config *config.Config, repo repo.Repository, logger logger.Logger, sender webhooksender.WebhookSender) *webhookDeliveryWorker {
+	cfg := config.Webhook
+
+	return &webhookDeliveryWorker{
+		config:           config,
+		repo:             repo,
+		logger:           logger.NewInstance().Field("component", "webhook_delivery_worker").Logger(),
+		sender:           sender,
+		pollInterval:     secondsOr(cfg.PollInterval, defaultWebhookPollInterval),
+		batchSize:        positiveOr(cfg.BatchSize, defaultWebhookBatchSize),
+		concurrency:      positiveOr(cfg.Concurrency, defaultWebhookConcurrency),
+		timeout:          secondsOr(cfg.Timeout, defaultWebhookTimeout),
+		maxAttempts:      uint(positiveOr(cfg.MaxAttempts, defaultWebhookMaxAttempts)),
+		backoffBase:      secondsOr(cfg.BackoffBase, defaultWebhookBackoffBase),
+		backoffMax:       secondsOr(cfg.BackoffMax, defaultWebhookBackoffMax),
+		failureThreshold: uint(positiveOr(cfg.FailureThreshold, defaultWebhookFailureThreshold)),
+	}
+}
+
+// Start polls for due deliveries until ctx is cancelled; a batch already in flight is allowed to finish.
+func (w *webhookDeliveryWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.processBatch(ctx)
+		case <-ctx.Done():
+			w.logger.Info().Msg("Webhook delivery worker stopping due to context cancellation.")
+			return
+		}
+	}
+}
+
+func (w *webhookDeliveryWorker) processBatch(ctx context.Context) {
+	tx := apm.DefaultTracer().StartTransaction("WebhookDeliveryWorker.processBatch", "task")
+	defer tx.End()
+
+	ctx = apm.ContextWithTransaction(ctx, tx)
+
+	released, err := w.repo.MySQL().WebhookDelivery().ReleaseStale(ctx, time.Now().Add(-2*w.timeout))
+	if err != nil {
+		w.captureError(ctx, err, "Failed to release stale webhook deliveries")
+	} else if released > 0 {
+		w.logger.Warn().Msgf("Released %d stale webhook deliveries", released)
+	}
+
+	deliveries, err := w.repo.MySQL().WebhookDelivery().FindDue(ctx, w.batchSize)
+	if err != nil {
+		w.captureError(ctx, err, "Failed to find due webhook deliveries")
+		return
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, w.concurrency)
+	)
+
+	// In-flight attempts must be recorded even when shutdown starts mid-batch.
+	deliverCtx := context.WithoutCancel(ctx)
+
+	for _, delivery := range deliveries {
+		claimed, err := w.repo.MySQL().WebhookDelivery().Claim(ctx, delivery.ID)
+		if err != nil {
+			w.captureError(ctx, err, "Failed to claim webhook delivery")
+			continue
+		}
+
+		if !claimed {
+			continue
+		}
+
+		sem <- struct{}{}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			w.deliver(deliverCtx, delivery)
+		})
+	}
+
+	wg.Wait()
+}
+
+func (w *webhookDeliveryWorker) deliver(ctx context.Context, delivery *entity.WebhookDelivery) {
+	span, ctx := apm.StartSpan(ctx, "WebhookDeliveryWorker.deliver", "task")
+	defer span.End()
+
+	log := w.logger.NewInstance().
+		Field("delivery_id", delivery.ID).
+		Field("subscription_id", delivery.SubscriptionID).
+		Field("event_id", delivery.EventID).
+		Field("attempt", delivery.Attempt).
+		Logger()
+
+	subscription, err := w.repo.MySQL().WebhookSubscription().FindByID(ctx, delivery.SubscriptionID)
+	// A lookup failure is ours, not the receiver's, so it is retried without counting against the
+	// subscription.
+	if err != nil && !errors.Is(err, exception.ErrNotFound) {
+		w.captureError(ctx, err, "Failed to load webhook subscription")
+		w.record(ctx, log, delivery, entity.WebhookDeliveryFailed, nil, nil, err)
+		w.scheduleRetry(ctx, log, delivery)
+
+		return
+	}
+
+	if subscription == nil || !subscription.IsActive {
+		w.record(ctx, log, delivery, entity.WebhookDeliveryCancelled, nil, nil, errors.New("subscription is inactive or deleted"))
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, sendErr := w.sender.Send(sendCtx, &webhooksender.Request{
+		URL:        subscription.TargetURL,
+		Secret:     subscription.Secret,
+		DeliveryID: strconv.FormatUint(delivery.ID, 10),
+		EventType:  delivery.EventType,
+		Body:       delivery.Payload,
+	})
+	duration := time.Since(start)
+
+	if sendErr == nil && resp.OK() {
+		w.record(ctx, log, delivery, entity.WebhookDeliverySucceeded, resp, &duration, nil)
+
+		if err := w.repo.MySQL().WebhookSubscription().ResetFailures(ctx, subscription.ID); err != nil {
+			w.captureError(ctx, err, "Failed to reset webhook subscription failures")
+		}
+
+		return
+	}
+
+	if sendErr == nil {
+		sendErr = fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	w.record(ctx, log, delivery, entity.WebhookDeliveryFailed, resp, &duration, sendErr)
+	w.handleFailure(ctx, log, subscription, delivery)
+}
+
+// handleFailure disables the subscription once it has failed failureThreshold attempts in a row,
+// otherwise schedules the next attempt.
+func (w *webhookDeliveryWorker) handleFailure(ctx context.Context, log logger.Logger, subscription *entity.WebhookSubscription, delivery *entity.WebhookDelivery) {
+	failures, err := w.repo.MySQL().WebhookSubscription().IncrementFailures(ctx, subscription.ID)
+	if err != nil {
+		w.captureError(ctx, err, "Failed to increment webhook subscription failures")
+	}
+
+	if failures >= w.failureThreshold {
+		reason := fmt.Sprintf("disabled after %d consecutive failed deliveries", failures)
+
+		if err := w.repo.MySQL().WebhookSubscription().Disable(ctx, subscription.ID, reason); err != nil {
+			w.captureError(ctx, err, "Failed to disable webhook subscription")
+			return
+		}
+
+		if err := w.repo.MySQL().WebhookDelivery().CancelPending(ctx, subscription.ID, "subscription disabled"); err != nil {
+			w.captureError(ctx, err, "Failed to cancel pending webhook deliveries")
+		}
+
+		log.Warn().Msgf("Webhook subscription %d %s", subscription.ID, reason)
+
+		return
+	}
+
+	w.scheduleRetry(ctx, log, delivery)
+}
+
+// scheduleRetry queues the next attempt of delivery with exponential backoff until maxAttempts is reached.
+func (w *webhookDeliveryWorker) scheduleRetry(ctx context.Context, log logger.Logger, delivery *entity.WebhookDelivery) {
+	if delivery.Attempt >= w.maxAttempts {
+		log.Warn().Msg("Webhook delivery exhausted all attempts")
+		return
+	}
+
+	_, err := w.repo.MySQL().WebhookDelivery().Create(ctx, &entity.WebhookDelivery{
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Attempt:        delivery.Attempt + 1,
+		Status:         entity.WebhookDeliveryPending,
+		ScheduledAt:    time.Now().Add(w.backoff(delivery.Attempt)),
+		ReplayOfID:     delivery.ReplayOfID,
+	})
+	if err != nil {
+		w.captureError(ctx, err, "Failed to schedule webhook delivery retry")
+	}
+}
+
+func (w *webhookDeliveryWorker) record(
+	ctx context.Context,
+	log logger.Logger,
+	delivery *entity.WebhookDelivery,
+	status entity.WebhookDeliveryStatus,
+	resp *webhooksender.Response,
+	duration *time.Duration,
+	deliveryErr error,
+) {
+	now := time.Now()
+
+	delivery.Status = status
+	delivery.AttemptedAt = &now
+
+	if resp != nil {
+		delivery.ResponseStatus = &resp.StatusCode
+		delivery.ResponseBody = &resp.Body
+	}
+
+	if duration != nil {
+		ms := uint(duration.Milliseconds())
+		delivery.DurationMs = &ms
+	}
+
+	if deliveryErr != nil {
+		msg := deliveryErr.Error()
+		delivery.Error = &msg
+
+		log.Warn().Err(deliveryErr).Msgf("Webhook delivery %s", status)
+	}
+
+	if err := w.repo.MySQL().WebhookDelivery().Complete(ctx, delivery); err != nil {
+		w.captureError(ctx, err, "Failed to record webhook delivery attempt")
+	}
+}
+
+// backoff doubles the base delay per attempt, caps it and adds up to 20% jitter to spread retries out.
+func (w *webhookDeliveryWorker) backoff(attempt uint) time.Duration {
+	delay := w.backoffMax
+	if shift := attempt - 1; shift < 32 {
+		if d := w.backoffBase << shift; d > 0 && d < w.backoffMax {
+			delay = d
+		}
+	}
+
+	return delay + rand.N(delay/5+1)
+}
+
+func (w *webhookDeliveryWorker) captureError(ctx context.Context, err error, msg string) {
+	if apmErr := apm.CaptureError(ctx, err); apmErr != nil {
+		apmErr.Handled = true
+		apmErr.Send()
+	}
+
+	w.logger.Error().Err(err).Msg(msg)
+}
+
+func secondsOr(seconds int, fallback time.Duration) time.Duration {
+	if seconds <= 0 {
+		return fallback
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+func positiveOr(value, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
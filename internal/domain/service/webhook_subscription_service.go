@@ -0,0 +1,439 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"goapptemp/config"
+	mysqlrepository "goapptemp/internal/adapter/repository/mysql"
+	"goapptemp/internal/domain/entity"
+	"goapptemp/internal/shared/exception"
+	"goapptemp/pkg/logger"
+	"goapptemp/pkg/webhooksender"
+	"slices"
+	"time"
+
+	repo "goapptemp/internal/adapter/repository"
+
+	serror "goapptemp/internal/domain/service/error"
+
+	"github.com/cockroachdb/errors"
+)
+
+var _ WebhookSubscriptionService = (*webhookSubscriptionService)(nil)
+
+type WebhookSubscriptionService interface {
+	Create(ctx context.Context, req *CreateWebhookSubscriptionRequest) (*entity.WebhookSubscription, error)
+	Update(ctx context.Context, req *UpdateWebhookSubscriptionRequest) (*entity.WebhookSubscription, error)
+	Delete(ctx context.Context, req *DeleteWebhookSubscriptionRequest) error
+	Find(ctx context.Context, req *FindWebhookSubscriptionsRequest) ([]*entity.WebhookSubscription, int, error)
+	FindOne(ctx context.Context, req *FindOneWebhookSubscriptionRequest) (*entity.WebhookSubscription, error)
+	FindDeliveries(ctx context.Context, req *FindWebhookDeliveriesRequest) ([]*entity.WebhookDelivery, int, error)
+	ReplayDelivery(ctx context.Context, req *ReplayWebhookDeliveryRequest) (*entity.WebhookDelivery, error)
+	Dispatch(ctx context.Context, event *entity.DomainEvent, body []byte) error
+}
+
+type webhookSubscriptionService struct {
+	config *config.Config
+	repo   repo.Repository
+	logger logger.Logger
+	auth   AuthService
+}
+
+func NewWebhookSubscriptionService(config *config.Config, repo repo.Repository, logger logger.Logger, auth AuthService) *webhookSubscriptionService {
+	return &webhookSubscriptionService{
+		config: config,
+		repo:   repo,
+		logger: logger,
+		auth:   auth,
+	}
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	AuthParams   *AuthParams
+	Subscription *entity.WebhookSubscription
+}
+
+func (s *webhookSubscriptionService) Create(ctx context.Context, req *CreateWebhookSubscriptionRequest) (*entity.WebhookSubscription, error) {
+	companyID, err := s.authorize(ctx, req.AuthParams, "WEBHOOK.CREATE")
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Subscription == nil {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Webhook subscription data cannot be nil")
+	}
+
+	if err := validateWebhookTarget(ctx, req.Subscription.TargetURL); err != nil {
+		return nil, err
+	}
+
+	eventTypes, err := normalizeWebhookEventTypes(req.Subscription.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Subscription.Secret
+	if secret == "" {
+		secret, err = generateWebhookSecret()
+		if err != nil {
+			return nil, exception.Wrap(err, exception.TypeInternalError, exception.CodeInternalError, "Failed to generate webhook secret")
+		}
+	}
+
+	subscription, err := s.repo.MySQL().WebhookSubscription().Create(ctx, &entity.WebhookSubscription{
+		CompanyID:  companyID,
+		Name:       req.Subscription.Name,
+		TargetURL:  req.Subscription.TargetURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		IsActive:   true,
+	})
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return subscription, nil
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	AuthParams *AuthParams
+	Update     *mysqlrepository.UpdateWebhookSubscriptionPayload
+}
+
+func (s *webhookSubscriptionService) Update(ctx context.Context, req *UpdateWebhookSubscriptionRequest) (*entity.WebhookSubscription, error) {
+	companyID, err := s.authorize(ctx, req.AuthParams, "WEBHOOK.UPDATE")
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Update == nil {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Update payload cannot be nil")
+	}
+
+	if req.Update.ID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Webhook subscription ID required for update")
+	}
+
+	if _, err := s.findOwned(ctx, companyID, req.Update.ID); err != nil {
+		return nil, err
+	}
+
+	if req.Update.TargetURL != nil {
+		if err := validateWebhookTarget(ctx, *req.Update.TargetURL); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Update.EventTypes != nil {
+		req.Update.EventTypes, err = normalizeWebhookEventTypes(req.Update.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	subscription, err := s.repo.MySQL().WebhookSubscription().Update(ctx, req.Update)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return subscription, nil
+}
+
+type DeleteWebhookSubscriptionRequest struct {
+	AuthParams     *AuthParams
+	SubscriptionID uint
+}
+
+func (s *webhookSubscriptionService) Delete(ctx context.Context, req *DeleteWebhookSubscriptionRequest) error {
+	companyID, err := s.authorize(ctx, req.AuthParams, "WEBHOOK.DELETE")
+	if err != nil {
+		return err
+	}
+
+	if req.SubscriptionID == 0 {
+		return exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Webhook subscription ID cannot be zero")
+	}
+
+	if _, err := s.findOwned(ctx, companyID, req.SubscriptionID); err != nil {
+		return err
+	}
+
+	atomicOperation := func(txRepo mysqlrepository.MySQLRepository) error {
+		if err := txRepo.WebhookDelivery().CancelPending(ctx, req.SubscriptionID, "subscription deleted"); err != nil {
+			return err
+		}
+
+		return txRepo.WebhookSubscription().Delete(ctx, req.SubscriptionID)
+	}
+	if err := s.repo.MySQL().Atomic(ctx, s.config, atomicOperation); err != nil {
+		return serror.TranslateRepoError(err)
+	}
+
+	return nil
+}
+
+type FindWebhookSubscriptionsRequest struct {
+	AuthParams *AuthParams
+	Filter     *mysqlrepository.FilterWebhookSubscriptionPayload
+}
+
+func (s *webhookSubscriptionService) Find(ctx context.Context, req *FindWebhookSubscriptionsRequest) ([]*entity.WebhookSubscription, int, error) {
+	companyID, err := s.authorize(ctx, req.AuthParams, "WEBHOOK.READ")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.Filter.CompanyIDs = []uint{companyID}
+
+	subscriptions, totalCount, err := s.repo.MySQL().WebhookSubscription().Find(ctx, req.Filter)
+	if err != nil {
+		return nil, 0, serror.TranslateRepoError(err)
+	}
+
+	return subscriptions, totalCount, nil
+}
+
+type FindOneWebhookSubscriptionRequest struct {
+	AuthParams     *AuthParams
+	SubscriptionID uint
+}
+
+func (s *webhookSubscriptionService) FindOne(ctx context.Context, req *FindOneWebhookSubscriptionRequest) (*entity.WebhookSubscription, error) {
+	companyID, err := s.authorize(ctx, req.AuthParams, "WEBHOOK.READ")
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SubscriptionID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Webhook subscription ID required for find one")
+	}
+
+	return s.findOwned(ctx, companyID, req.SubscriptionID)
+}
+
+type FindWebhookDeliveriesRequest struct {
+	AuthParams     *AuthParams
+	SubscriptionID uint
+	Filter         *mysqlrepository.FilterWebhookDeliveryPayload
+}
+
+func (s *webhookSubscriptionService) FindDeliveries(ctx context.Context, req *FindWebhookDeliveriesRequest) ([]*entity.WebhookDelivery, int, error) {
+	companyID, err := s.authorize(ctx, req.AuthParams, "WEBHOOK.READ")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if req.SubscriptionID == 0 {
+		return nil, 0, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Webhook subscription ID cannot be zero")
+	}
+
+	if _, err := s.findOwned(ctx, companyID, req.SubscriptionID); err != nil {
+		return nil, 0, err
+	}
+
+	req.Filter.SubscriptionIDs = []uint{req.SubscriptionID}
+
+	deliveries, totalCount, err := s.repo.MySQL().WebhookDelivery().Find(ctx, req.Filter)
+	if err != nil {
+		return nil, 0, serror.TranslateRepoError(err)
+	}
+
+	return deliveries, totalCount, nil
+}
+
+type ReplayWebhookDeliveryRequest struct {
+	AuthParams     *AuthParams
+	SubscriptionID uint
+	DeliveryID     uint64
+}
+
+// ReplayDelivery queues a fresh first attempt with the original payload; the original record is left untouched.
+func (s *webhookSubscriptionService) ReplayDelivery(ctx context.Context, req *ReplayWebhookDeliveryRequest) (*entity.WebhookDelivery, error) {
+	companyID, err := s.authorize(ctx, req.AuthParams, "WEBHOOK.UPDATE")
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SubscriptionID == 0 || req.DeliveryID == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Webhook subscription ID and delivery ID are required")
+	}
+
+	subscription, err := s.findOwned(ctx, companyID, req.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !subscription.IsActive {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Webhook subscription is disabled")
+	}
+
+	original, err := s.repo.MySQL().WebhookDelivery().FindByID(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if original.SubscriptionID != subscription.ID {
+		return nil, exception.New(exception.TypeNotFound, exception.CodeNotFound, "Webhook delivery not found")
+	}
+
+	delivery, err := s.repo.MySQL().WebhookDelivery().Create(ctx, &entity.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Attempt:        1,
+		Status:         entity.WebhookDeliveryPending,
+		ScheduledAt:    time.Now(),
+		ReplayOfID:     &original.ID,
+	})
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	return delivery, nil
+}
+
+// Dispatch queues one delivery per matching active subscription of the event's tenant. It runs as a
+// retried task, so subscriptions that already have a delivery for the event are skipped; the unique
+// key on deliveries covers dispatches racing each other.
+func (s *webhookSubscriptionService) Dispatch(ctx context.Context, event *entity.DomainEvent, body []byte) error {
+	if event == nil || event.TenantID == 0 || !slices.Contains(entity.WebhookEventTypes, event.Type) {
+		return nil
+	}
+
+	isActive := true
+
+	subscriptions, _, err := s.repo.MySQL().WebhookSubscription().Find(ctx, &mysqlrepository.FilterWebhookSubscriptionPayload{
+		CompanyIDs: []uint{event.TenantID},
+		IsActive:   &isActive,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find webhook subscriptions for event %s (%s)", event.Type, event.ID)
+	}
+
+	existing, _, err := s.repo.MySQL().WebhookDelivery().Find(ctx, &mysqlrepository.FilterWebhookDeliveryPayload{EventID: event.ID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find webhook deliveries for event %s (%s)", event.Type, event.ID)
+	}
+
+	delivered := make(map[uint]bool, len(existing))
+	for _, delivery := range existing {
+		delivered[delivery.SubscriptionID] = true
+	}
+
+	now := time.Now()
+	deliveries := make([]*entity.WebhookDelivery, 0, len(subscriptions))
+
+	for _, subscription := range subscriptions {
+		if !subscription.Subscribes(event.Type) || delivered[subscription.ID] {
+			continue
+		}
+
+		deliveries = append(deliveries, &entity.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      string(event.Type),
+			Payload:        body,
+			Attempt:        1,
+			Status:         entity.WebhookDeliveryPending,
+			ScheduledAt:    now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := s.repo.MySQL().WebhookDelivery().BulkCreate(ctx, deliveries); err != nil {
+		return errors.Wrapf(err, "failed to queue webhook deliveries for event %s (%s)", event.Type, event.ID)
+	}
+
+	return nil
+}
+
+// authorize checks the permission and returns the caller's company, which scopes every subscription they can see.
+func (s *webhookSubscriptionService) authorize(ctx context.Context, authParams *AuthParams, permissionCode string) (uint, error) {
+	if authParams == nil || authParams.AccessTokenClaims == nil {
+		return 0, exception.New(exception.TypePermissionDenied, exception.CodeForbidden, "Token payload not provided")
+	}
+
+	ok, err := s.auth.AuthorizationCheck(ctx, authParams.AccessTokenClaims.UserID, permissionCode)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		return 0, exception.New(exception.TypeForbidden, exception.CodeForbidden, "Not allowed to access")
+	}
+
+	user, err := s.repo.MySQL().User().FindByID(ctx, authParams.AccessTokenClaims.UserID)
+	if err != nil {
+		return 0, serror.TranslateRepoError(err)
+	}
+
+	return user.CompanyID, nil
+}
+
+func (s *webhookSubscriptionService) findOwned(ctx context.Context, companyID, subscriptionID uint) (*entity.WebhookSubscription, error) {
+	subscription, err := s.repo.MySQL().WebhookSubscription().FindByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, serror.TranslateRepoError(err)
+	}
+
+	if subscription.CompanyID != companyID {
+		return nil, exception.New(exception.TypeNotFound, exception.CodeNotFound, "Webhook subscription not found")
+	}
+
+	return subscription, nil
+}
+
+// validateWebhookTarget keeps subscriptions from pointing the sender at internal services, whose
+// responses would otherwise be readable through the delivery log.
+func validateWebhookTarget(ctx context.Context, targetURL string) error {
+	err := webhooksender.ValidateTarget(ctx, targetURL)
+	if err == nil {
+		return nil
+	}
+
+	msg := "must be a valid URL"
+
+	switch {
+	case errors.Is(err, webhooksender.ErrInsecureTarget):
+		msg = "must use https"
+	case errors.Is(err, webhooksender.ErrBlockedTarget):
+		msg = "must not point to a private, loopback or link-local address"
+	}
+
+	return exception.NewWithErrors(exception.TypeValidationError, exception.CodeValidationFailed, "Invalid webhook target URL",
+		exception.FieldErrors{"target_url": {msg}})
+}
+
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "At least one event type is required")
+	}
+
+	res := make([]string, 0, len(eventTypes))
+
+	for _, eventType := range eventTypes {
+		if !slices.Contains(entity.WebhookEventTypes, entity.DomainEventType(eventType)) {
+			return nil, exception.New(exception.TypeBadRequest, exception.CodeBadRequest, "Unsupported webhook event type: "+eventType)
+		}
+
+		if !slices.Contains(res, eventType) {
+			res = append(res, eventType)
+		}
+	}
+
+	return res, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -49,6 +49,12 @@ const (
 	CodeAuthHeaderInvalid     = "AUTH_HEADER_INVALID"
 	CodeAuthUnsupported       = "AUTH_UNSUPPORTED"
 	CodeDBConstraintViolation = "DB_CONSTRAINT_VIOLATION"
+	CodeRateLimitExceeded     = "RATE_LIMIT_EXCEEDED"
+	CodeAPIKeyInvalid         = "API_KEY_INVALID"
+	CodePasswordReused        = "PASSWORD_REUSED"
+	CodePasswordExpired       = "PASSWORD_EXPIRED"
+	CodeUserInactive          = "USER_INACTIVE"
+	CodeImpersonationDenied   = "IMPERSONATION_DENIED"
 )
 
 var (
@@ -72,4 +78,9 @@ var (
 	ErrAuthUnsupported      = New(TypePermissionDenied, CodeAuthUnsupported, "Unsupported authorization type")
 	ErrAuthTokenInvalid     = New(TypeBadRequest, CodeTokenInvalid, "Invalid or expired token")
 	ErrAuthTokenBlacklisted = New(TypePermissionDenied, CodeTokenBlacklisted, "Token has been logged out")
+	ErrAPIKeyInvalid        = New(TypeUnauthorized, CodeAPIKeyInvalid, "Invalid, expired or revoked API key")
+	ErrPasswordExpired      = New(TypeForbidden, CodePasswordExpired, "Password has expired and must be changed")
+	ErrUserInactive         = New(TypeForbidden, CodeUserInactive, "Account is suspended or not activated")
+	ErrUserTokensRevoked    = New(TypePermissionDenied, CodeTokenBlacklisted, "Token has been revoked")
+	ErrImpersonationDenied  = New(TypeForbidden, CodeImpersonationDenied, "Not allowed while impersonating a user")
 )
@@ -10,10 +10,6 @@ import (
 func NewValidator() (*validator.Validate, error) {
 	v := validator.New()
 
-	if err := v.RegisterValidation("password_chars_contain", PasswordCharsContain); err != nil {
-		return nil, err
-	}
-
 	if err := v.RegisterValidation("username_chars_allowed", UsernameCharsAllowed); err != nil {
 		return nil, err
 	}
@@ -30,21 +26,9 @@ func NewValidator() (*validator.Validate, error) {
 		return nil, err
 	}
 
-	v.RegisterAlias("password", "min=8,password_chars_contain")
-
 	return v, nil
 }
 
-func PasswordCharsContain(fl validator.FieldLevel) bool {
-	password := fl.Field().String()
-	hasUpper := regexp.MustCompile(`[A-Z]`).MatchString(password)
-	hasLower := regexp.MustCompile(`[a-z]`).MatchString(password)
-	hasNumber := regexp.MustCompile(`[0-9]`).MatchString(password)
-	hasSymbol := regexp.MustCompile(`[^a-zA-Z0-9]`).MatchString(password)
-
-	return hasUpper && hasLower && hasNumber && hasSymbol
-}
-
 func UsernameCharsAllowed(fl validator.FieldLevel) bool {
 	username := fl.Field().String()
 	isAllowed := regexp.MustCompile(`^[a-zA-Z0-9_]+$`).MatchString(username)
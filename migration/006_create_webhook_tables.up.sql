@@ -0,0 +1,61 @@
+START TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS `webhook_subscriptions` (
+    `id`                   INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    `company_id`           INT UNSIGNED  NOT NULL,
+    `name`                 VARCHAR(255)  NOT NULL,
+    `target_url`           VARCHAR(2048) NOT NULL,
+    `secret`               VARCHAR(255)  NOT NULL,
+    `event_types`          JSON          NOT NULL,
+    `is_active`            BOOLEAN       NOT NULL DEFAULT TRUE,
+    `consecutive_failures` INT UNSIGNED  NOT NULL DEFAULT 0,
+    `disabled_at`          TIMESTAMP     NULL     DEFAULT NULL,
+    `disabled_reason`      VARCHAR(255)  DEFAULT NULL,
+    `created_at`           TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`           TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `deleted_at`           TIMESTAMP     NULL     DEFAULT NULL,
+    `name_active`          VARCHAR(255) GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, `name`, NULL)) STORED,
+    INDEX `idx_company_id_is_active` (`company_id`, `is_active`),
+    UNIQUE KEY `uq_webhook_subscriptions_company_id_name_active` (`company_id`, `name_active`),
+    CONSTRAINT `fk_webhook_subscriptions_company_id_companies` FOREIGN KEY (`company_id`) REFERENCES `companies`(`id`) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+    `id`              BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    `subscription_id` INT UNSIGNED NOT NULL,
+    `event_id`        VARCHAR(64)  NOT NULL,
+    `event_type`      VARCHAR(255) NOT NULL,
+    `payload`         JSON         NOT NULL,
+    `attempt`         INT UNSIGNED NOT NULL DEFAULT 1,
+    `status`          VARCHAR(32)  NOT NULL DEFAULT 'pending',
+    `response_status` INT          DEFAULT NULL,
+    `response_body`   TEXT         DEFAULT NULL,
+    `error`           TEXT         DEFAULT NULL,
+    `duration_ms`     INT UNSIGNED DEFAULT NULL,
+    `scheduled_at`    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `attempted_at`    TIMESTAMP    NULL     DEFAULT NULL,
+    `replay_of_id`    BIGINT UNSIGNED DEFAULT NULL,
+    `created_at`      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX `idx_status_scheduled_at` (`status`, `scheduled_at`),
+    INDEX `idx_subscription_id_event_id` (`subscription_id`, `event_id`),
+    CONSTRAINT `fk_webhook_deliveries_subscription_id_webhook_subscriptions` FOREIGN KEY (`subscription_id`) REFERENCES `webhook_subscriptions`(`id`) ON DELETE RESTRICT
+);
+
+INSERT INTO
+    `permissions` (`id`, `code`, `name`, `description`)
+VALUES
+    (73, 'WEBHOOK.READ', 'Webhook Read', 'Permission to read webhook subscription'),
+    (74, 'WEBHOOK.CREATE', 'Webhook Create', 'Permission to create webhook subscription'),
+    (75, 'WEBHOOK.UPDATE', 'Webhook Update', 'Permission to update webhook subscription'),
+    (76, 'WEBHOOK.DELETE', 'Webhook Delete', 'Permission to delete webhook subscription');
+
+INSERT INTO
+    `role_permissions` (`permission_id`, `role_id`)
+VALUES
+    (73, 1),
+    (74, 1),
+    (75, 1),
+    (76, 1);
+
+COMMIT;
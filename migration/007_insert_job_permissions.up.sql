@@ -0,0 +1,15 @@
+START TRANSACTION;
+
+INSERT INTO
+    `permissions` (`id`, `code`, `name`, `description`)
+VALUES
+    (77, 'JOB.READ', 'Job Read', 'Permission to read background jobs and their run history'),
+    (78, 'JOB.EXECUTE', 'Job Execute', 'Permission to trigger background jobs manually');
+
+INSERT INTO
+    `role_permissions` (`permission_id`, `role_id`)
+VALUES
+    (77, 1),
+    (78, 1);
+
+COMMIT;
@@ -0,0 +1,16 @@
+START TRANSACTION;
+
+ALTER TABLE `users`
+    ADD COLUMN `locale` VARCHAR(10) NOT NULL DEFAULT 'en' AFTER `fullname`;
+
+INSERT INTO
+    `permissions` (`id`, `code`, `name`, `description`)
+VALUES
+    (79, 'EMAIL_TEMPLATE.READ', 'Email Template Read', 'Permission to list and preview email templates');
+
+INSERT INTO
+    `role_permissions` (`permission_id`, `role_id`)
+VALUES
+    (79, 1);
+
+COMMIT;
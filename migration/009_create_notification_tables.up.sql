@@ -0,0 +1,29 @@
+START TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS `notifications` (
+    `id`         BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    `user_id`    INT UNSIGNED NOT NULL,
+    `type`       VARCHAR(255) NOT NULL,
+    `title`      VARCHAR(255) NOT NULL,
+    `body`       TEXT         NOT NULL,
+    `data`       JSON         DEFAULT NULL,
+    `read_at`    TIMESTAMP    NULL     DEFAULT NULL,
+    `created_at` TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX `idx_user_id_read_at` (`user_id`, `read_at`),
+    CONSTRAINT `fk_notifications_user_id_users` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `notification_preferences` (
+    `id`         INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    `user_id`    INT UNSIGNED NOT NULL,
+    `type`       VARCHAR(255) NOT NULL,
+    `channel`    VARCHAR(32)  NOT NULL,
+    `enabled`    BOOLEAN      NOT NULL DEFAULT TRUE,
+    `created_at` TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY `uq_notification_preferences_user_id_type_channel` (`user_id`, `type`, `channel`),
+    CONSTRAINT `fk_notification_preferences_user_id_users` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,15 @@
+START TRANSACTION;
+
+INSERT INTO
+    `permissions` (`id`, `code`, `name`, `description`)
+VALUES
+    (80, 'LOCKOUT.READ', 'Lockout Read', 'Permission to list locked users and blocked IP addresses'),
+    (81, 'LOCKOUT.UPDATE', 'Lockout Update', 'Permission to unlock users and unblock IP addresses');
+
+INSERT INTO
+    `role_permissions` (`permission_id`, `role_id`)
+VALUES
+    (80, 1),
+    (81, 1);
+
+COMMIT;
@@ -0,0 +1,36 @@
+START TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS `api_keys` (
+    `id`           INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    `company_id`   INT UNSIGNED NOT NULL,
+    `user_id`      INT UNSIGNED NOT NULL,
+    `name`         VARCHAR(255) NOT NULL,
+    `prefix`       VARCHAR(32)  NOT NULL,
+    `secret_hash`  CHAR(64)     NOT NULL,
+    `permissions`  JSON         NOT NULL,
+    `expires_at`   TIMESTAMP    NULL     DEFAULT NULL,
+    `last_used_at` TIMESTAMP    NULL     DEFAULT NULL,
+    `revoked_at`   TIMESTAMP    NULL     DEFAULT NULL,
+    `created_at`   TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`   TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY `uq_api_keys_prefix` (`prefix`),
+    INDEX `idx_company_id_revoked_at` (`company_id`, `revoked_at`),
+    CONSTRAINT `fk_api_keys_company_id_companies` FOREIGN KEY (`company_id`) REFERENCES `companies`(`id`) ON DELETE RESTRICT,
+    CONSTRAINT `fk_api_keys_user_id_users` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+
+INSERT INTO
+    `permissions` (`id`, `code`, `name`, `description`)
+VALUES
+    (82, 'API_KEY.READ', 'API Key Read', 'Permission to list API keys'),
+    (83, 'API_KEY.CREATE', 'API Key Create', 'Permission to issue API keys'),
+    (84, 'API_KEY.DELETE', 'API Key Delete', 'Permission to revoke API keys');
+
+INSERT INTO
+    `role_permissions` (`permission_id`, `role_id`)
+VALUES
+    (82, 1),
+    (83, 1),
+    (84, 1);
+
+COMMIT;
@@ -0,0 +1,17 @@
+START TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS `user_identities` (
+    `id`            INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    `user_id`       INT UNSIGNED NOT NULL,
+    `provider`      VARCHAR(64)  NOT NULL,
+    `subject`       VARCHAR(255) NOT NULL,
+    `email`         VARCHAR(255) NOT NULL,
+    `last_login_at` TIMESTAMP    NULL     DEFAULT NULL,
+    `created_at`    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY `uq_user_identities_provider_subject` (`provider`, `subject`),
+    INDEX `idx_user_id` (`user_id`),
+    CONSTRAINT `fk_user_identities_user_id_users` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,17 @@
+START TRANSACTION;
+
+ALTER TABLE `users`
+    ADD COLUMN `password_changed_at` TIMESTAMP NULL DEFAULT NULL AFTER `password`;
+
+UPDATE `users` SET `password_changed_at` = CURRENT_TIMESTAMP WHERE `password_changed_at` IS NULL;
+
+CREATE TABLE IF NOT EXISTS `password_history` (
+    `id`            INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    `user_id`       INT UNSIGNED NOT NULL,
+    `password_hash` VARCHAR(255) NOT NULL,
+    `created_at`    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX `idx_user_id_created_at` (`user_id`, `created_at`),
+    CONSTRAINT `fk_password_history_user_id_users` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+START TRANSACTION;
+
+ALTER TABLE `users`
+    ADD COLUMN `status` VARCHAR(16) NOT NULL DEFAULT 'active' AFTER `locale`,
+    ADD INDEX `idx_users_status` (`status`);
+
+COMMIT;
@@ -0,0 +1,17 @@
+START TRANSACTION;
+
+ALTER TABLE `users`
+    ADD COLUMN `suspended_at`      TIMESTAMP    NULL DEFAULT NULL AFTER `status`,
+    ADD COLUMN `suspension_reason` VARCHAR(255) NULL DEFAULT NULL AFTER `suspended_at`;
+
+INSERT INTO
+    `permissions` (`id`, `code`, `name`, `description`)
+VALUES
+    (85, 'USER.SUSPEND', 'User Suspend', 'Permission to suspend and reactivate users');
+
+INSERT INTO
+    `role_permissions` (`permission_id`, `role_id`)
+VALUES
+    (85, 1);
+
+COMMIT;
@@ -0,0 +1,20 @@
+START TRANSACTION;
+
+-- company_id NULL marks a global template role; company roles may extend templates via parent_id.
+ALTER TABLE `roles`
+    ADD COLUMN `parent_id`     INT UNSIGNED NULL DEFAULT NULL AFTER `id`,
+    ADD COLUMN `company_id`    INT UNSIGNED NULL DEFAULT NULL AFTER `parent_id`,
+    ADD COLUMN `company_scope` INT UNSIGNED GENERATED ALWAYS AS (IFNULL(`company_id`, 0)) STORED,
+    ADD INDEX `idx_roles_parent_id` (`parent_id`),
+    ADD INDEX `idx_roles_company_id` (`company_id`),
+    ADD CONSTRAINT `fk_roles_parent_id_roles` FOREIGN KEY (`parent_id`) REFERENCES `roles`(`id`) ON DELETE RESTRICT,
+    ADD CONSTRAINT `fk_roles_company_id_companies` FOREIGN KEY (`company_id`) REFERENCES `companies`(`id`) ON DELETE RESTRICT;
+
+-- Codes and names only need to be unique within a company, or among the global roles.
+ALTER TABLE `roles`
+    DROP INDEX `uq_roles_code_active`,
+    DROP INDEX `uq_roles_name_active`,
+    ADD UNIQUE KEY `uq_roles_code_active` (`company_scope`, `code_active`),
+    ADD UNIQUE KEY `uq_roles_name_active` (`company_scope`, `name_active`);
+
+COMMIT;
@@ -0,0 +1,19 @@
+START TRANSACTION;
+
+-- Dispatch may run twice for the same event; keep the first delivery of each attempt.
+DELETE `duplicate`
+FROM `webhook_deliveries` AS `duplicate`
+JOIN `webhook_deliveries` AS `kept`
+    ON `kept`.`subscription_id` = `duplicate`.`subscription_id`
+    AND `kept`.`event_id` = `duplicate`.`event_id`
+    AND `kept`.`attempt` = `duplicate`.`attempt`
+    AND `kept`.`id` < `duplicate`.`id`
+WHERE `kept`.`replay_of_id` IS NULL
+    AND `duplicate`.`replay_of_id` IS NULL;
+
+-- Replays are NULL here, so a delivery can be replayed any number of times.
+ALTER TABLE `webhook_deliveries`
+    ADD COLUMN `dispatch_attempt` INT UNSIGNED GENERATED ALWAYS AS (IF(`replay_of_id` IS NULL, `attempt`, NULL)) STORED,
+    ADD UNIQUE KEY `uq_webhook_deliveries_dispatch` (`subscription_id`, `event_id`, `dispatch_attempt`);
+
+COMMIT;
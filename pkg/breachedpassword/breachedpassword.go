@@ -0,0 +1,83 @@
+package breachedpassword
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// List is an offline set of passwords known to be common or leaked. Lookups are case-insensitive.
+type List struct {
+	passwords map[string]struct{}
+}
+
+// New returns the embedded list, extended with the newline-separated files in paths.
+func New(paths ...string) (*List, error) {
+	l := &List{passwords: make(map[string]struct{})}
+
+	if err := l.read(strings.NewReader(commonPasswords)); err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		if err := l.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+func (l *List) Contains(password string) bool {
+	if l == nil {
+		return false
+	}
+
+	_, ok := l.passwords[strings.ToLower(password)]
+
+	return ok
+}
+
+func (l *List) Len() int {
+	if l == nil {
+		return 0
+	}
+
+	return len(l.passwords)
+}
+
+func (l *List) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open breached password list %s", path)
+	}
+	defer f.Close()
+
+	return errors.Wrapf(l.read(f), "failed to read breached password list %s", path)
+}
+
+// read adds one password per line, skipping blank lines and lines starting with "#".
+func (l *List) read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		l.passwords[strings.ToLower(line)] = struct{}{}
+	}
+
+	return scanner.Err()
+}
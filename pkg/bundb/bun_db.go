@@ -61,6 +61,7 @@ func NewBunDB(config *config.Config, logger logger.Logger) (*bunDB, error) {
 		hook.WithSlowQueryThreshold(time.Duration(config.MySQL.SlowQueryThreshold)*time.Millisecond),
 	))
 	db.AddQueryHook(hook.NewTracerHook())
+	db.AddQueryHook(hook.NewMetricsHook())
 
 	return &bunDB{
 		config: config,
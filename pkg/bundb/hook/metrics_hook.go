@@ -0,0 +1,29 @@
+package hook
+
+import (
+	"context"
+	"database/sql"
+	"goapptemp/pkg/metrics"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/uptrace/bun"
+)
+
+var _ bun.QueryHook = (*MetricsHook)(nil)
+
+type MetricsHook struct{}
+
+func NewMetricsHook() *MetricsHook {
+	return &MetricsHook{}
+}
+
+func (h *MetricsHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *MetricsHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	failed := event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) && !errors.Is(event.Err, sql.ErrTxDone)
+
+	metrics.ObserveDBQuery(event.Operation(), time.Since(event.StartTime), failed)
+}
@@ -0,0 +1,329 @@
+package emailsender
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const base64LineLength = 76
+
+var ErrNoRecipients = errors.New("email has no recipients")
+
+type EmailSender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Message is a driver-independent email. At least one of Text or HTML must be set; when both are,
+// they are sent as multipart/alternative so clients pick the richest part they can render.
+type Message struct {
+	To          []string
+	Cc          []string
+	Bcc         []string
+	ReplyTo     string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []*Attachment
+}
+
+// Recipients returns every envelope recipient, including Bcc.
+func (m *Message) Recipients() []string {
+	recipients := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	recipients = append(recipients, m.To...)
+	recipients = append(recipients, m.Cc...)
+
+	return append(recipients, m.Bcc...)
+}
+
+// Sender identifies the From address shared by every driver.
+type Sender struct {
+	Address string
+	Name    string
+	// ReplyTo is used when a message does not set its own.
+	ReplyTo string
+}
+
+// Encode renders msg as an RFC 5322 message. Bcc is only written as a header when withBcc is set,
+// for APIs such as Gmail that read the envelope from the headers and strip it themselves.
+func Encode(sender Sender, msg *Message, withBcc bool) ([]byte, error) {
+	if len(msg.Recipients()) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	if msg.Text == "" && msg.HTML == "" {
+		return nil, errors.New("email has no body")
+	}
+
+	from, err := mail.ParseAddress(sender.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid sender address")
+	}
+
+	from.Name = sender.Name
+
+	var buf bytes.Buffer
+
+	header := make(textproto.MIMEHeader)
+	header.Set("From", from.String())
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", messageID(from.Address))
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header.Set("MIME-Version", "1.0")
+
+	var bcc []string
+	if withBcc {
+		bcc = msg.Bcc
+	}
+
+	for _, h := range []struct {
+		name  string
+		value []string
+	}{
+		{"To", msg.To},
+		{"Cc", msg.Cc},
+		{"Bcc", bcc},
+		{"Reply-To", nonEmpty(msg.ReplyTo, sender.ReplyTo)},
+	} {
+		if len(h.value) == 0 {
+			continue
+		}
+
+		list, err := formatAddresses(h.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s address", h.name)
+		}
+
+		header.Set(h.name, list)
+	}
+
+	body, contentType, err := encodeBody(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range contentType {
+		header[k] = v
+	}
+
+	writeHeader(&buf, header)
+	buf.Write(body)
+
+	return buf.Bytes(), nil
+}
+
+// encodeBody returns the message body together with the headers that describe it.
+func encodeBody(msg *Message) ([]byte, textproto.MIMEHeader, error) {
+	content, contentHeader, err := encodeContent(msg)
+	if err != nil || len(msg.Attachments) == 0 {
+		return content, contentHeader, err
+	}
+
+	var buf bytes.Buffer
+
+	mixed := multipart.NewWriter(&buf)
+
+	part, err := mixed.CreatePart(contentHeader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to write email body")
+	}
+
+	if _, err := part.Write(content); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to write email body")
+	}
+
+	for _, attachment := range msg.Attachments {
+		if err := writeAttachment(mixed, attachment); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to write email body")
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", "multipart/mixed; boundary="+mixed.Boundary())
+
+	return buf.Bytes(), header, nil
+}
+
+func encodeContent(msg *Message) ([]byte, textproto.MIMEHeader, error) {
+	switch {
+	case msg.HTML == "":
+		return encodeText("text/plain", msg.Text)
+	case msg.Text == "":
+		return encodeText("text/html", msg.HTML)
+	}
+
+	var buf bytes.Buffer
+
+	alternative := multipart.NewWriter(&buf)
+
+	// Clients prefer the last alternative they understand, so HTML goes after the plain text.
+	for _, body := range []struct{ contentType, content string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		content, header, err := encodeText(body.contentType, body.content)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		part, err := alternative.CreatePart(header)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to write email body")
+		}
+
+		if _, err := part.Write(content); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to write email body")
+		}
+	}
+
+	if err := alternative.Close(); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to write email body")
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", "multipart/alternative; boundary="+alternative.Boundary())
+
+	return buf.Bytes(), header, nil
+}
+
+func encodeText(contentType, content string) ([]byte, textproto.MIMEHeader, error) {
+	var buf bytes.Buffer
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := io.WriteString(w, content); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encode email body")
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encode email body")
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", contentType+"; charset=UTF-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	return buf.Bytes(), header, nil
+}
+
+func writeAttachment(w *multipart.Writer, attachment *Attachment) error {
+	if attachment == nil {
+		return nil
+	}
+
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "base64")
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write attachment %s", attachment.Filename)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 0 {
+		n := min(base64LineLength, len(encoded))
+		if _, err := io.WriteString(part, encoded[:n]+"\r\n"); err != nil {
+			return errors.Wrapf(err, "failed to write attachment %s", attachment.Filename)
+		}
+
+		encoded = encoded[n:]
+	}
+
+	return nil
+}
+
+// writeHeader writes headers in a stable order so encoded messages are easy to diff in the file driver.
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	order := []string{"From", "To", "Cc", "Bcc", "Reply-To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type", "Content-Transfer-Encoding"}
+
+	for _, key := range order {
+		for _, value := range header.Values(key) {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+
+	buf.WriteString("\r\n")
+}
+
+// formatAddresses parses each address so that user-supplied values cannot inject extra headers.
+func formatAddresses(addresses []string) (string, error) {
+	formatted := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		parsed, err := mail.ParseAddress(address)
+		if err != nil {
+			return "", errors.Wrap(err, address)
+		}
+
+		formatted = append(formatted, parsed.String())
+	}
+
+	return strings.Join(formatted, ", "), nil
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if _, host, ok := strings.Cut(from, "@"); ok {
+		domain = host
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+func nonEmpty(values ...string) []string {
+	for _, v := range values {
+		if v != "" {
+			return []string{v}
+		}
+	}
+
+	return nil
+}
+
+// envelopeAddresses strips display names, as SMTP MAIL FROM and RCPT TO take bare addresses.
+func envelopeAddresses(addresses []string) ([]string, error) {
+	res := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		parsed, err := mail.ParseAddress(address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid recipient %s", address)
+		}
+
+		res = append(res, parsed.Address)
+	}
+
+	return res, nil
+}
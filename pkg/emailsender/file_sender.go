@@ -0,0 +1,63 @@
+package emailsender
+
+import (
+	"context"
+	"fmt"
+	"goapptemp/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
+)
+
+var _ EmailSender = (*fileSender)(nil)
+
+// fileSender is a mail catcher for development and tests: each message is written to dir as an
+// .eml file that any mail client can open. With an empty dir it only logs the envelope.
+type fileSender struct {
+	dir    string
+	sender Sender
+	logger logger.Logger
+}
+
+func NewFileSender(dir string, sender Sender, logger logger.Logger) (*fileSender, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, errors.Wrapf(err, "failed to create email directory %s", dir)
+		}
+	}
+
+	return &fileSender{
+		dir:    dir,
+		sender: sender,
+		logger: logger.NewInstance().Field("component", "email_file_sender").Logger(),
+	}, nil
+}
+
+func (s *fileSender) Send(ctx context.Context, msg *Message) error {
+	raw, err := Encode(s.sender, msg, true)
+	if err != nil {
+		return err
+	}
+
+	recipients := strings.Join(msg.Recipients(), ", ")
+
+	if s.dir == "" {
+		s.logger.Info().Msgf("Email %q to %s not delivered (log driver)", msg.Subject, recipients)
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405"), uuid.NewString()[:8])
+	path := filepath.Join(s.dir, name)
+
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write email to %s", path)
+	}
+
+	s.logger.Info().Msgf("Email %q to %s written to %s", msg.Subject, recipients, path)
+
+	return nil
+}
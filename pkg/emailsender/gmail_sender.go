@@ -1,10 +1,9 @@
-package gmailsender
+package emailsender
 
 import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"os"
 
@@ -15,18 +14,20 @@ import (
 	"google.golang.org/api/option"
 )
 
-var _ GmailSender = (*gmailSender)(nil)
-
-type GmailSender interface {
-	SendEmail(ctx context.Context, to, subject, body string) error
-}
+var _ EmailSender = (*gmailSender)(nil)
 
 type gmailSender struct {
 	gmailService *gmail.Service
-	senderEmail  string
+	sender       Sender
 }
 
-func NewGmailSender(ctx context.Context, credPath, tokenPath, senderEmail string) (*gmailSender, error) {
+// NewGmailSender sends through the Gmail API using an OAuth client secret file and a previously
+// authorised token file.
+func NewGmailSender(ctx context.Context, credPath, tokenPath string, sender Sender) (*gmailSender, error) {
+	if credPath == "" || tokenPath == "" {
+		return nil, errors.New("gmail credential and token files are required")
+	}
+
 	b, err := os.ReadFile(credPath)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to read client secret file")
@@ -49,7 +50,7 @@ func NewGmailSender(ctx context.Context, credPath, tokenPath, senderEmail string
 
 	return &gmailSender{
 		gmailService: srv,
-		senderEmail:  senderEmail,
+		sender:       sender,
 	}, nil
 }
 
@@ -75,26 +76,17 @@ func tokenFromFile(file string) (*oauth2.Token, error) {
 	return tok, err
 }
 
-func (s *gmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
-	messageStr := fmt.Sprintf(
-		"From: %s\r\n"+
-			"To: %s\r\n"+
-			"Subject: %s\r\n"+
-			"MIME-version: 1.0;\r\n"+
-			"Content-Type: text/html; charset=\"UTF-8\";\r\n\r\n"+
-			"%s",
-		s.senderEmail, to, subject, body,
-	)
-
-	messageBytes := []byte(messageStr)
-	encodedMessage := base64.URLEncoding.EncodeToString(messageBytes)
+func (s *gmailSender) Send(ctx context.Context, msg *Message) error {
+	raw, err := Encode(s.sender, msg, true)
+	if err != nil {
+		return err
+	}
 
 	gMessage := &gmail.Message{
-		Raw: encodedMessage,
+		Raw: base64.URLEncoding.EncodeToString(raw),
 	}
 
-	_, err := s.gmailService.Users.Messages.Send("me", gMessage).Context(ctx).Do()
-	if err != nil {
+	if _, err := s.gmailService.Users.Messages.Send("me", gMessage).Context(ctx).Do(); err != nil {
 		return errors.Wrap(err, "failed to send email via Gmail API")
 	}
 
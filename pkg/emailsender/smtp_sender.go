@@ -0,0 +1,170 @@
+package emailsender
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+type SMTPSecurity string
+
+const (
+	// SMTPSecurityStartTLS upgrades a plain connection and refuses servers that do not offer STARTTLS.
+	SMTPSecurityStartTLS SMTPSecurity = "starttls"
+	// SMTPSecurityTLS connects over TLS from the start, usually on port 465.
+	SMTPSecurityTLS SMTPSecurity = "tls"
+	// SMTPSecurityNone is only meant for local relays and mail catchers.
+	SMTPSecurityNone SMTPSecurity = "none"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Security SMTPSecurity
+	Timeout  time.Duration
+}
+
+var _ EmailSender = (*smtpSender)(nil)
+
+type smtpSender struct {
+	config SMTPConfig
+	sender Sender
+}
+
+func NewSMTPSender(config SMTPConfig, sender Sender) (*smtpSender, error) {
+	if config.Host == "" || config.Port <= 0 {
+		return nil, errors.New("smtp host and port are required")
+	}
+
+	switch config.Security {
+	case "":
+		config.Security = SMTPSecurityStartTLS
+	case SMTPSecurityStartTLS, SMTPSecurityTLS, SMTPSecurityNone:
+	default:
+		return nil, errors.Newf("unsupported smtp security %q", config.Security)
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &smtpSender{
+		config: config,
+		sender: sender,
+	}, nil
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg *Message) error {
+	raw, err := Encode(s.sender, msg, false)
+	if err != nil {
+		return err
+	}
+
+	from, err := envelopeAddresses([]string{s.sender.Address})
+	if err != nil {
+		return err
+	}
+
+	recipients, err := envelopeAddresses(msg.Recipients())
+	if err != nil {
+		return err
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Mail(from[0]); err != nil {
+		return errors.Wrap(err, "smtp MAIL FROM rejected")
+	}
+
+	for _, rcpt := range recipients {
+		if err := client.Rcpt(rcpt); err != nil {
+			return errors.Wrapf(err, "smtp RCPT TO %s rejected", rcpt)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "smtp DATA rejected")
+	}
+
+	if _, err := w.Write(raw); err != nil {
+		return errors.Wrap(err, "failed to write smtp message")
+	}
+
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "smtp server rejected message")
+	}
+
+	return client.Quit()
+}
+
+// dial connects, negotiates TLS and authenticates. The whole conversation shares one deadline
+// taken from ctx or the configured timeout, whichever comes first.
+func (s *smtpSender) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	tlsConfig := &tls.Config{ServerName: s.config.Host, MinVersion: tls.VersionTLS12}
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if s.config.Security == SMTPSecurityTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to smtp server %s", addr)
+	}
+
+	deadline := time.Now().Add(s.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to set smtp deadline")
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to start smtp session")
+	}
+
+	if s.config.Security == SMTPSecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, errors.New("smtp server does not support STARTTLS")
+		}
+
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, errors.Wrap(err, "smtp STARTTLS failed")
+		}
+	}
+
+	if s.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)); err != nil {
+			client.Close()
+			return nil, errors.Wrap(err, "smtp authentication failed")
+		}
+	}
+
+	return client, nil
+}
@@ -0,0 +1,380 @@
+package mailtemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	layoutDir  = "layouts"
+	partialDir = "partials"
+	sampleDir  = "samples"
+
+	htmlExt = ".html.tmpl"
+	textExt = ".txt.tmpl"
+
+	defaultLocale = "en"
+)
+
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// Rendered is a template executed for one locale; Subject comes from the "subject" block of the text
+// variant and Summary from its optional "summary" block, a one-line version for in-app notifications.
+type Rendered struct {
+	Name    string
+	Locale  string
+	Subject string
+	Summary string
+	HTML    string
+	Text    string
+}
+
+type TemplateInfo struct {
+	Name    string
+	Locales []string
+	Sample  map[string]any
+}
+
+// view is the root value every template sees; call-specific values live under .Data.
+type view struct {
+	AppName string
+	Locale  string
+	Year    int
+	Data    any
+}
+
+type compiled struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+var _ Registry = (*registry)(nil)
+
+type Registry interface {
+	Render(name, locale string, data any) (*Rendered, error)
+	Templates() []*TemplateInfo
+	Sample(name string) (map[string]any, error)
+	Locales() []string
+}
+
+type Option func(r *registry)
+
+func WithDefaultLocale(locale string) Option {
+	return func(r *registry) {
+		if locale != "" {
+			r.defaultLocale = NormalizeLocale(locale)
+		}
+	}
+}
+
+func WithAppName(name string) Option {
+	return func(r *registry) {
+		r.appName = name
+	}
+}
+
+type registry struct {
+	appName       string
+	defaultLocale string
+	// templates is keyed by name, then locale.
+	templates map[string]map[string]*compiled
+	samples   map[string]map[string]any
+}
+
+// NewRegistry parses every template in fsys up front so a broken template fails startup rather than a send.
+// fsys holds layouts/ and partials/ shared by all templates, one directory per locale containing
+// <name>.html.tmpl and <name>.txt.tmpl pairs plus an optional partials/ directory whose blocks
+// override the shared ones, and optional samples/<name>.json preview data.
+func NewRegistry(fsys fs.FS, opts ...Option) (*registry, error) {
+	r := &registry{
+		defaultLocale: defaultLocale,
+		templates:     make(map[string]map[string]*compiled),
+		samples:       make(map[string]map[string]any),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read email templates")
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		switch entry.Name() {
+		case layoutDir, partialDir:
+			continue
+		case sampleDir:
+			err = r.loadSamples(fsys)
+		default:
+			err = r.loadLocale(fsys, entry.Name())
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for name, locales := range r.templates {
+		if _, ok := locales[r.defaultLocale]; !ok {
+			return nil, errors.Newf("email template %s has no %s variant", name, r.defaultLocale)
+		}
+	}
+
+	return r, nil
+}
+
+func (r *registry) loadLocale(fsys fs.FS, locale string) error {
+	files, err := fs.Glob(fsys, path.Join(locale, "*"+htmlExt))
+	if err != nil {
+		return errors.Wrapf(err, "failed to list %s email templates", locale)
+	}
+
+	htmlBase, err := parseBase(fsys, locale, htmlExt, htmltemplate.New("").Option("missingkey=error").Funcs(htmlFuncs).ParseFS)
+	if err != nil {
+		return err
+	}
+
+	textBase, err := parseBase(fsys, locale, textExt, texttemplate.New("").Option("missingkey=error").Funcs(textFuncs).ParseFS)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), htmlExt)
+		textFile := path.Join(locale, name+textExt)
+
+		html, err := htmlBase.Clone()
+		if err != nil {
+			return errors.Wrapf(err, "failed to clone %s layout", locale)
+		}
+
+		if html, err = html.ParseFS(fsys, file); err != nil {
+			return errors.Wrapf(err, "failed to parse %s", file)
+		}
+
+		text, err := textBase.Clone()
+		if err != nil {
+			return errors.Wrapf(err, "failed to clone %s layout", locale)
+		}
+
+		if text, err = text.ParseFS(fsys, textFile); err != nil {
+			return errors.Wrapf(err, "failed to parse %s", textFile)
+		}
+
+		if text.Lookup("subject") == nil {
+			return errors.Newf("email template %s does not define a subject", textFile)
+		}
+
+		if r.templates[name] == nil {
+			r.templates[name] = make(map[string]*compiled)
+		}
+
+		r.templates[name][NormalizeLocale(locale)] = &compiled{html: html, text: text}
+	}
+
+	return nil
+}
+
+// parseBase parses the shared layouts and partials followed by the locale's own partials, so that a
+// block defined by the locale wins.
+func parseBase[T any](fsys fs.FS, locale, ext string, parse func(fs.FS, ...string) (T, error)) (T, error) {
+	patterns := []string{layoutDir + "/*" + ext, partialDir + "/*" + ext}
+
+	localePartials := path.Join(locale, partialDir, "*"+ext)
+	if matches, _ := fs.Glob(fsys, localePartials); len(matches) > 0 {
+		patterns = append(patterns, localePartials)
+	}
+
+	tmpl, err := parse(fsys, patterns...)
+	if err != nil {
+		var zero T
+		return zero, errors.Wrapf(err, "failed to parse %s layouts", locale)
+	}
+
+	return tmpl, nil
+}
+
+func (r *registry) loadSamples(fsys fs.FS) error {
+	files, err := fs.Glob(fsys, sampleDir+"/*.json")
+	if err != nil {
+		return errors.Wrap(err, "failed to list email template samples")
+	}
+
+	for _, file := range files {
+		raw, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", file)
+		}
+
+		sample := make(map[string]any)
+		if err := json.Unmarshal(raw, &sample); err != nil {
+			return errors.Wrapf(err, "failed to decode %s", file)
+		}
+
+		r.samples[strings.TrimSuffix(path.Base(file), ".json")] = sample
+	}
+
+	return nil
+}
+
+// Render picks the closest variant for locale ("id-ID" falls back to "id", then to the default
+// locale) and executes its subject, HTML and text parts.
+func (r *registry) Render(name, locale string, data any) (*Rendered, error) {
+	locales, ok := r.templates[name]
+	if !ok {
+		return nil, errors.Wrap(ErrTemplateNotFound, name)
+	}
+
+	locale = r.resolveLocale(locales, locale)
+	tmpl := locales[locale]
+
+	v := &view{
+		AppName: r.appName,
+		Locale:  locale,
+		Year:    time.Now().Year(),
+		Data:    data,
+	}
+
+	var subject, summary, text, html bytes.Buffer
+
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", v); err != nil {
+		return nil, errors.Wrapf(err, "failed to render %s subject", name)
+	}
+
+	if tmpl.text.Lookup("summary") != nil {
+		if err := tmpl.text.ExecuteTemplate(&summary, "summary", v); err != nil {
+			return nil, errors.Wrapf(err, "failed to render %s summary", name)
+		}
+	}
+
+	if err := tmpl.text.ExecuteTemplate(&text, "layout", v); err != nil {
+		return nil, errors.Wrapf(err, "failed to render %s text", name)
+	}
+
+	if err := tmpl.html.ExecuteTemplate(&html, "layout", v); err != nil {
+		return nil, errors.Wrapf(err, "failed to render %s html", name)
+	}
+
+	return &Rendered{
+		Name:    name,
+		Locale:  locale,
+		Subject: strings.TrimSpace(subject.String()),
+		Summary: strings.TrimSpace(summary.String()),
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
+
+func (r *registry) Templates() []*TemplateInfo {
+	res := make([]*TemplateInfo, 0, len(r.templates))
+
+	for name, locales := range r.templates {
+		info := &TemplateInfo{
+			Name:    name,
+			Locales: make([]string, 0, len(locales)),
+			Sample:  r.samples[name],
+		}
+
+		for locale := range locales {
+			info.Locales = append(info.Locales, locale)
+		}
+
+		sort.Strings(info.Locales)
+
+		res = append(res, info)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res
+}
+
+func (r *registry) Sample(name string) (map[string]any, error) {
+	if _, ok := r.templates[name]; !ok {
+		return nil, errors.Wrap(ErrTemplateNotFound, name)
+	}
+
+	return r.samples[name], nil
+}
+
+func (r *registry) Locales() []string {
+	seen := make(map[string]struct{})
+
+	for _, locales := range r.templates {
+		for locale := range locales {
+			seen[locale] = struct{}{}
+		}
+	}
+
+	res := make([]string, 0, len(seen))
+	for locale := range seen {
+		res = append(res, locale)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func (r *registry) resolveLocale(available map[string]*compiled, locale string) string {
+	locale = NormalizeLocale(locale)
+	if _, ok := available[locale]; ok {
+		return locale
+	}
+
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		if _, ok := available[base]; ok {
+			return base
+		}
+	}
+
+	return r.defaultLocale
+}
+
+// NormalizeLocale lower-cases a language tag and uses "-" as the separator, so "pt_BR" becomes "pt-br".
+func NormalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
+
+var htmlFuncs = htmltemplate.FuncMap{
+	"dict": dict,
+}
+
+var textFuncs = texttemplate.FuncMap{
+	"dict": dict,
+}
+
+// dict builds a map from alternating keys and values so partials can take named arguments.
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict requires an even number of arguments")
+	}
+
+	res := make(map[string]any, len(pairs)/2)
+
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, errors.Newf("dict key %v is not a string", pairs[i])
+		}
+
+		res[key] = pairs[i+1]
+	}
+
+	return res, nil
+}
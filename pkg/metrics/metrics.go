@@ -0,0 +1,168 @@
+// Package metrics holds the Prometheus collectors of the service. They live in one process-wide
+// registry so the HTTP, database, Redis and job instrumentation can record into it without
+// threading a handle through every constructor.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "goapptemp"
+
+// Login methods.
+const (
+	LoginPassword = "password"
+	LoginSSO      = "sso"
+)
+
+// Background job kinds.
+const (
+	JobTask      = "task"
+	JobScheduled = "scheduled"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests handled, by route template, method and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency, by route template, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "mysql",
+		Name:      "query_duration_seconds",
+		Help:      "SQL query latency, by operation.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation"})
+
+	dbErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mysql",
+		Name:      "query_errors_total",
+		Help:      "SQL queries that failed, by operation. Empty results are not counted.",
+	}, []string{"operation"})
+
+	redisDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_duration_seconds",
+		Help:      "Redis command latency, by command; pipelines are recorded as one \"pipeline\" command.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"command"})
+
+	redisErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_errors_total",
+		Help:      "Redis commands that failed, by command. Missing keys are not counted.",
+	}, []string{"command"})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "logins_total",
+		Help:      "Login attempts, by method and result.",
+	}, []string{"method", "result"})
+
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "job",
+		Name:      "duration_seconds",
+		Help:      "Background job run time, by kind (task or scheduled), name and result.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300, 900},
+	}, []string{"kind", "name", "result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		dbDuration,
+		dbErrors,
+		redisDuration,
+		redisErrors,
+		logins,
+		jobDuration,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// RegisterDBStats exposes the connection pool statistics of db labelled with name. Registering the
+// same name again is a no-op.
+func RegisterDBStats(db *sql.DB, name string) error {
+	err := registry.Register(collectors.NewDBStatsCollector(db, name))
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+
+	return err
+}
+
+// ObserveHTTPRequest records a handled request. route must be the route template, not the raw path,
+// to keep the label cardinality bounded.
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func ObserveDBQuery(operation string, duration time.Duration, failed bool) {
+	dbDuration.WithLabelValues(operation).Observe(duration.Seconds())
+
+	if failed {
+		dbErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+func ObserveRedisCommand(command string, duration time.Duration, failed bool) {
+	redisDuration.WithLabelValues(command).Observe(duration.Seconds())
+
+	if failed {
+		redisErrors.WithLabelValues(command).Inc()
+	}
+}
+
+func ObserveLogin(method string, succeeded bool) {
+	logins.WithLabelValues(method, result(succeeded)).Inc()
+}
+
+func ObserveJob(kind, name string, duration time.Duration, succeeded bool) {
+	jobDuration.WithLabelValues(kind, name, result(succeeded)).Observe(duration.Seconds())
+}
+
+func result(succeeded bool) string {
+	if succeeded {
+		return "success"
+	}
+
+	return "failure"
+}
@@ -0,0 +1,372 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const (
+	discoveryPath      = "/.well-known/openid-configuration"
+	defaultHTTPTimeout = 10 * time.Second
+	// keyRefreshInterval limits how often an unknown key id can trigger a JWKS refetch.
+	keyRefreshInterval = time.Minute
+	maxResponseSize    = 1 << 20
+)
+
+var (
+	ErrDiscovery     = errors.New("oidc discovery failed")
+	ErrExchange      = errors.New("oidc code exchange failed")
+	ErrInvalidToken  = errors.New("invalid id token")
+	ErrNonceMismatch = errors.New("id token nonce mismatch")
+)
+
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims holds the ID token claims the application relies on.
+type Claims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	Email             string `json:"email"`
+	EmailVerified     Bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// Bool accepts both JSON booleans and the "true"/"false" strings some providers send.
+type Bool bool
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null", "":
+		*b = false
+	default:
+		return errors.Newf("invalid boolean %s", data)
+	}
+
+	return nil
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+var _ Provider = (*provider)(nil)
+
+type Provider interface {
+	// AuthCodeURL builds the authorization request, binding state, nonce and the PKCE verifier's challenge.
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	// Exchange redeems the code with its PKCE verifier and returns the verified ID token claims.
+	Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error)
+}
+
+type provider struct {
+	config Config
+	client *http.Client
+
+	mu        sync.Mutex
+	meta      *metadata
+	keys      map[string]crypto.PublicKey
+	keysFetch time.Time
+}
+
+type Option func(p *provider)
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *provider) {
+		if client != nil {
+			p.client = client
+		}
+	}
+}
+
+// NewProvider does no network I/O; discovery runs on first use and is cached, so an identity
+// provider that is down at startup does not keep the application from booting.
+func NewProvider(config Config, opts ...Option) *provider {
+	p := &provider{
+		config: config,
+		client: &http.Client{Timeout: defaultHTTPTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if len(p.config.Scopes) == 0 {
+		p.config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return p
+}
+
+func NewVerifier() string {
+	return oauth2.GenerateVerifier()
+}
+
+func (p *provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return oauthConfig.AuthCodeURL(state,
+		oauth2.S256ChallengeOption(verifier),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	), nil
+}
+
+func (p *provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+
+	token, err := oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, errors.Mark(errors.Wrap(err, "failed to exchange authorization code"), ErrExchange)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.Mark(errors.New("token response has no id_token"), ErrExchange)
+	}
+
+	return p.verify(ctx, rawIDToken, nonce)
+}
+
+func (p *provider) verify(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := new(Claims)
+
+	_, err = jwt.ParseWithClaims(rawIDToken, claims,
+		func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+
+			return p.key(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, errors.Mark(errors.Wrap(err, "failed to verify id token"), ErrInvalidToken)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	return claims, nil
+}
+
+func (p *provider) oauthConfig(ctx context.Context) (*oauth2.Config, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       p.config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  meta.AuthorizationEndpoint,
+			TokenURL: meta.TokenEndpoint,
+		},
+	}, nil
+}
+
+func (p *provider) metadata(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	meta := new(metadata)
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.config.IssuerURL, "/")+discoveryPath, meta); err != nil {
+		return nil, errors.Mark(err, ErrDiscovery)
+	}
+
+	if meta.Issuer != strings.TrimSuffix(p.config.IssuerURL, "/") && meta.Issuer != p.config.IssuerURL {
+		return nil, errors.Mark(errors.Newf("issuer %q does not match configured %q", meta.Issuer, p.config.IssuerURL), ErrDiscovery)
+	}
+
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.Mark(errors.New("provider metadata is missing required endpoints"), ErrDiscovery)
+	}
+
+	p.meta = meta
+
+	return meta, nil
+}
+
+// key returns the signing key for kid, refetching the key set when the provider has rotated keys.
+func (p *provider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	if p.keys != nil && time.Since(p.keysFetch) < keyRefreshInterval {
+		return nil, errors.Newf("unknown signing key %q", kid)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := p.getJSON(ctx, p.meta.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	p.keys = keys
+	p.keysFetch = time.Now()
+
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, errors.Newf("unknown signing key %q", kid)
+}
+
+func (p *provider) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to build request for %s", url)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch %s", url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Newf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v); err != nil {
+		return errors.Wrapf(err, "failed to decode %s", url)
+	}
+
+	return nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Newf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, errors.Newf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid base64url value")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,187 @@
+package policy
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultPolicies documents the file format; it ships without active policies.
+//
+//go:embed default_policies.yaml
+var defaultPolicies []byte
+
+type document struct {
+	Policies []*Policy `yaml:"policies"`
+}
+
+// Engine evaluates the policies loaded at startup. It is safe for concurrent use.
+type Engine struct {
+	policies []*Policy
+}
+
+// New loads the embedded defaults and the YAML files in paths. schema lists, per resource, the
+// fields policies may reference; a policy naming anything else fails loading instead of silently
+// never matching.
+func New(schema map[string][]string, paths ...string) (*Engine, error) {
+	e := &Engine{}
+
+	if err := e.load(defaultPolicies, schema); err != nil {
+		return nil, errors.Wrap(err, "load default policies")
+	}
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read policy file %s", path)
+		}
+
+		if err := e.load(data, schema); err != nil {
+			return nil, errors.Wrapf(err, "load policy file %s", path)
+		}
+	}
+
+	return e, nil
+}
+
+func (e *Engine) load(data []byte, schema map[string][]string) error {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	for i, policy := range doc.Policies {
+		if policy == nil {
+			continue
+		}
+
+		if err := validate(policy, schema); err != nil {
+			return fmt.Errorf("policy %d (%s): %w", i, policy.Name, err)
+		}
+
+		e.policies = append(e.policies, policy)
+	}
+
+	return nil
+}
+
+func validate(policy *Policy, schema map[string][]string) error {
+	if policy.Effect != EffectAllow && policy.Effect != EffectDeny {
+		return fmt.Errorf("unknown effect %q", policy.Effect)
+	}
+
+	fields, ok := schema[policy.Resource]
+	if !ok {
+		return fmt.Errorf("unknown resource %q", policy.Resource)
+	}
+
+	if len(policy.Actions) == 0 {
+		return errors.New("at least one action is required")
+	}
+
+	for i := range policy.Conditions {
+		if err := policy.Conditions[i].validate(fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Engine) Len() int {
+	if e == nil {
+		return 0
+	}
+
+	return len(e.policies)
+}
+
+// Governs reports whether any policy mentions the resource and action, regardless of subject.
+// Callers use it to skip loading the subject when policies cannot apply.
+func (e *Engine) Governs(resource, action string) bool {
+	if e == nil {
+		return false
+	}
+
+	for _, policy := range e.policies {
+		if policy.Resource == resource && containsAction(policy, action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter combines the policies that apply to subject into predicates for a list query. It returns
+// nil when nothing restricts the subject. Super admins are never restricted.
+func (e *Engine) Filter(subject *Subject, resource, action string) *Filter {
+	if e == nil || subject == nil || subject.SuperAdmin {
+		return nil
+	}
+
+	var (
+		filter        Filter
+		applies       bool
+		unconditional bool
+	)
+
+	for _, policy := range e.policies {
+		if !policy.appliesTo(subject, resource, action) {
+			continue
+		}
+
+		applies = true
+
+		clause := make([]Predicate, 0, len(policy.Conditions))
+		for i := range policy.Conditions {
+			clause = append(clause, policy.Conditions[i].resolve(subject))
+		}
+
+		switch policy.Effect {
+		case EffectDeny:
+			filter.Deny = append(filter.Deny, clause)
+		case EffectAllow:
+			if len(clause) == 0 {
+				unconditional = true
+			}
+
+			filter.Allow = append(filter.Allow, clause)
+		}
+	}
+
+	if !applies {
+		return nil
+	}
+
+	if unconditional {
+		filter.Allow = nil
+	}
+
+	if filter.Allow == nil && filter.Deny == nil {
+		return nil
+	}
+
+	return &filter
+}
+
+// Allowed evaluates the policies for a single resource.
+func (e *Engine) Allowed(subject *Subject, resource, action string, attributes Resource) bool {
+	return e.Filter(subject, resource, action).Matches(attributes)
+}
+
+func containsAction(policy *Policy, action string) bool {
+	for _, candidate := range policy.Actions {
+		if candidate == action {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package policy
+
+import "fmt"
+
+// Predicate is a condition with subject references already resolved.
+type Predicate struct {
+	Field  string
+	Op     Operator
+	Values []any
+}
+
+// Matches compares values by their formatted form, so YAML integers match uint attributes.
+func (p *Predicate) Matches(resource Resource) bool {
+	value, ok := resource[p.Field]
+	if !ok {
+		return false
+	}
+
+	found := false
+
+	for _, candidate := range p.Values {
+		if fmt.Sprint(candidate) == fmt.Sprint(value) {
+			found = true
+			break
+		}
+	}
+
+	if p.Op == OpNe {
+		return !found
+	}
+
+	return found
+}
+
+// Filter is what the policies allow for a list query. A row is visible when it matches every
+// predicate of at least one Allow clause, or Allow is nil, and matches no Deny clause entirely.
+type Filter struct {
+	Allow [][]Predicate
+	Deny  [][]Predicate
+}
+
+func (f *Filter) Matches(resource Resource) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, clause := range f.Deny {
+		if matchesAll(clause, resource) {
+			return false
+		}
+	}
+
+	if f.Allow == nil {
+		return true
+	}
+
+	for _, clause := range f.Allow {
+		if matchesAll(clause, resource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesAll(clause []Predicate, resource Resource) bool {
+	for i := range clause {
+		if !clause[i].Matches(resource) {
+			return false
+		}
+	}
+
+	return true
+}
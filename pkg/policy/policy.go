@@ -0,0 +1,148 @@
+// Package policy evaluates attribute-based access policies on top of permission checks. A policy
+// narrows what a subject may do with a kind of resource; resources no policy mentions stay
+// governed by permissions alone.
+package policy
+
+import (
+	"fmt"
+	"slices"
+)
+
+type Effect string
+
+const (
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+type Operator string
+
+const (
+	// OpEq matches when the attribute equals one of the condition's values.
+	OpEq Operator = "eq"
+	// OpNe matches when the attribute equals none of the condition's values.
+	OpNe Operator = "ne"
+)
+
+// Subject attributes a condition can compare against with its subject key.
+const (
+	SubjectUserID    = "user_id"
+	SubjectCompanyID = "company_id"
+	SubjectRoleIDs   = "role_ids"
+	SubjectRoleCodes = "role_codes"
+)
+
+// Policy applies to the subjects selected by Subject when they perform one of Actions on Resource.
+// An allow policy limits those subjects to resources matching all of its conditions; a deny policy
+// forbids resources matching all of its conditions. A policy without conditions matches everything.
+type Policy struct {
+	Name        string          `yaml:"name"`
+	Description string          `yaml:"description"`
+	Effect      Effect          `yaml:"effect"`
+	Resource    string          `yaml:"resource"`
+	Actions     []string        `yaml:"actions"`
+	Subject     SubjectSelector `yaml:"subject"`
+	Conditions  []Condition     `yaml:"conditions"`
+}
+
+// SubjectSelector picks subjects by role code. Empty Roles selects every subject.
+type SubjectSelector struct {
+	Roles        []string `yaml:"roles"`
+	ExcludeRoles []string `yaml:"exclude_roles"`
+}
+
+// Condition compares a resource attribute with literal values or with an attribute of the subject.
+type Condition struct {
+	Field   string   `yaml:"field"`
+	Op      Operator `yaml:"op"`
+	Values  []any    `yaml:"values"`
+	Subject string   `yaml:"subject"`
+}
+
+type Subject struct {
+	UserID     uint
+	CompanyID  uint
+	RoleIDs    []uint
+	RoleCodes  []string
+	SuperAdmin bool
+}
+
+// Resource holds the attributes of one resource, keyed by the field names policies use.
+type Resource map[string]any
+
+func (p *Policy) appliesTo(subject *Subject, resource, action string) bool {
+	if p.Resource != resource || !slices.Contains(p.Actions, action) {
+		return false
+	}
+
+	for _, code := range p.Subject.ExcludeRoles {
+		if slices.Contains(subject.RoleCodes, code) {
+			return false
+		}
+	}
+
+	if len(p.Subject.Roles) == 0 {
+		return true
+	}
+
+	for _, code := range p.Subject.Roles {
+		if slices.Contains(subject.RoleCodes, code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolve replaces subject references with the subject's values.
+func (c *Condition) resolve(subject *Subject) Predicate {
+	predicate := Predicate{Field: c.Field, Op: c.Op, Values: c.Values}
+
+	switch c.Subject {
+	case "":
+	case SubjectUserID:
+		predicate.Values = []any{subject.UserID}
+	case SubjectCompanyID:
+		predicate.Values = []any{subject.CompanyID}
+	case SubjectRoleIDs:
+		predicate.Values = toAny(subject.RoleIDs)
+	case SubjectRoleCodes:
+		predicate.Values = toAny(subject.RoleCodes)
+	}
+
+	return predicate
+}
+
+func (c *Condition) validate(fields []string) error {
+	if !slices.Contains(fields, c.Field) {
+		return fmt.Errorf("unknown field %q", c.Field)
+	}
+
+	if c.Op != OpEq && c.Op != OpNe {
+		return fmt.Errorf("field %q: unknown operator %q", c.Field, c.Op)
+	}
+
+	switch c.Subject {
+	case "":
+		if len(c.Values) == 0 {
+			return fmt.Errorf("field %q: either values or subject is required", c.Field)
+		}
+	case SubjectUserID, SubjectCompanyID, SubjectRoleIDs, SubjectRoleCodes:
+		if len(c.Values) != 0 {
+			return fmt.Errorf("field %q: values and subject are mutually exclusive", c.Field)
+		}
+	default:
+		return fmt.Errorf("field %q: unknown subject attribute %q", c.Field, c.Subject)
+	}
+
+	return nil
+}
+
+func toAny[T any](values []T) []any {
+	res := make([]any, 0, len(values))
+	for _, value := range values {
+		res = append(res, value)
+	}
+
+	return res
+}
@@ -12,8 +12,10 @@ import (
 
 type Pubsub interface {
 	NewPublisher(ctx context.Context, topicID string) (*pubsub.Topic, error)
+	NewSubscriber(ctx context.Context, subscriptionID string) (*pubsub.Subscription, error)
 	Shutdown() error
 }
+
 type pubsubClient struct {
 	client *pubsub.Client
 }
@@ -63,6 +65,21 @@ func (p *pubsubClient) NewPublisher(ctx context.Context, topicID string) (*pubsu
 	return topic, nil
 }
 
+func (p *pubsubClient) NewSubscriber(ctx context.Context, subscriptionID string) (*pubsub.Subscription, error) {
+	subscription := p.client.Subscription(subscriptionID)
+
+	exists, err := subscription.Exists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if subscription exists: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("subscription %q does not exist", subscriptionID)
+	}
+
+	return subscription, nil
+}
+
 func (p *pubsubClient) Shutdown() error {
 	if p.client != nil {
 		return p.client.Close()
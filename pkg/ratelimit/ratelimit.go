@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+var ErrInvalidQuota = errors.New("invalid rate limit quota")
+
+type Algorithm string
+
+const (
+	// SlidingWindow approximates a rolling window by weighting the previous fixed window's count
+	// by how much of it still overlaps the rolling window.
+	SlidingWindow Algorithm = "sliding_window"
+	// TokenBucket refills Limit tokens per Window up to Burst, allowing short spikes above the
+	// average rate.
+	TokenBucket Algorithm = "token_bucket"
+)
+
+// Quota allows Limit requests per Window. Name namespaces the counters, so two quotas sharing a
+// name share their budget.
+type Quota struct {
+	Name      string
+	Limit     int
+	Window    time.Duration
+	Algorithm Algorithm
+	// Burst is the token bucket capacity; it defaults to Limit and is ignored by SlidingWindow.
+	Burst int
+}
+
+func (q Quota) validate() error {
+	if q.Name == "" {
+		return errors.Wrap(ErrInvalidQuota, "name is required")
+	}
+
+	if q.Limit <= 0 || q.Window <= 0 {
+		return errors.Wrapf(ErrInvalidQuota, "%s: limit and window must be positive", q.Name)
+	}
+
+	switch q.Algorithm {
+	case SlidingWindow, TokenBucket:
+		return nil
+	default:
+		return errors.Wrapf(ErrInvalidQuota, "%s: unknown algorithm %q", q.Name, q.Algorithm)
+	}
+}
+
+// Policy renders the quota in the RateLimit-Policy header format, e.g. "100;w=60".
+func (q Quota) Policy() string {
+	return fmt.Sprintf("%d;w=%d", q.Limit, int(math.Ceil(q.Window.Seconds())))
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is how long until the full quota is available again.
+	ResetAfter time.Duration
+	// RetryAfter is how long a denied caller should wait before the next request can pass.
+	RetryAfter time.Duration
+}
+
+// Store keeps the counters. Implementations must apply each call atomically so concurrent
+// requests across instances cannot overspend a quota.
+type Store interface {
+	AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*Result, error)
+	AllowTokenBucket(ctx context.Context, key string, capacity, limit int, window time.Duration, now time.Time) (*Result, error)
+}
+
+var _ Limiter = (*limiter)(nil)
+
+type Limiter interface {
+	Allow(ctx context.Context, quota Quota, key string) (*Result, error)
+}
+
+type limiter struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewLimiter(store Store) *limiter {
+	return &limiter{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Allow consumes one request from key's budget under quota.
+func (l *limiter) Allow(ctx context.Context, quota Quota, key string) (*Result, error) {
+	if err := quota.validate(); err != nil {
+		return nil, err
+	}
+
+	storeKey := quota.Name + ":" + key
+
+	var (
+		result *Result
+		err    error
+	)
+
+	switch quota.Algorithm {
+	case TokenBucket:
+		burst := quota.Burst
+		if burst <= 0 {
+			burst = quota.Limit
+		}
+
+		result, err = l.store.AllowTokenBucket(ctx, storeKey, burst, quota.Limit, quota.Window, l.now())
+	default:
+		result, err = l.store.AllowSlidingWindow(ctx, storeKey, quota.Limit, quota.Window, l.now())
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to apply rate limit %s", quota.Name)
+	}
+
+	if result.Limit == 0 {
+		result.Limit = quota.Limit
+	}
+
+	return result, nil
+}
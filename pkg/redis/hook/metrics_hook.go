@@ -0,0 +1,56 @@
+package redishook
+
+import (
+	"context"
+	"errors"
+	"goapptemp/pkg/metrics"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ redis.Hook = (*MetricsHook)(nil)
+
+type MetricsHook struct{}
+
+func NewMetricsHook() *MetricsHook {
+	return &MetricsHook{}
+}
+
+func (h *MetricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *MetricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+
+		metrics.ObserveRedisCommand(cmd.Name(), time.Since(start), err != nil && !errors.Is(err, redis.Nil))
+
+		return err
+	}
+}
+
+func (h *MetricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+
+		failed := err != nil && !errors.Is(err, redis.Nil)
+		for _, cmd := range cmds {
+			if cmd.Err() != nil && !errors.Is(cmd.Err(), redis.Nil) {
+				failed = true
+
+				break
+			}
+		}
+
+		metrics.ObserveRedisCommand("pipeline", time.Since(start), failed)
+
+		return err
+	}
+}
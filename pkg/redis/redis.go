@@ -46,6 +46,7 @@ func NewRedisClient(config *config.Config, logger logger.Logger) (*redisClient,
 
 	db.AddHook(tracerHook)
 	db.AddHook(loggerHook)
+	db.AddHook(redishook.NewMetricsHook())
 
 	if _, err := db.Ping(ctx).Result(); err != nil {
 		log.Fatalf("Tidak dapat terhubung ke Redis: %v", err)
@@ -0,0 +1,412 @@
+package scheduler
+
+import (
+	"context"
+	"goapptemp/pkg/logger"
+	"goapptemp/pkg/metrics"
+	"os"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	apm "go.elastic.co/apm/v2"
+)
+
+const (
+	defaultTimeout     = 5 * time.Minute
+	defaultHistorySize = 50
+	lockMargin         = 30 * time.Second
+	slotMargin         = 5 * time.Second
+)
+
+var (
+	ErrJobNotFound      = errors.New("job not found")
+	ErrJobAlreadyExists = errors.New("job already registered")
+	ErrJobRunning       = errors.New("job is already running")
+)
+
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+type RunTrigger string
+
+const (
+	TriggerSchedule RunTrigger = "schedule"
+	TriggerManual   RunTrigger = "manual"
+)
+
+type Job struct {
+	Name        string
+	Description string
+	// Schedule is a standard five-field cron expression or a descriptor such as "@hourly" or "@every 30s".
+	Schedule string
+	Timeout  time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Run struct {
+	ID         string     `json:"id"`
+	Job        string     `json:"job"`
+	Trigger    RunTrigger `json:"trigger"`
+	Status     RunStatus  `json:"status"`
+	Node       string     `json:"node"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+	Error      string     `json:"error,omitempty"`
+}
+
+type JobInfo struct {
+	Name        string
+	Description string
+	Schedule    string
+	Timeout     time.Duration
+	NextRunAt   *time.Time
+	Running     bool
+	LastRun     *Run
+}
+
+// Store holds the distributed lock and run history shared by every replica.
+type Store interface {
+	AcquireLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, key, token string) error
+	SaveJobRun(ctx context.Context, run *Run, limit int) error
+	FindJobRuns(ctx context.Context, job string, limit int) ([]*Run, error)
+}
+
+var _ Scheduler = (*scheduler)(nil)
+
+type Scheduler interface {
+	Register(job Job) error
+	Start(ctx context.Context)
+	Jobs(ctx context.Context) []*JobInfo
+	Runs(ctx context.Context, name string, limit int) ([]*Run, error)
+	Trigger(ctx context.Context, name string) (*Run, error)
+}
+
+type Option func(s *scheduler)
+
+func WithHistorySize(size int) Option {
+	return func(s *scheduler) {
+		if size > 0 {
+			s.historySize = size
+		}
+	}
+}
+
+func WithNode(node string) Option {
+	return func(s *scheduler) {
+		if node != "" {
+			s.node = node
+		}
+	}
+}
+
+type registeredJob struct {
+	job     Job
+	entryID cron.EntryID
+}
+
+type scheduler struct {
+	store       Store
+	logger      logger.Logger
+	parser      cron.Parser
+	cron        *cron.Cron
+	node        string
+	historySize int
+	mu          sync.RWMutex
+	jobs        map[string]*registeredJob
+	running     map[string]*Run
+	baseCtx     context.Context
+	wg          sync.WaitGroup
+}
+
+func NewScheduler(store Store, logger logger.Logger, opts ...Option) *scheduler {
+	node, _ := os.Hostname()
+	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+	s := &scheduler{
+		store:       store,
+		logger:      logger.NewInstance().Field("component", "job_scheduler").Logger(),
+		parser:      parser,
+		cron:        cron.New(cron.WithParser(parser)),
+		node:        node,
+		historySize: defaultHistorySize,
+		jobs:        make(map[string]*registeredJob),
+		running:     make(map[string]*Run),
+		baseCtx:     context.Background(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("job name and run function are required")
+	}
+
+	if job.Timeout <= 0 {
+		job.Timeout = defaultTimeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return errors.Wrap(ErrJobAlreadyExists, job.Name)
+	}
+
+	schedule, err := s.parser.Parse(job.Schedule)
+	if err != nil {
+		return errors.Wrapf(err, "invalid schedule %q for job %s", job.Schedule, job.Name)
+	}
+
+	entryID := s.cron.Schedule(schedule, cron.FuncJob(func() {
+		s.execute(job, schedule)
+	}))
+
+	s.jobs[job.Name] = &registeredJob{job: job, entryID: entryID}
+
+	return nil
+}
+
+// Start blocks until ctx is cancelled, then waits for running jobs, whose contexts are cancelled too.
+// Scheduled runs are tracked by cron itself; wg only covers manual triggers.
+func (s *scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.baseCtx = ctx
+	s.mu.Unlock()
+
+	s.cron.Start()
+	s.logger.Info().Msgf("Job scheduler started with %d jobs", len(s.jobs))
+
+	<-ctx.Done()
+
+	<-s.cron.Stop().Done()
+	s.wg.Wait()
+
+	s.logger.Info().Msg("Job scheduler stopped")
+}
+
+func (s *scheduler) Jobs(ctx context.Context) []*JobInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]*JobInfo, 0, len(s.jobs))
+
+	for name, registered := range s.jobs {
+		info := &JobInfo{
+			Name:        name,
+			Description: registered.job.Description,
+			Schedule:    registered.job.Schedule,
+			Timeout:     registered.job.Timeout,
+		}
+
+		if next := s.cron.Entry(registered.entryID).Next; !next.IsZero() {
+			info.NextRunAt = &next
+		}
+
+		if run, ok := s.running[name]; ok {
+			snapshot := *run
+			info.Running = true
+			info.LastRun = &snapshot
+		} else if runs, err := s.store.FindJobRuns(ctx, name, 1); err == nil && len(runs) > 0 {
+			info.LastRun = runs[0]
+		}
+
+		res = append(res, info)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res
+}
+
+func (s *scheduler) Runs(ctx context.Context, name string, limit int) ([]*Run, error) {
+	s.mu.RLock()
+	_, ok := s.jobs[name]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, errors.Wrap(ErrJobNotFound, name)
+	}
+
+	if limit <= 0 || limit > s.historySize {
+		limit = s.historySize
+	}
+
+	return s.store.FindJobRuns(ctx, name, limit)
+}
+
+// Trigger starts the job in the background and returns its run record once the lock is held,
+// or ErrJobRunning if any replica is already running it.
+func (s *scheduler) Trigger(ctx context.Context, name string) (*Run, error) {
+	s.mu.RLock()
+	registered, ok := s.jobs[name]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, errors.Wrap(ErrJobNotFound, name)
+	}
+
+	run, release, err := s.begin(ctx, registered.job, TriggerManual)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := *run
+
+	s.wg.Go(func() {
+		s.finish(registered.job, run, release)
+	})
+
+	return &snapshot, nil
+}
+
+func (s *scheduler) execute(job Job, schedule cron.Schedule) {
+	claimed, err := s.claimSlot(context.Background(), job, schedule)
+	if err != nil {
+		s.logger.Error().Err(err).Msgf("Failed to claim scheduled run of job %s", job.Name)
+
+		return
+	}
+
+	if !claimed {
+		return
+	}
+
+	run, release, err := s.begin(context.Background(), job, TriggerSchedule)
+	if err != nil {
+		if !errors.Is(err, ErrJobRunning) {
+			s.logger.Error().Err(err).Msgf("Failed to start job %s", job.Name)
+		}
+
+		return
+	}
+
+	s.finish(job, run, release)
+}
+
+// claimSlot makes sure a scheduled activation runs on one replica only. The run lock taken by begin
+// is released as soon as the job finishes, so on its own it would let a replica whose timer fires a
+// little later run the same activation again. The slot claim is never released: it expires shortly
+// before the next activation instead.
+func (s *scheduler) claimSlot(ctx context.Context, job Job, schedule cron.Schedule) (bool, error) {
+	now := time.Now()
+	interval := schedule.Next(now).Sub(now)
+
+	acquired, err := s.store.AcquireLock(ctx, "job:"+job.Name+":slot", uuid.NewString(), interval-min(interval/2, slotMargin))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to acquire slot lock for job %s", job.Name)
+	}
+
+	return acquired, nil
+}
+
+func (s *scheduler) begin(ctx context.Context, job Job, trigger RunTrigger) (*Run, func(), error) {
+	lockKey := "job:" + job.Name
+	token := uuid.NewString()
+
+	acquired, err := s.store.AcquireLock(ctx, lockKey, token, job.Timeout+lockMargin)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to acquire lock for job %s", job.Name)
+	}
+
+	if !acquired {
+		return nil, nil, errors.Wrap(ErrJobRunning, job.Name)
+	}
+
+	run := &Run{
+		ID:        token,
+		Job:       job.Name,
+		Trigger:   trigger,
+		Status:    RunStatusRunning,
+		Node:      s.node,
+		StartedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.running[job.Name] = run
+	s.mu.Unlock()
+
+	release := func() {
+		if err := s.store.ReleaseLock(context.Background(), lockKey, token); err != nil {
+			s.logger.Error().Err(err).Msgf("Failed to release lock for job %s", job.Name)
+		}
+	}
+
+	return run, release, nil
+}
+
+func (s *scheduler) finish(job Job, run *Run, release func()) {
+	defer release()
+
+	s.mu.RLock()
+	baseCtx := s.baseCtx
+	s.mu.RUnlock()
+
+	tx := apm.DefaultTracer().StartTransaction("Job "+job.Name, "scheduled")
+	defer tx.End()
+
+	ctx, cancel := context.WithTimeout(apm.ContextWithTransaction(baseCtx, tx), job.Timeout)
+	defer cancel()
+
+	err := s.safeRun(ctx, job)
+
+	// The record in s.running is shared with Jobs readers, so the outcome goes on a copy.
+	result := *run
+	finishedAt := time.Now()
+	result.FinishedAt = &finishedAt
+	result.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	result.Status = RunStatusSucceeded
+	tx.Result = "success"
+
+	metrics.ObserveJob(metrics.JobScheduled, job.Name, finishedAt.Sub(run.StartedAt), err == nil)
+
+	if err != nil {
+		result.Status = RunStatusFailed
+		result.Error = err.Error()
+		tx.Result = "failure"
+
+		if apmErr := apm.CaptureError(ctx, err); apmErr != nil {
+			apmErr.Handled = true
+			apmErr.Send()
+		}
+
+		s.logger.Error().Err(err).Msgf("Job %s failed after %dms", job.Name, result.DurationMs)
+	} else {
+		s.logger.Info().Msgf("Job %s completed in %dms", job.Name, result.DurationMs)
+	}
+
+	s.mu.Lock()
+	delete(s.running, job.Name)
+	s.mu.Unlock()
+
+	if err := s.store.SaveJobRun(context.WithoutCancel(ctx), &result, s.historySize); err != nil {
+		s.logger.Error().Err(err).Msgf("Failed to save run history for job %s", job.Name)
+	}
+}
+
+func (s *scheduler) safeRun(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Newf("job panicked: %v", r)
+			s.logger.Error().Err(err).Msgf("Job %s panicked: %s", job.Name, debug.Stack())
+		}
+	}()
+
+	return job.Run(ctx)
+}
@@ -0,0 +1,443 @@
+package taskqueue
+
+import (
+	"context"
+	"encoding/json"
+	"goapptemp/pkg/logger"
+	"goapptemp/pkg/metrics"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
+	apm "go.elastic.co/apm/v2"
+)
+
+const (
+	defaultName              = "default"
+	defaultWorkers           = 4
+	defaultMaxAttempts       = 5
+	defaultVisibilityTimeout = time.Minute
+	defaultPollInterval      = time.Second
+	defaultBackoffBase       = 5 * time.Second
+	defaultBackoffMax        = 10 * time.Minute
+	defaultDrainTimeout      = 10 * time.Second
+	defaultDeadLetterSize    = 1000
+	settleMargin             = 5 * time.Second
+)
+
+var (
+	ErrUnknownTaskType   = errors.New("unknown task type")
+	ErrHandlerExists     = errors.New("task handler already registered")
+	ErrPermanent         = errors.New("permanent task failure")
+	errVisibilityExpired = errors.New("task visibility timeout expired")
+)
+
+type Task struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempt     int             `json:"attempt"`
+	MaxAttempts int             `json:"max_attempts"`
+	EnqueuedAt  time.Time       `json:"enqueued_at"`
+	LastError   string          `json:"last_error,omitempty"`
+}
+
+func (t *Task) Decode(v any) error {
+	if err := json.Unmarshal(t.Payload, v); err != nil {
+		return errors.Mark(errors.Wrapf(err, "failed to decode %s payload", t.Type), ErrPermanent)
+	}
+
+	return nil
+}
+
+// Handler processes one task. Returning an error wrapping ErrPermanent dead-letters the task
+// straight away; any other error schedules a retry until the task runs out of attempts.
+type Handler func(ctx context.Context, task *Task) error
+
+// Store persists encoded tasks. A claimed task stays invisible to other workers until the
+// visibility timeout passes, after which it is handed out again. The store counts those expired
+// claims per task until the task is acked, retried or dead-lettered, since the worker that lost it
+// never got to record the attempt.
+type Store interface {
+	PushTasks(ctx context.Context, queue string, tasks ...[]byte) error
+	ClaimTask(ctx context.Context, queue string, visibilityTimeout time.Duration) ([]byte, int, error)
+	AckTask(ctx context.Context, queue string, task []byte) error
+	RetryTask(ctx context.Context, queue string, task, next []byte, at time.Time) error
+	DeadLetterTask(ctx context.Context, queue string, task, dead []byte, limit int) error
+}
+
+var _ Queue = (*queue)(nil)
+
+type Queue interface {
+	Register(taskType string, handler Handler) error
+	Enqueue(ctx context.Context, taskType string, payload any) error
+	EnqueueBatch(ctx context.Context, taskType string, payloads ...any) error
+	Start(ctx context.Context)
+}
+
+type Option func(q *queue)
+
+func WithName(name string) Option {
+	return func(q *queue) {
+		if name != "" {
+			q.name = name
+		}
+	}
+}
+
+func WithWorkers(workers int) Option {
+	return func(q *queue) {
+		if workers > 0 {
+			q.workers = workers
+		}
+	}
+}
+
+func WithMaxAttempts(attempts int) Option {
+	return func(q *queue) {
+		if attempts > 0 {
+			q.maxAttempts = attempts
+		}
+	}
+}
+
+func WithVisibilityTimeout(timeout time.Duration) Option {
+	return func(q *queue) {
+		if timeout > 0 {
+			q.visibilityTimeout = timeout
+		}
+	}
+}
+
+func WithPollInterval(interval time.Duration) Option {
+	return func(q *queue) {
+		if interval > 0 {
+			q.pollInterval = interval
+		}
+	}
+}
+
+func WithBackoff(base, maxDelay time.Duration) Option {
+	return func(q *queue) {
+		if base > 0 {
+			q.backoffBase = base
+		}
+
+		if maxDelay > 0 {
+			q.backoffMax = maxDelay
+		}
+	}
+}
+
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(q *queue) {
+		if timeout > 0 {
+			q.drainTimeout = timeout
+		}
+	}
+}
+
+func WithDeadLetterSize(size int) Option {
+	return func(q *queue) {
+		if size > 0 {
+			q.deadLetterSize = size
+		}
+	}
+}
+
+type queue struct {
+	store             Store
+	logger            logger.Logger
+	name              string
+	workers           int
+	maxAttempts       int
+	visibilityTimeout time.Duration
+	pollInterval      time.Duration
+	backoffBase       time.Duration
+	backoffMax        time.Duration
+	drainTimeout      time.Duration
+	deadLetterSize    int
+	mu                sync.RWMutex
+	handlers          map[string]Handler
+}
+
+func NewQueue(store Store, logger logger.Logger, opts ...Option) *queue {
+	q := &queue{
+		store:             store,
+		name:              defaultName,
+		workers:           defaultWorkers,
+		maxAttempts:       defaultMaxAttempts,
+		visibilityTimeout: defaultVisibilityTimeout,
+		pollInterval:      defaultPollInterval,
+		backoffBase:       defaultBackoffBase,
+		backoffMax:        defaultBackoffMax,
+		drainTimeout:      defaultDrainTimeout,
+		deadLetterSize:    defaultDeadLetterSize,
+		handlers:          make(map[string]Handler),
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	q.logger = logger.NewInstance().Field("component", "task_queue").Field("queue", q.name).Logger()
+
+	return q
+}
+
+func (q *queue) Register(taskType string, handler Handler) error {
+	if taskType == "" || handler == nil {
+		return errors.New("task type and handler are required")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, exists := q.handlers[taskType]; exists {
+		return errors.Wrap(ErrHandlerExists, taskType)
+	}
+
+	q.handlers[taskType] = handler
+
+	return nil
+}
+
+func (q *queue) Enqueue(ctx context.Context, taskType string, payload any) error {
+	return q.EnqueueBatch(ctx, taskType, payload)
+}
+
+// EnqueueBatch queues one task per payload in a single write, so either all of them are queued or
+// none is.
+func (q *queue) EnqueueBatch(ctx context.Context, taskType string, payloads ...any) error {
+	q.mu.RLock()
+	_, ok := q.handlers[taskType]
+	q.mu.RUnlock()
+
+	if !ok {
+		return errors.Wrap(ErrUnknownTaskType, taskType)
+	}
+
+	if len(payloads) == 0 {
+		return nil
+	}
+
+	tasks := make([][]byte, 0, len(payloads))
+
+	for _, payload := range payloads {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode %s payload", taskType)
+		}
+
+		data, err := json.Marshal(&Task{
+			ID:          uuid.NewString(),
+			Type:        taskType,
+			Payload:     encoded,
+			MaxAttempts: q.maxAttempts,
+			EnqueuedAt:  time.Now(),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to encode task")
+		}
+
+		tasks = append(tasks, data)
+	}
+
+	if err := q.store.PushTasks(ctx, q.name, tasks...); err != nil {
+		return errors.Wrapf(err, "failed to enqueue %s", taskType)
+	}
+
+	return nil
+}
+
+// Start runs the worker pool until ctx is cancelled. Workers then keep claiming until the queue
+// is empty or drainTimeout passes, so work enqueued by in-flight requests is not left behind;
+// anything still queued after that stays in the store for the next start.
+func (q *queue) Start(ctx context.Context) {
+	stopCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	defer stop()
+
+	stopDrain := context.AfterFunc(ctx, func() {
+		q.logger.Info().Msgf("Task queue draining for up to %s", q.drainTimeout)
+		time.AfterFunc(q.drainTimeout, stop)
+	})
+	defer stopDrain()
+
+	q.logger.Info().Msgf("Task queue started with %d workers", q.workers)
+
+	var wg sync.WaitGroup
+
+	for range q.workers {
+		wg.Go(func() {
+			q.work(ctx, stopCtx)
+		})
+	}
+
+	wg.Wait()
+
+	q.logger.Info().Msg("Task queue stopped")
+}
+
+func (q *queue) work(ctx, stopCtx context.Context) {
+	for stopCtx.Err() == nil {
+		if q.processNext(stopCtx) {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(q.pollInterval):
+		}
+	}
+}
+
+// processNext reports whether a task was claimed, so the caller knows to skip the poll delay.
+func (q *queue) processNext(ctx context.Context) bool {
+	data, expirations, err := q.store.ClaimTask(ctx, q.name, q.visibilityTimeout)
+	if err != nil {
+		if ctx.Err() == nil {
+			q.logger.Error().Err(err).Msg("Failed to claim task")
+		}
+
+		return false
+	}
+
+	if data == nil {
+		return false
+	}
+
+	// Once claimed, the task runs to completion and is settled even if draining ends meanwhile.
+	ctx = context.WithoutCancel(ctx)
+
+	task := new(Task)
+	if err := json.Unmarshal(data, task); err != nil {
+		q.logger.Error().Err(err).Msg("Dropping undecodable task to dead-letter list")
+
+		if err := q.store.DeadLetterTask(ctx, q.name, data, data, q.deadLetterSize); err != nil {
+			q.logger.Error().Err(err).Msg("Failed to dead-letter undecodable task")
+		}
+
+		return true
+	}
+
+	// Every expired claim was an attempt that crashed or hung its worker.
+	task.Attempt += expirations + 1
+
+	if task.Attempt > task.MaxAttempts {
+		task.Attempt = task.MaxAttempts
+		q.fail(ctx, task, data, errors.Mark(errVisibilityExpired, ErrPermanent))
+
+		return true
+	}
+
+	tx := apm.DefaultTracer().StartTransaction("Task "+task.Type, "queue")
+	defer tx.End()
+
+	ctx = apm.ContextWithTransaction(ctx, tx)
+
+	startTime := time.Now()
+	err = q.run(ctx, task)
+
+	metrics.ObserveJob(metrics.JobTask, task.Type, time.Since(startTime), err == nil)
+
+	if err == nil {
+		tx.Result = "success"
+
+		if err := q.store.AckTask(ctx, q.name, data); err != nil {
+			q.logger.Error().Err(err).Msgf("Failed to ack task %s", task.ID)
+		}
+
+		return true
+	}
+
+	tx.Result = "failure"
+
+	if apmErr := apm.CaptureError(ctx, err); apmErr != nil {
+		apmErr.Handled = true
+		apmErr.Send()
+	}
+
+	q.fail(ctx, task, data, err)
+
+	return true
+}
+
+func (q *queue) run(ctx context.Context, task *Task) (err error) {
+	q.mu.RLock()
+	handler, ok := q.handlers[task.Type]
+	q.mu.RUnlock()
+
+	if !ok {
+		return errors.Mark(errors.Wrap(ErrUnknownTaskType, task.Type), ErrPermanent)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Newf("task panicked: %v", r)
+			q.logger.Error().Err(err).Msgf("Task %s panicked: %s", task.Type, debug.Stack())
+		}
+	}()
+
+	ctx, cancel := context.WithTimeoutCause(ctx, q.handlerTimeout(), errVisibilityExpired)
+	defer cancel()
+
+	return handler(ctx, task)
+}
+
+func (q *queue) fail(ctx context.Context, task *Task, data []byte, taskErr error) {
+	log := q.logger.NewInstance().
+		Field("task_id", task.ID).
+		Field("task_type", task.Type).
+		Field("attempt", task.Attempt).
+		Logger()
+
+	task.LastError = taskErr.Error()
+
+	next, err := json.Marshal(task)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to encode failed task")
+		return
+	}
+
+	if errors.Is(taskErr, ErrPermanent) || task.Attempt >= task.MaxAttempts {
+		log.Error().Err(taskErr).Msg("Task moved to dead-letter list")
+
+		if err := q.store.DeadLetterTask(ctx, q.name, data, next, q.deadLetterSize); err != nil {
+			log.Error().Err(err).Msg("Failed to dead-letter task")
+		}
+
+		return
+	}
+
+	delay := q.backoff(task.Attempt)
+	log.Warn().Err(taskErr).Msgf("Task failed, retrying in %s", delay)
+
+	if err := q.store.RetryTask(ctx, q.name, data, next, time.Now().Add(delay)); err != nil {
+		log.Error().Err(err).Msg("Failed to schedule task retry")
+	}
+}
+
+// handlerTimeout leaves part of the visibility timeout for acking, retrying or dead-lettering the
+// task, which only apply while the claim is still held.
+func (q *queue) handlerTimeout() time.Duration {
+	return q.visibilityTimeout - min(settleMargin, q.visibilityTimeout/4)
+}
+
+// backoff doubles the base delay per attempt, caps it and adds up to 20% jitter.
+func (q *queue) backoff(attempt int) time.Duration {
+	delay := q.backoffMax
+	if shift := attempt - 1; shift >= 0 && shift < 32 {
+		if d := q.backoffBase << shift; d > 0 && d < q.backoffMax {
+			delay = d
+		}
+	}
+
+	return delay + rand.N(delay/5+1)
+}
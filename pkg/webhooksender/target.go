@@ -0,0 +1,103 @@
+package webhooksender
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	ErrInsecureTarget = errors.New("webhook target must use https")
+	ErrBlockedTarget  = errors.New("webhook target resolves to a private, loopback or link-local address")
+)
+
+// blockedPrefixes are ranges outside the ones netip classifies that still reach shared or internal
+// infrastructure.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+}
+
+// ValidateTarget checks a subscription URL before it is stored: it must be https and its host must
+// not be, or resolve to, an internal address. The dialer checks again at send time, since DNS can
+// change after the subscription is saved.
+func ValidateTarget(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrap(err, "invalid webhook target")
+	}
+
+	if target.Scheme != "https" {
+		return ErrInsecureTarget
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(target.Hostname()), ".")
+	if host == "" {
+		return errors.New("webhook target has no host")
+	}
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrBlockedTarget
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if blockedAddr(addr) {
+			return ErrBlockedTarget
+		}
+
+		return nil
+	}
+
+	// A host that does not resolve yet is accepted; the dialer rejects it later if it turns internal.
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+
+	for _, addr := range addrs {
+		if blockedAddr(addr) {
+			return ErrBlockedTarget
+		}
+	}
+
+	return nil
+}
+
+func blockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// guardDial runs on every connection after DNS resolution, so a hostname that was public when the
+// subscription was saved cannot be pointed at an internal address later.
+func guardDial(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrap(err, "invalid dial address")
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil || blockedAddr(addr) {
+		return errors.Wrap(ErrBlockedTarget, host)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package webhooksender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	maxResponseBodySize = 2048
+)
+
+var _ WebhookSender = (*webhookSender)(nil)
+
+type WebhookSender interface {
+	Send(ctx context.Context, req *Request) (*Response, error)
+}
+
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID string
+	EventType  string
+	Body       []byte
+}
+
+type Response struct {
+	StatusCode int
+	Body       string
+}
+
+func (r *Response) OK() bool {
+	return r != nil && r.StatusCode >= http.StatusOK && r.StatusCode < http.StatusMultipleChoices
+}
+
+type webhookSender struct {
+	client    *http.Client
+	userAgent string
+}
+
+func NewWebhookSender(timeout time.Duration, userAgent string) *webhookSender {
+	dialer := &net.Dialer{Timeout: timeout, Control: guardDial}
+
+	return &webhookSender{
+		client: &http.Client{
+			Timeout: timeout,
+			// No proxy: a proxy would make the connection on our behalf and bypass guardDial.
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				ForceAttemptHTTP2:   true,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		userAgent: userAgent,
+	}
+}
+
+// Send posts the body and returns the receiver's response; non-2xx statuses are not treated as errors.
+func (s *webhookSender) Send(ctx context.Context, req *Request) (*Response, error) {
+	timestamp := time.Now().Unix()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build webhook request")
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", s.userAgent)
+	httpReq.Header.Set(HeaderID, req.DeliveryID)
+	httpReq.Header.Set(HeaderEvent, req.EventType)
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+
+	httpResp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send webhook request")
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseBodySize))
+	if err != nil {
+		return &Response{StatusCode: httpResp.StatusCode}, nil
+	}
+
+	return &Response{
+		StatusCode: httpResp.StatusCode,
+		Body:       string(body),
+	}, nil
+}
+
+// Sign returns "sha256=<hex>" of HMAC-SHA256 over "<timestamp>.<body>", which receivers recompute to verify.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}